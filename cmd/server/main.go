@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/config"
+	"github.com/AlexRijikov/go-petshop-api/internal/database"
+	"github.com/AlexRijikov/go-petshop-api/internal/keys"
+	"github.com/AlexRijikov/go-petshop-api/internal/notify"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/routes"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"github.com/AlexRijikov/go-petshop-api/internal/storage"
+)
+
+// main — точка входу: завантажує конфігурацію, підключає БД, реєструє маршрути
+// і запускає HTTP-сервер з коректною зупинкою (graceful shutdown) по SIGINT/SIGTERM.
+// `server migrate ...` замість сервера керує міграціями схеми (див. migrate.go).
+
+func main() {
+	// Міграціям потрібне лише підключення до БД — без JWT_SECRET/JWT_KEYS_DIR та інших налаштувань сервера
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(config.LoadDatabase(), os.Args[2:]); err != nil {
+			log.Fatalf("Помилка міграції: %v", err)
+		}
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Помилка конфігурації: %v", err)
+	}
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		log.Fatalf("Помилка підключення до БД: %v", err)
+	}
+
+	// Сервер не стартує на застарілій схемі — спочатку потрібно виконати `migrate up`
+
+	if err := database.CheckMigrations(context.Background(), db); err != nil {
+		log.Fatalf("Схема БД не актуальна (%v): виконайте `go run ./cmd/server migrate up`", err)
+	}
+
+	// Менеджер ключів JWT — спільний для підпису, перевірки та JWKS
+
+	km, err := keys.NewManager(cfg.JWT)
+	if err != nil {
+		log.Fatalf("Помилка ключів JWT: %v", err)
+	}
+
+	// Сховище завантажених файлів (зображення продуктів)
+
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatalf("Помилка сховища файлів: %v", err)
+	}
+
+	// Куди надсилати сповіщення про низькі залишки (ALERT_NOTIFIER)
+
+	notifier, err := notify.New(cfg.Alerts)
+	if err != nil {
+		log.Fatalf("Помилка сповіщень: %v", err)
+	}
+
+	// Персональні рекомендації: модель у пам'яті з каталогу та історії замовлень (оновлюється фоново нижче)
+
+	recommender := services.NewRecommender(repositories.NewRecommendationRepository(db), repositories.NewPetRepository(db), repositories.NewProductRepository(db))
+
+	// Створюємо gin.Engine і реєструємо всі маршрути API
+
+	r := gin.Default()
+	routes.RegisterRoutes(r, db, cfg, km, store, recommender)
+
+	srv := &http.Server{
+		Addr:         ":" + cfg.Server.Port,
+		Handler:      r,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+
+	// Контекст скасовується при отриманні SIGINT (Ctrl+C) або SIGTERM (зупинка контейнера)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Синхронізація ключів JWT зі спільним каталогом і ротація (JWT_ROTATION_INTERVAL), зупиняється разом із сервером
+
+	km.Start(ctx)
+
+	// Перевірка низьких залишків за розкладом (LOW_STOCK_CHECK_INTERVAL), зупиняється разом із сервером
+
+	services.NewLowStockMonitor(repositories.NewAlertRepository(db), notifier).Start(ctx, cfg.Alerts.LowStockInterval)
+
+	// Оновлення моделі рекомендацій за розкладом (RECOMMENDATIONS_REFRESH_INTERVAL), зупиняється разом із сервером
+
+	recommender.Start(ctx, cfg.Recommendations.RefreshInterval)
+
+	go func() {
+		log.Printf("Сервер запущено на порту %s", cfg.Server.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Помилка сервера: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Println("Отримано сигнал зупинки, завершуємо активні запити...")
+
+	// Чекаємо завершення активних запитів не довше за ShutdownTimeout
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Помилка при зупинці сервера: %v", err)
+	}
+
+	// Закриваємо пул з'єднань з БД після того, як усі запити завершено
+
+	if err := database.Close(db); err != nil {
+		log.Printf("Помилка при закритті з'єднання з БД: %v", err)
+	}
+
+	log.Println("Сервер зупинено")
+}
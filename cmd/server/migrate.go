@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/config"
+	"github.com/AlexRijikov/go-petshop-api/internal/database"
+)
+
+// runMigrate виконує підкоманду `migrate`:
+//
+//	migrate up        — застосувати всі нові міграції
+//	migrate down [n]  — відкотити n останніх міграцій (за замовчуванням 1)
+//	migrate status    — показати застосовані та очікувані міграції
+
+func runMigrate(cfg config.DatabaseConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("використання: migrate up | down [n] | status")
+	}
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	m, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, mig := range applied {
+			fmt.Printf("застосовано %04d_%s\n", mig.Version, mig.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("нових міграцій немає")
+		}
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("некоректна кількість кроків %q", args[1])
+			}
+			steps = n
+		}
+		reverted, err := m.Down(ctx, steps)
+		for _, mig := range reverted {
+			fmt.Printf("відкочено %04d_%s\n", mig.Version, mig.Name)
+		}
+		return err
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range statuses {
+			state := "очікує"
+			if st.AppliedAt != nil {
+				state = "застосовано " + st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", st.Version, st.Name, state)
+		}
+		return nil
+	}
+	return fmt.Errorf("невідома команда migrate %q (up, down, status)", args[0])
+}
@@ -0,0 +1,81 @@
+package allocation
+
+// Вибір локації для позицій замовлення. Спершу шукається одна локація, яка має товар для всього
+// замовлення (одне відправлення); якщо такої немає — кожна позиція береться з першої за пріоритетом
+// локації, де її достатньо. Позиція не ділиться між локаціями.
+
+// Line — позиція замовлення: продукт і кількість
+
+type Line struct {
+	ProductID uint
+	Quantity  int
+}
+
+// Stock — залишок продукту в локації
+
+type Stock struct {
+	WarehouseID uint
+	ProductID   uint
+	Quantity    int
+}
+
+// Allocate повертає локацію для кожної позиції (у тому ж порядку, що й lines).
+// warehouses — ID доступних локацій за пріоритетом; залишки інших локацій ігноруються.
+// false — хоча б одну позицію неможливо зарезервувати в жодній локації.
+
+func Allocate(lines []Line, warehouses []uint, stock []Stock) ([]uint, bool) {
+	available := make(map[uint]map[uint]int, len(warehouses))
+	for _, id := range warehouses {
+		available[id] = map[uint]int{}
+	}
+	for _, s := range stock {
+		if levels, ok := available[s.WarehouseID]; ok {
+			levels[s.ProductID] += s.Quantity
+		}
+	}
+
+	// Одна локація для всього замовлення (дублікати продукту сумуються)
+
+	need := make(map[uint]int, len(lines))
+	for _, l := range lines {
+		need[l.ProductID] += l.Quantity
+	}
+	for _, id := range warehouses {
+		if covers(available[id], need) {
+			out := make([]uint, len(lines))
+			for i := range out {
+				out[i] = id
+			}
+			return out, true
+		}
+	}
+
+	// Інакше — кожна позиція окремо з першої локації, де її достатньо
+
+	out := make([]uint, len(lines))
+	for i, l := range lines {
+		found := false
+		for _, id := range warehouses {
+			if available[id][l.ProductID] >= l.Quantity {
+				available[id][l.ProductID] -= l.Quantity
+				out[i], found = id, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return out, true
+}
+
+// covers перевіряє, що в локації є весь потрібний товар
+
+func covers(levels map[uint]int, need map[uint]int) bool {
+	for productID, qty := range need {
+		if levels[productID] < qty {
+			return false
+		}
+	}
+	return true
+}
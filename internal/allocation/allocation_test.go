@@ -0,0 +1,45 @@
+package allocation_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/allocation"
+)
+
+// Тест: замовлення цілком з однієї локації, навіть якщо вона не перша за пріоритетом
+
+func TestAllocateSingleWarehouse(t *testing.T) {
+	stock := []allocation.Stock{
+		{WarehouseID: 1, ProductID: 10, Quantity: 5},
+		{WarehouseID: 2, ProductID: 10, Quantity: 2},
+		{WarehouseID: 2, ProductID: 20, Quantity: 1},
+	}
+	got, ok := allocation.Allocate([]allocation.Line{{ProductID: 10, Quantity: 2}, {ProductID: 20, Quantity: 1}}, []uint{1, 2}, stock)
+	assert.True(t, ok)
+	assert.Equal(t, []uint{2, 2}, got)
+
+	got, ok = allocation.Allocate([]allocation.Line{{ProductID: 10, Quantity: 2}}, []uint{1, 2}, stock)
+	assert.True(t, ok)
+	assert.Equal(t, []uint{1}, got, "перша за пріоритетом")
+}
+
+// Тест: позиції з різних локацій, дублікати продукту і нестача
+
+func TestAllocateSplit(t *testing.T) {
+	stock := []allocation.Stock{
+		{WarehouseID: 1, ProductID: 10, Quantity: 3},
+		{WarehouseID: 2, ProductID: 20, Quantity: 4},
+		{WarehouseID: 3, ProductID: 10, Quantity: 9}, // неактивна локація — немає в списку
+	}
+	got, ok := allocation.Allocate([]allocation.Line{{ProductID: 10, Quantity: 3}, {ProductID: 20, Quantity: 4}}, []uint{1, 2}, stock)
+	assert.True(t, ok)
+	assert.Equal(t, []uint{1, 2}, got)
+
+	_, ok = allocation.Allocate([]allocation.Line{{ProductID: 10, Quantity: 2}, {ProductID: 10, Quantity: 2}}, []uint{1, 2}, stock)
+	assert.False(t, ok, "разом 4 одиниці, а в активних локаціях лише 3")
+
+	_, ok = allocation.Allocate([]allocation.Line{{ProductID: 30, Quantity: 1}}, []uint{1, 2}, stock)
+	assert.False(t, ok)
+}
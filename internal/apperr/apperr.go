@@ -0,0 +1,119 @@
+package apperr
+
+import "net/http"
+
+// Типізовані помилки домену. Сервіси повертають *Error з видом (Kind) і стабільним кодом,
+// а middleware.ErrorHandler перетворює їх на відповідь application/problem+json (RFC 7807).
+// Будь-яка інша помилка вважається внутрішньою: клієнт отримує 500 без деталей (без SQL тощо).
+
+// Kind — вид помилки, визначає HTTP-статус
+
+type Kind uint8
+
+const (
+	KindInternal             Kind = iota // непередбачена помилка (500)
+	KindValidation                       // некоректні вхідні дані (400)
+	KindUnauthorized                     // немає або недійсні облікові дані (401)
+	KindForbidden                        // недостатньо прав (403)
+	KindNotFound                         // ресурс не знайдено (404)
+	KindConflict                         // конфлікт зі станом ресурсу (409)
+	KindUnsupportedMediaType             // непідтримуваний Content-Type запиту (415)
+	KindPreconditionFailed               // умова запиту (If-Match) не виконана (412)
+	KindPayloadTooLarge                  // тіло запиту перевищує ліміт розміру (413)
+)
+
+// Status повертає HTTP-статус для виду помилки
+
+func (k Kind) Status() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindUnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
+	case KindPreconditionFailed:
+		return http.StatusPreconditionFailed
+	case KindPayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusInternalServerError
+}
+
+// Error — помилка домену зі стабільним кодом для клієнтів
+
+type Error struct {
+	Kind    Kind
+	Code    string // машиночитний код (product_not_found) — частина контракту API, не змінюється
+	Message string // короткий опис, однаковий для всіх випадків з цим кодом (title)
+	Detail  string // подробиці конкретного випадку (detail), необов'язково
+	base    *Error // sentinel, з якого створено копію через WithDetail (для errors.Is)
+}
+
+// Error повертає опис помилки разом із подробицями
+
+func (e *Error) Error() string {
+	if e.Detail != "" {
+		return e.Message + ": " + e.Detail
+	}
+	return e.Message
+}
+
+// Unwrap дозволяє errors.Is(err, ErrX) для копій, створених через WithDetail
+
+func (e *Error) Unwrap() error {
+	if e.base == nil {
+		return nil
+	}
+	return e.base
+}
+
+// WithDetail повертає копію помилки з подробицями конкретного випадку (sentinel не змінюється)
+
+func (e *Error) WithDetail(detail string) *Error {
+	return &Error{Kind: e.Kind, Code: e.Code, Message: e.Message, Detail: detail, base: e}
+}
+
+// New створює помилку домену
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Validation — некоректні вхідні дані (400)
+
+func Validation(code, message string) *Error { return New(KindValidation, code, message) }
+
+// Unauthorized — потрібна автентифікація (401)
+
+func Unauthorized(code, message string) *Error { return New(KindUnauthorized, code, message) }
+
+// Forbidden — недостатньо прав (403)
+
+func Forbidden(code, message string) *Error { return New(KindForbidden, code, message) }
+
+// NotFound — ресурс не знайдено (404)
+
+func NotFound(code, message string) *Error { return New(KindNotFound, code, message) }
+
+// Conflict — конфлікт зі станом ресурсу (409)
+
+func Conflict(code, message string) *Error { return New(KindConflict, code, message) }
+
+// PreconditionFailed — умова запиту не виконана, наприклад застарілий ETag в If-Match (412)
+
+func PreconditionFailed(code, message string) *Error {
+	return New(KindPreconditionFailed, code, message)
+}
+
+// PayloadTooLarge — тіло запиту (наприклад, завантажений файл) більше за дозволене (413)
+
+func PayloadTooLarge(code, message string) *Error {
+	return New(KindPayloadTooLarge, code, message)
+}
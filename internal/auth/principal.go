@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+)
+
+// ErrUnauthenticated — запит без автентифікованого користувача (Principal відсутній)
+
+var ErrUnauthenticated = apperr.Unauthorized("unauthenticated", "user not authorized")
+
+// Principal — автентифікований користувач поточного запиту, побудований AuthMiddleware з JWT claims.
+// Доступний і через gin.Context (у handlers), і через context.Context (у services/repositories).
+
+type Principal struct {
+	UserID    uint      // ID користувача (claim "user_id")
+	Role      string    // роль користувача: user, admin (claim "role")
+	TokenID   string    // унікальний ідентифікатор токена (claim "jti") — потрібен для logout
+	Scopes    []string  // дозволи токена (claim "scope", через пробіл)
+	ExpiresAt time.Time // коли токен перестає бути дійсним (claim "exp")
+}
+
+// HasRole перевіряє, чи має користувач одну з вказаних ролей
+
+func (p *Principal) HasRole(roles ...string) bool {
+	for _, r := range roles {
+		if p.Role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope перевіряє, чи містить токен вказаний дозвіл
+
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// principalKey — ключ для зберігання Principal у gin.Context та context.Context
+
+type principalKey struct{}
+
+// ginPrincipalKey — ключ у gin.Context (gin зберігає значення за рядковим ключем)
+
+const ginPrincipalKey = "auth.principal"
+
+// WithPrincipal повертає новий context.Context з Principal
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext дістає Principal з context.Context (false — запит не автентифікований)
+
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// SetPrincipal зберігає Principal і в gin.Context, і в контексті HTTP-запиту
+
+func SetPrincipal(c *gin.Context, p *Principal) {
+	c.Set(ginPrincipalKey, p)
+	c.Request = c.Request.WithContext(WithPrincipal(c.Request.Context(), p))
+}
+
+// PrincipalFrom дістає Principal з gin.Context (false — запит не пройшов AuthMiddleware)
+
+func PrincipalFrom(c *gin.Context) (*Principal, bool) {
+	v, ok := c.Get(ginPrincipalKey)
+	if !ok {
+		return nil, false
+	}
+	p, ok := v.(*Principal)
+	return p, ok && p != nil
+}
+
+// UserID повертає ID автентифікованого користувача або 0, якщо Principal відсутній
+
+func UserID(c *gin.Context) uint {
+	if p, ok := PrincipalFrom(c); ok {
+		return p.UserID
+	}
+	return 0
+}
@@ -0,0 +1,273 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Config містить усі налаштування застосунку, зчитані з .env або змінних середовища.
+// Значення за замовчуванням підходять для локальної розробки.
+
+type Config struct {
+	Server          ServerConfig
+	Database        DatabaseConfig
+	JWT             JWTConfig
+	Storage         StorageConfig
+	Alerts          AlertsConfig
+	Recommendations RecommendationsConfig
+}
+
+// ServerConfig — налаштування HTTP-сервера (порт і таймаути)
+
+type ServerConfig struct {
+	Port            string        // порт, на якому слухає сервер (SERVER_PORT)
+	ReadTimeout     time.Duration // максимальний час читання запиту (SERVER_READ_TIMEOUT)
+	WriteTimeout    time.Duration // максимальний час запису відповіді (SERVER_WRITE_TIMEOUT)
+	IdleTimeout     time.Duration // час життя keep-alive з'єднання (SERVER_IDLE_TIMEOUT)
+	ShutdownTimeout time.Duration // скільки чекаємо завершення активних запитів при зупинці (SERVER_SHUTDOWN_TIMEOUT)
+}
+
+// DatabaseConfig — частини DSN для підключення до PostgreSQL
+
+type DatabaseConfig struct {
+	Host     string // DB_HOST
+	Port     string // DB_PORT
+	User     string // DB_USER
+	Password string // DB_PASSWORD
+	Name     string // DB_NAME
+	SSLMode  string // DB_SSLMODE
+}
+
+// JWTConfig — налаштування підпису JWT токенів
+
+type JWTConfig struct {
+	Algorithm        string        // алгоритм підпису: HS256, RS256 або EdDSA (JWT_ALG)
+	Secret           string        // секретний ключ для HS256 (JWT_SECRET)
+	KeysDir          string        // каталог з PEM приватними ключами, ім'я файлу = kid (JWT_KEYS_DIR)
+	SigningKeyID     string        // kid ключа для підпису; за замовчуванням — останній за алфавітом (JWT_SIGNING_KID)
+	RotationInterval time.Duration // як часто додавати новий ключ у JWT_KEYS_DIR; 0 — без ротації (JWT_ROTATION_INTERVAL)
+	KeyPublishDelay  time.Duration // скільки новий ключ лише публікується в JWKS, перш ніж ним підписувати (JWT_KEY_PUBLISH_DELAY)
+	AccessTTL        time.Duration // час життя access токена (JWT_ACCESS_TTL)
+	RefreshTTL       time.Duration // час життя refresh токена (JWT_REFRESH_TTL)
+}
+
+// StorageConfig — сховище завантажених файлів (зображення продуктів)
+
+type StorageConfig struct {
+	Driver        string // тип сховища: local (STORAGE_DRIVER)
+	LocalDir      string // каталог для файлів драйвера local (STORAGE_LOCAL_DIR)
+	PublicURL     string // префікс URL файлів; для local сервер сам роздає каталог за цим шляхом (STORAGE_PUBLIC_URL)
+	MaxUploadSize int64  // максимальний розмір одного файлу в байтах (STORAGE_MAX_UPLOAD_SIZE)
+}
+
+// RecommendationsConfig — модель персональних рекомендацій у пам'яті
+
+type RecommendationsConfig struct {
+	RefreshInterval time.Duration // як часто перебудовувати модель, > 0 (RECOMMENDATIONS_REFRESH_INTERVAL)
+}
+
+// AlertsConfig — фонова перевірка низьких залишків і доставка сповіщень
+
+type AlertsConfig struct {
+	LowStockInterval time.Duration // як часто перевіряти залишки; 0 — перевірку вимкнено (LOW_STOCK_CHECK_INTERVAL)
+	Notifier         string        // куди надсилати сповіщення: log або webhook (ALERT_NOTIFIER)
+	WebhookURL       string        // адреса для POST-запитів webhook (ALERT_WEBHOOK_URL)
+	WebhookSecret    string        // секрет підпису тіла HMAC-SHA256; порожній — без підпису (ALERT_WEBHOOK_SECRET)
+	WebhookTimeout   time.Duration // таймаут одного запиту webhook (ALERT_WEBHOOK_TIMEOUT)
+}
+
+// DSN формує рядок підключення до PostgreSQL з частин конфігурації
+
+func (c DatabaseConfig) DSN() string {
+	return fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		c.Host, c.User, c.Password, c.Name, c.Port, c.SSLMode,
+	)
+}
+
+// Load завантажує .env (якщо є) і зчитує конфігурацію зі змінних середовища.
+// Повертає помилку, якщо значення має некоректний формат або не задано ключів для підпису JWT.
+
+func Load() (*Config, error) {
+	loadDotEnv()
+
+	cfg := &Config{
+		Server: ServerConfig{
+			Port: getEnv("SERVER_PORT", "8080"),
+		},
+		Database: databaseFromEnv(),
+		JWT: JWTConfig{
+			Algorithm:    getEnv("JWT_ALG", "HS256"),
+			Secret:       os.Getenv("JWT_SECRET"),
+			KeysDir:      os.Getenv("JWT_KEYS_DIR"),
+			SigningKeyID: os.Getenv("JWT_SIGNING_KID"),
+		},
+		Storage: StorageConfig{
+			Driver:    getEnv("STORAGE_DRIVER", "local"),
+			LocalDir:  getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+			PublicURL: getEnv("STORAGE_PUBLIC_URL", "/media"),
+		},
+		Alerts: AlertsConfig{
+			Notifier:      getEnv("ALERT_NOTIFIER", "log"),
+			WebhookURL:    os.Getenv("ALERT_WEBHOOK_URL"),
+			WebhookSecret: os.Getenv("ALERT_WEBHOOK_SECRET"),
+		},
+	}
+
+	// Таймаути сервера і час життя токенів (формат time.ParseDuration: 5s, 1m тощо)
+
+	var err error
+	if cfg.Server.ReadTimeout, err = getDuration("SERVER_READ_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.Server.WriteTimeout, err = getDuration("SERVER_WRITE_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.Server.IdleTimeout, err = getDuration("SERVER_IDLE_TIMEOUT", 60*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.Server.ShutdownTimeout, err = getDuration("SERVER_SHUTDOWN_TIMEOUT", 15*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.JWT.AccessTTL, err = getDuration("JWT_ACCESS_TTL", 15*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.JWT.RefreshTTL, err = getDuration("JWT_REFRESH_TTL", 30*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.JWT.RotationInterval, err = getDuration("JWT_ROTATION_INTERVAL", 0); err != nil {
+		return nil, err
+	}
+	if cfg.JWT.KeyPublishDelay, err = getDuration("JWT_KEY_PUBLISH_DELAY", 5*time.Minute); err != nil {
+		return nil, err
+	}
+
+	if cfg.Alerts.LowStockInterval, err = getDuration("LOW_STOCK_CHECK_INTERVAL", 5*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.Alerts.WebhookTimeout, err = getDuration("ALERT_WEBHOOK_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.Recommendations.RefreshInterval, err = getDuration("RECOMMENDATIONS_REFRESH_INTERVAL", 15*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.Recommendations.RefreshInterval <= 0 {
+		return nil, fmt.Errorf("RECOMMENDATIONS_REFRESH_INTERVAL має бути > 0")
+	}
+
+	if cfg.Storage.MaxUploadSize, err = getInt64("STORAGE_MAX_UPLOAD_SIZE", 5<<20); err != nil {
+		return nil, err
+	}
+	if cfg.Storage.MaxUploadSize <= 0 {
+		return nil, fmt.Errorf("STORAGE_MAX_UPLOAD_SIZE має бути > 0")
+	}
+	if cfg.Storage.Driver != "local" {
+		return nil, fmt.Errorf("непідтримуваний STORAGE_DRIVER %q (local)", cfg.Storage.Driver)
+	}
+
+	switch cfg.Alerts.Notifier {
+	case "log":
+	case "webhook":
+		if u, err := url.Parse(cfg.Alerts.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("ALERT_WEBHOOK_URL має бути абсолютною http(s) адресою для ALERT_NOTIFIER=webhook")
+		}
+	default:
+		return nil, fmt.Errorf("непідтримуваний ALERT_NOTIFIER %q (log, webhook)", cfg.Alerts.Notifier)
+	}
+
+	if _, err := strconv.Atoi(cfg.Server.Port); err != nil {
+		return nil, fmt.Errorf("некоректний SERVER_PORT %q: %w", cfg.Server.Port, err)
+	}
+	switch cfg.JWT.Algorithm {
+	case "HS256", "RS256", "EdDSA":
+	default:
+		return nil, fmt.Errorf("непідтримуваний JWT_ALG %q (HS256, RS256, EdDSA)", cfg.JWT.Algorithm)
+	}
+	if cfg.JWT.Algorithm == "HS256" && cfg.JWT.KeysDir == "" && cfg.JWT.Secret == "" {
+		return nil, fmt.Errorf("JWT_SECRET не задано")
+	}
+	if cfg.JWT.RotationInterval > 0 {
+		switch {
+		case cfg.JWT.KeysDir == "":
+			return nil, fmt.Errorf("JWT_ROTATION_INTERVAL потребує спільного каталогу ключів JWT_KEYS_DIR")
+		case cfg.JWT.SigningKeyID != "":
+			return nil, fmt.Errorf("JWT_SIGNING_KID не можна поєднувати з JWT_ROTATION_INTERVAL")
+		case cfg.JWT.RotationInterval <= cfg.JWT.KeyPublishDelay:
+			return nil, fmt.Errorf("JWT_ROTATION_INTERVAL має бути більшим за JWT_KEY_PUBLISH_DELAY")
+		}
+	}
+
+	return cfg, nil
+}
+
+// LoadDatabase завантажує .env (якщо є) і зчитує лише підключення до БД — для `migrate`,
+// якому не потрібні ключі JWT, сховище чи сповіщення (деплой-задачі не мають знати секрети підпису)
+
+func LoadDatabase() DatabaseConfig {
+	loadDotEnv()
+	return databaseFromEnv()
+}
+
+// loadDotEnv підвантажує змінні з .env, якщо файл є
+
+func loadDotEnv() {
+	if err := godotenv.Load(); err != nil {
+		log.Println(" Не вдалося завантажити .env, використовую системні змінні")
+	}
+}
+
+// databaseFromEnv зчитує параметри підключення до PostgreSQL
+
+func databaseFromEnv() DatabaseConfig {
+	return DatabaseConfig{
+		Host:     getEnv("DB_HOST", "localhost"),
+		Port:     getEnv("DB_PORT", "5432"),
+		User:     getEnv("DB_USER", "postgres"),
+		Password: os.Getenv("DB_PASSWORD"),
+		Name:     getEnv("DB_NAME", "petshop"),
+		SSLMode:  getEnv("DB_SSLMODE", "disable"),
+	}
+}
+
+// getEnv повертає значення змінної середовища або значення за замовчуванням
+
+func getEnv(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+// getDuration читає тривалість зі змінної середовища (наприклад, "15s")
+
+func getDuration(key string, def time.Duration) (time.Duration, error) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("некоректне значення %s %q: %w", key, v, err)
+	}
+	return d, nil
+}
+
+// getInt64 читає ціле число зі змінної середовища (наприклад, розмір у байтах)
+
+func getInt64(key string, def int64) (int64, error) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("некоректне значення %s %q: %w", key, v, err)
+	}
+	return n, nil
+}
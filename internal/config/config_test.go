@@ -0,0 +1,29 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/config"
+)
+
+// Тест: підключення до БД для migrate читається без ключів JWT, а повна конфігурація сервера без них не завантажується
+
+func TestLoadDatabaseWithoutJWT(t *testing.T) {
+	t.Setenv("JWT_ALG", "HS256")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_KEYS_DIR", "")
+	t.Setenv("DB_HOST", "db.internal")
+	t.Setenv("DB_NAME", "shop")
+
+	db := config.LoadDatabase()
+	assert.Equal(t, "db.internal", db.Host)
+	assert.Equal(t, "shop", db.Name)
+	assert.Equal(t, "5432", db.Port)
+
+	_, err := config.Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_SECRET")
+}
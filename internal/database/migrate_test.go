@@ -0,0 +1,61 @@
+package database_test
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/database"
+)
+
+// Тест: міграції впорядковані за версією, up і down збираються в одну міграцію
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0002_add_stock.up.sql":     {Data: []byte("ALTER TABLE t ADD stock int;")},
+		"m/0001_create_t.up.sql":      {Data: []byte("CREATE TABLE t (id int);")},
+		"m/0001_create_t.down.sql":    {Data: []byte("DROP TABLE t;")},
+		"m/0002_add_stock.down.sql":   {Data: []byte("ALTER TABLE t DROP stock;")},
+		"m/0003_backfill_only.up.sql": {Data: []byte("UPDATE t SET stock = 0;")},
+	}
+
+	migrations, err := database.LoadMigrations(fsys, "m")
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "create_t", migrations[0].Name)
+	assert.Equal(t, "DROP TABLE t;", migrations[0].Down)
+	assert.Equal(t, int64(2), migrations[1].Version)
+	assert.Empty(t, migrations[2].Down) // незворотна міграція
+}
+
+// Тест: некоректна назва файлу або міграція без .up.sql — помилка
+
+func TestLoadMigrationsInvalid(t *testing.T) {
+	_, err := database.LoadMigrations(fstest.MapFS{"m/create.sql": {Data: []byte("SELECT 1")}}, "m")
+	assert.Error(t, err)
+
+	_, err = database.LoadMigrations(fstest.MapFS{"m/0001_init.down.sql": {Data: []byte("SELECT 1")}}, "m")
+	assert.Error(t, err)
+
+	_, err = database.LoadMigrations(fstest.MapFS{
+		"m/0001_init.up.sql":  {Data: []byte("SELECT 1")},
+		"m/0001_other.up.sql": {Data: []byte("SELECT 1")},
+	}, "m")
+	assert.Error(t, err)
+}
+
+// Тест: вбудований набір міграцій коректний і кожну міграцію можна відкотити
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := database.LoadMigrations(os.DirFS("."), "migrations")
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, m := range migrations {
+		assert.Equal(t, int64(i+1), m.Version, "версії мають йти без пропусків")
+		assert.NotEmpty(t, m.Down, "міграція %d_%s без down", m.Version, m.Name)
+	}
+}
@@ -0,0 +1,35 @@
+-- Базова схема каталогу і користувачів.
+-- IF NOT EXISTS дозволяє прийняти бази, створені раніше через GORM AutoMigrate.
+
+CREATE TABLE IF NOT EXISTS products (
+    id          bigserial PRIMARY KEY,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    name        varchar(255) NOT NULL,
+    description text,
+    price_cents bigint NOT NULL,
+    stock       bigint NOT NULL DEFAULT 0,
+    sku         varchar(100),
+    image_url   varchar(255),
+    category    varchar(100),
+    metadata    json
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at ON products (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku ON products (sku);
+
+CREATE TABLE IF NOT EXISTS users (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    username   varchar(255) NOT NULL,
+    email      varchar(255) NOT NULL,
+    password   varchar(255) NOT NULL,
+    role       varchar(50) NOT NULL DEFAULT 'user'
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
@@ -0,0 +1,23 @@
+-- Refresh-токени (зберігається лише SHA-256 хеш) і відкликані access-токени (logout)
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id          bigserial PRIMARY KEY,
+    created_at  timestamptz,
+    user_id     bigint NOT NULL,
+    token_hash  varchar(64) NOT NULL,
+    expires_at  timestamptz NOT NULL,
+    revoked_at  timestamptz,
+    replaced_by bigint
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires_at ON refresh_tokens (expires_at);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti        varchar(64) PRIMARY KEY,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
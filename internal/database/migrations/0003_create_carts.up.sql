@@ -0,0 +1,23 @@
+-- Кошик покупця: один кошик на користувача, один рядок на продукт
+
+CREATE TABLE IF NOT EXISTS carts (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    user_id    bigint NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_carts_user_id ON carts (user_id);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    cart_id    bigint NOT NULL,
+    product_id bigint NOT NULL,
+    quantity   bigint NOT NULL,
+    CONSTRAINT fk_carts_items FOREIGN KEY (cart_id) REFERENCES carts (id) ON DELETE CASCADE,
+    CONSTRAINT fk_cart_items_product FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_product ON cart_items (cart_id, product_id);
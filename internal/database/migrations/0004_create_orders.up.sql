@@ -0,0 +1,41 @@
+-- Замовлення, позиції зі знімком цін та історія змін статусу
+
+CREATE TABLE IF NOT EXISTS orders (
+    id          bigserial PRIMARY KEY,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    user_id     bigint NOT NULL,
+    status      varchar(20) NOT NULL DEFAULT 'pending',
+    total_cents bigint NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders (user_id);
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders (status);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id               bigserial PRIMARY KEY,
+    order_id         bigint NOT NULL,
+    product_id       bigint NOT NULL,
+    product_name     varchar(255) NOT NULL,
+    sku              varchar(100),
+    unit_price_cents bigint NOT NULL,
+    quantity         bigint NOT NULL,
+    subtotal_cents   bigint NOT NULL,
+    CONSTRAINT fk_orders_items FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items (order_id);
+CREATE INDEX IF NOT EXISTS idx_order_items_product_id ON order_items (product_id);
+
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id          bigserial PRIMARY KEY,
+    created_at  timestamptz,
+    order_id    bigint NOT NULL,
+    from_status varchar(20),
+    to_status   varchar(20) NOT NULL,
+    changed_by  bigint NOT NULL,
+    note        varchar(500),
+    CONSTRAINT fk_orders_history FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history (order_id);
@@ -0,0 +1,4 @@
+-- GIN індекс для повнотекстового пошуку продуктів (вираз збігається з repositories.productSearchVector)
+
+CREATE INDEX IF NOT EXISTS idx_products_search ON products
+    USING GIN (to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(description, '')));
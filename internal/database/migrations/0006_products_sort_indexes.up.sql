@@ -0,0 +1,5 @@
+-- Індекси для keyset-пагінації каталогу: (колонка сортування, id) лише для не видалених продуктів
+
+CREATE INDEX IF NOT EXISTS idx_products_price_id ON products (price_cents, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_products_name_id ON products (name, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_products_created_at_id ON products (created_at, id) WHERE deleted_at IS NULL;
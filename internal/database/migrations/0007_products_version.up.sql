@@ -0,0 +1,3 @@
+-- Версія продукту для оптимістичного блокування (ETag / If-Match)
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
@@ -0,0 +1,25 @@
+-- Ієрархічні категорії каталогу і зв'язок продуктів з категоріями (many-to-many)
+
+CREATE TABLE IF NOT EXISTS categories (
+    id          bigserial PRIMARY KEY,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    name        varchar(100) NOT NULL,
+    slug        varchar(100) NOT NULL,
+    description text,
+    parent_id   bigint,
+    CONSTRAINT fk_categories_children FOREIGN KEY (parent_id) REFERENCES categories (id) ON DELETE RESTRICT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug ON categories (slug);
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+CREATE TABLE IF NOT EXISTS product_categories (
+    product_id  bigint NOT NULL,
+    category_id bigint NOT NULL,
+    PRIMARY KEY (product_id, category_id),
+    CONSTRAINT fk_product_categories_product FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE,
+    CONSTRAINT fk_product_categories_category FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_categories_category_id ON product_categories (category_id);
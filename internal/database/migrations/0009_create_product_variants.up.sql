@@ -0,0 +1,18 @@
+-- Варіанти продукту: власний артикул, ціна, залишок і атрибути (розмір, вага, смак)
+
+CREATE TABLE IF NOT EXISTS product_variants (
+    id          bigserial PRIMARY KEY,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    product_id  bigint NOT NULL,
+    sku         varchar(100) NOT NULL,
+    options     jsonb NOT NULL DEFAULT '{}',
+    price_cents bigint NOT NULL,
+    stock       bigint NOT NULL DEFAULT 0,
+    CONSTRAINT fk_products_variants FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE,
+    CONSTRAINT chk_product_variants_price CHECK (price_cents > 0),
+    CONSTRAINT chk_product_variants_stock CHECK (stock >= 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_sku ON product_variants (sku);
+CREATE INDEX IF NOT EXISTS idx_product_variants_product_id ON product_variants (product_id);
@@ -0,0 +1,12 @@
+-- Типізовані атрибути продуктів: схема в категорії, значення в jsonb продукту з GIN індексом.
+-- Наявні об'єкти з metadata переносяться в attributes як є (схема перевіряється при наступній зміні продукту).
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS attributes jsonb NOT NULL DEFAULT '[]';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS attributes jsonb NOT NULL DEFAULT '{}';
+
+UPDATE products SET attributes = metadata::jsonb
+WHERE metadata IS NOT NULL AND json_typeof(metadata) = 'object' AND attributes = '{}'::jsonb;
+
+-- jsonb_path_ops: компактний індекс для фільтрів рівності (attributes @> '{"species": "dog"}')
+
+CREATE INDEX IF NOT EXISTS idx_products_attributes ON products USING GIN (attributes jsonb_path_ops);
@@ -0,0 +1,18 @@
+-- Завантажені зображення продуктів: файли в сховищі (ключ оригіналу), URL мініатюр у jsonb, порядок показу
+
+CREATE TABLE IF NOT EXISTS product_images (
+    id           bigserial PRIMARY KEY,
+    created_at   timestamptz,
+    product_id   bigint NOT NULL,
+    position     bigint NOT NULL DEFAULT 0,
+    key          varchar(255) NOT NULL,
+    url          varchar(255) NOT NULL,
+    content_type varchar(50) NOT NULL,
+    size_bytes   bigint NOT NULL,
+    width        bigint NOT NULL,
+    height       bigint NOT NULL,
+    thumbnails   jsonb NOT NULL DEFAULT '{}',
+    CONSTRAINT fk_products_images FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_images_product_id ON product_images (product_id, position);
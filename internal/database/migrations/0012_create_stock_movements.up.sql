@@ -0,0 +1,35 @@
+-- Журнал руху товару: кожна зміна products.stock записується з типом, причиною і автором.
+-- Журнал лише доповнюється — тригер забороняє UPDATE і DELETE.
+
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id          bigserial PRIMARY KEY,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    product_id  bigint NOT NULL,
+    type        varchar(20) NOT NULL CHECK (type IN ('receipt', 'sale', 'return', 'adjustment', 'damage')),
+    quantity    bigint NOT NULL CHECK (quantity <> 0),
+    stock_after bigint NOT NULL CHECK (stock_after >= 0),
+    reason      varchar(500),
+    actor_id    bigint NOT NULL DEFAULT 0,
+    order_id    bigint,
+    CONSTRAINT fk_stock_movements_product FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_id ON stock_movements (product_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_order_id ON stock_movements (order_id);
+
+CREATE OR REPLACE FUNCTION stock_movements_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_stock_movements_append_only
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION stock_movements_append_only();
+
+-- Початковий залишок наявних продуктів — перший запис журналу, від якого рахуються наступні рухи
+
+INSERT INTO stock_movements (created_at, product_id, type, quantity, stock_after, reason)
+SELECT now(), id, 'adjustment', stock, stock, 'opening balance'
+FROM products
+WHERE stock <> 0;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS stock_alerts;
+DROP INDEX IF EXISTS idx_products_reorder;
+ALTER TABLE products DROP COLUMN IF EXISTS reorder_quantity;
+ALTER TABLE products DROP COLUMN IF EXISTS reorder_point;
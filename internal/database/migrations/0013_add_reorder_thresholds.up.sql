@@ -0,0 +1,15 @@
+-- Пороги дозамовлення продуктів і стан сповіщень про низький залишок.
+-- Рядок у stock_alerts означає, що про продукт уже сповіщено; він видаляється, коли залишок знову вищий за поріг.
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reorder_point bigint NOT NULL DEFAULT 0 CHECK (reorder_point >= 0);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reorder_quantity bigint NOT NULL DEFAULT 0 CHECK (reorder_quantity >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_products_reorder ON products (id) WHERE reorder_point > 0 AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS stock_alerts (
+    product_id    bigint PRIMARY KEY,
+    created_at    timestamptz NOT NULL DEFAULT now(),
+    stock         bigint NOT NULL,
+    reorder_point bigint NOT NULL,
+    CONSTRAINT fk_stock_alerts_product FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE
+);
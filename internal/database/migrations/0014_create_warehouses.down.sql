@@ -0,0 +1,9 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS warehouse_id;
+
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_type_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_type_check
+    CHECK (type IN ('receipt', 'sale', 'return', 'adjustment', 'damage')) NOT VALID;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS warehouse_id;
+
+DROP TABLE IF EXISTS warehouse_stock;
+DROP TABLE IF EXISTS warehouses;
@@ -0,0 +1,51 @@
+-- Складські локації (склад, магазини) і залишки продуктів у кожній з них.
+-- products.stock залишається сумою залишків локацій; наявний залишок переноситься на основний склад.
+
+CREATE TABLE IF NOT EXISTS warehouses (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    code       varchar(50) NOT NULL,
+    name       varchar(100) NOT NULL,
+    kind       varchar(20) NOT NULL CHECK (kind IN ('warehouse', 'store')),
+    address    varchar(255),
+    priority   bigint NOT NULL DEFAULT 0,
+    active     boolean NOT NULL DEFAULT true
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_warehouses_code ON warehouses (code);
+
+CREATE TABLE IF NOT EXISTS warehouse_stock (
+    warehouse_id bigint NOT NULL,
+    product_id   bigint NOT NULL,
+    quantity     bigint NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    updated_at   timestamptz,
+    PRIMARY KEY (warehouse_id, product_id),
+    CONSTRAINT fk_warehouse_stock_warehouse FOREIGN KEY (warehouse_id) REFERENCES warehouses (id) ON DELETE RESTRICT,
+    CONSTRAINT fk_products_availability FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_warehouse_stock_product_id ON warehouse_stock (product_id);
+
+INSERT INTO warehouses (created_at, updated_at, code, name, kind, priority)
+VALUES (now(), now(), 'main', 'Основний склад', 'warehouse', 0)
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO warehouse_stock (warehouse_id, product_id, quantity, updated_at)
+SELECT w.id, p.id, p.stock, now()
+FROM products p CROSS JOIN warehouses w
+WHERE w.code = 'main' AND p.stock > 0
+ON CONFLICT DO NOTHING;
+
+-- Рухи журналу і позиції замовлень запам'ятовують локацію; попередні записи лишаються без неї
+
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS warehouse_id bigint
+    CONSTRAINT fk_stock_movements_warehouse REFERENCES warehouses (id) ON DELETE RESTRICT;
+CREATE INDEX IF NOT EXISTS idx_stock_movements_warehouse_id ON stock_movements (warehouse_id);
+
+ALTER TABLE stock_movements DROP CONSTRAINT IF EXISTS stock_movements_type_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_type_check
+    CHECK (type IN ('receipt', 'sale', 'return', 'adjustment', 'damage', 'transfer'));
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS warehouse_id bigint;
+CREATE INDEX IF NOT EXISTS idx_order_items_warehouse_id ON order_items (warehouse_id);
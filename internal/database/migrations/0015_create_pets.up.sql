@@ -0,0 +1,17 @@
+-- Профілі тварин покупців (вид, порода, вік, вага, алергії)
+
+CREATE TABLE IF NOT EXISTS pets (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    user_id    bigint NOT NULL,
+    name       varchar(50) NOT NULL,
+    species    varchar(20) NOT NULL,
+    breed      varchar(100),
+    birth_date date,
+    weight_kg  numeric(6,2) NOT NULL DEFAULT 0 CHECK (weight_kg >= 0),
+    allergies  jsonb NOT NULL DEFAULT '[]',
+    CONSTRAINT fk_pets_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_pets_user_id ON pets (user_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_products_rating_id;
+ALTER TABLE products DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE products DROP COLUMN IF EXISTS rating_avg;
+
+DROP TABLE IF EXISTS reviews;
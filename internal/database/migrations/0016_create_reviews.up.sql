@@ -0,0 +1,30 @@
+-- Відгуки покупців (оцінка 1–5, модерація) і агрегований рейтинг продукту зі схвалених відгуків
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id                bigserial PRIMARY KEY,
+    created_at        timestamptz,
+    updated_at        timestamptz,
+    product_id        bigint NOT NULL,
+    user_id           bigint NOT NULL,
+    rating            smallint NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    text              text NOT NULL,
+    verified_purchase boolean NOT NULL DEFAULT false,
+    status            varchar(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    moderation_note   varchar(500),
+    moderated_by      bigint,
+    moderated_at      timestamptz,
+    CONSTRAINT fk_reviews_product FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE,
+    CONSTRAINT fk_reviews_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_product_user ON reviews (product_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_reviews_user_id ON reviews (user_id);
+CREATE INDEX IF NOT EXISTS idx_reviews_status ON reviews (status);
+CREATE INDEX IF NOT EXISTS idx_reviews_product_approved ON reviews (product_id, created_at) WHERE status = 'approved';
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rating_avg numeric(3,2) NOT NULL DEFAULT 0 CHECK (rating_avg BETWEEN 0 AND 5);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS rating_count bigint NOT NULL DEFAULT 0 CHECK (rating_count >= 0);
+
+-- Keyset-пагінація каталогу за рейтингом (як idx_products_price_id)
+
+CREATE INDEX IF NOT EXISTS idx_products_rating_id ON products (rating_avg, id) WHERE deleted_at IS NULL;
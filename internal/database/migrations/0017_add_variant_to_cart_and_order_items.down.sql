@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_order_items_variant_id;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+
+DELETE FROM cart_items WHERE variant_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_cart_product;
+ALTER TABLE cart_items DROP CONSTRAINT IF EXISTS fk_cart_items_variant;
+ALTER TABLE cart_items DROP COLUMN IF EXISTS variant_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_product ON cart_items (cart_id, product_id);
@@ -0,0 +1,13 @@
+-- Варіант продукту в позиціях кошика і замовлення: ціна, артикул і залишок беруться з варіанту.
+-- У кошику один рядок на пару продукт + варіант (NULL — продукт без варіанту).
+
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS variant_id bigint;
+ALTER TABLE cart_items ADD CONSTRAINT fk_cart_items_variant FOREIGN KEY (variant_id) REFERENCES product_variants (id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_cart_product;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_product ON cart_items (cart_id, product_id, (COALESCE(variant_id, 0)));
+
+-- Позиція замовлення — знімок: варіант може бути видалено, тому зовнішнього ключа немає (як і для product_id)
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id bigint;
+CREATE INDEX IF NOT EXISTS idx_order_items_variant_id ON order_items (variant_id);
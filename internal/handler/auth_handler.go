@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"time"
 
+	"github.com/AlexRijikov/go-petshop-api/internal/auth"
 	"github.com/AlexRijikov/go-petshop-api/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
-// AuthHandler обробляє HTTP-запити, пов'язані з аутентифікацією користувачів (реєстрація та вхід)
+// AuthHandler обробляє HTTP-запити, пов'язані з аутентифікацією користувачів (реєстрація, вхід, оновлення токенів, вихід)
 
 type AuthHandler struct {
 	svc services.AuthService
@@ -20,11 +24,16 @@ func NewAuthHandler(s services.AuthService) *AuthHandler {
 }
 
 // RegisterRoutes реєструє маршрути аутентифікації у вказаній групі маршрутизатора (rg *gin.RouterGroup)
+// Вихід (logout) потребує дійсного access токена, тому проходить через middleware з protect
 
-func (h *AuthHandler) RegisterRoutes(rg *gin.RouterGroup) {
+func (h *AuthHandler) RegisterRoutes(rg *gin.RouterGroup, protect ...gin.HandlerFunc) {
 	auth := rg.Group("/auth")
 	auth.POST("/register", h.Register)
 	auth.POST("/login", h.Login)
+	auth.POST("/refresh", h.Refresh)
+
+	logout := append(append([]gin.HandlerFunc{}, protect...), h.Logout) // новий зріз: не пишемо в масив викликача
+	auth.POST("/logout", logout...)
 }
 
 // authRequest використовується для прив'язки та валідації вхідних даних при реєстрації та вході користувача
@@ -43,14 +52,14 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	// Якщо помилка прив'язки/валідації, повертаємо 400 Bad Request (помилка клієнта)
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(invalidBody(err))
 		return
 	}
 
 	// Викликаємо сервіс для реєстрації користувача
 
 	if err := h.svc.Register(c.Request.Context(), req.Email, req.Password); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -63,17 +72,71 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req authRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(invalidBody(err))
 		return
 	}
 
-	token, err := h.svc.Login(c.Request.Context(), req.Email, req.Password)
+	tokens, err := h.svc.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		c.Error(err)
 		return
 	}
 
-	// Повертаємо токен у відповіді
+	// Повертаємо access і refresh токени у відповіді
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// refreshRequest містить refresh токен для оновлення пари токенів або виходу
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Refresh обмінює refresh токен на нову пару токенів (старий refresh токен стає недійсним)
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	tokens, err := h.svc.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout відкликає поточний access токен (jti з auth.Principal) і, якщо передано, refresh токен поточного користувача.
+// Тіло запиту необов'язкове: {"refresh_token": "..."}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	principal, ok := auth.PrincipalFrom(c)
+	if !ok {
+		c.Error(auth.ErrUnauthenticated)
+		return
+	}
+	exp := principal.ExpiresAt
+	if exp.IsZero() {
+		exp = time.Now().Add(24 * time.Hour) // запасний варіант, якщо в токені немає exp
+	}
+
+	if err := h.svc.Logout(c.Request.Context(), principal.UserID, principal.TokenID, exp, req.RefreshToken); err != nil {
+		c.Error(err)
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
 }
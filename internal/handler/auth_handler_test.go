@@ -0,0 +1,28 @@
+package handlers_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/handler"
+)
+
+// Тест: реєстрація маршрутів не записує h.Logout у вільну ємність зрізу middleware викликача
+
+func TestAuthRoutesKeepCallerMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	sentinel := func(c *gin.Context) {}
+	protect := make([]gin.HandlerFunc, 1, 2)
+	protect[0] = func(c *gin.Context) { c.Next() }
+	spare := protect[:2]
+	spare[1] = sentinel
+
+	handlers.NewAuthHandler(nil).RegisterRoutes(r.Group("/api"), protect...)
+
+	assert.Equal(t, reflect.ValueOf(sentinel).Pointer(), reflect.ValueOf(spare[1]).Pointer(), "вільний елемент зрізу викликача не перезаписано")
+}
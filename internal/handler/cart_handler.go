@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/auth"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// CartHandler обробляє HTTP-запити кошика поточного користувача (/api/cart)
+
+type CartHandler struct {
+	svc services.CartService
+}
+
+// NewCartHandler створює новий CartHandler з наданим сервісом CartService
+
+func NewCartHandler(s services.CartService) *CartHandler {
+	return &CartHandler{svc: s}
+}
+
+// RegisterRoutes реєструє маршрути кошика; всі вони потребують авторизації (protect).
+// Позицію варіанту в PUT/DELETE /items/:product_id задає ?variant_id=
+
+func (h *CartHandler) RegisterRoutes(rg *gin.RouterGroup, protect ...gin.HandlerFunc) {
+	grp := rg.Group("/cart", protect...)
+	grp.GET("", h.Get)
+	grp.DELETE("", h.Clear)
+	grp.POST("/items", h.AddItem)
+	grp.PUT("/items/:product_id", h.UpdateItem)
+	grp.DELETE("/items/:product_id", h.RemoveItem)
+}
+
+// addCartItemRequest — продукт (і, за потреби, його варіант) та кількість для додавання в кошик
+
+type addCartItemRequest struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	VariantID uint `json:"variant_id"` // 0 — продукт без варіанту
+	Quantity  int  `json:"quantity" binding:"required,gt=0"`
+}
+
+// updateCartItemRequest — нова кількість продукту в кошику (0 — видалити)
+
+type updateCartItemRequest struct {
+	Quantity *int `json:"quantity" binding:"required,gte=0"`
+}
+
+// Get (Отримання кошика з підрахованою сумою)
+
+func (h *CartHandler) Get(c *gin.Context) {
+	cart, err := h.svc.GetCart(c.Request.Context(), auth.UserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// AddItem (Додавання продукту в кошик)
+
+func (h *CartHandler) AddItem(c *gin.Context) {
+	var req addCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	cart, err := h.svc.AddItem(c.Request.Context(), auth.UserID(c), req.ProductID, req.VariantID, req.Quantity)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// UpdateItem (Зміна кількості продукту в кошику)
+
+func (h *CartHandler) UpdateItem(c *gin.Context) {
+	productID, ok := paramID(c, "product_id")
+	if !ok {
+		return
+	}
+	variantID, ok := queryVariantID(c)
+	if !ok {
+		return
+	}
+	var req updateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	cart, err := h.svc.UpdateItem(c.Request.Context(), auth.UserID(c), productID, variantID, *req.Quantity)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// RemoveItem (Видалення продукту з кошика)
+
+func (h *CartHandler) RemoveItem(c *gin.Context) {
+	productID, ok := paramID(c, "product_id")
+	if !ok {
+		return
+	}
+	variantID, ok := queryVariantID(c)
+	if !ok {
+		return
+	}
+	cart, err := h.svc.RemoveItem(c.Request.Context(), auth.UserID(c), productID, variantID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// Clear (Очищення кошика)
+
+func (h *CartHandler) Clear(c *gin.Context) {
+	if err := h.svc.Clear(c.Request.Context(), auth.UserID(c)); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// queryVariantID читає необов'язковий ?variant_id= (0 — позиція без варіанту); некоректне значення — errInvalidQuery
+
+func queryVariantID(c *gin.Context) (uint, bool) {
+	p := c.Query("variant_id")
+	if p == "" {
+		return 0, true
+	}
+	v, err := strconv.ParseUint(p, 10, 64)
+	if err != nil || v == 0 {
+		c.Error(invalidQuery("variant_id"))
+		return 0, false
+	}
+	return uint(v), true
+}
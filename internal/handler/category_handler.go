@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryHandler обробляє HTTP-запити таксономії каталогу (/api/categories)
+// і призначення категорій продуктам (/api/products/:id/categories)
+
+type CategoryHandler struct {
+	svc      services.CategoryService
+	products services.ProductService
+}
+
+// NewCategoryHandler створює новий CategoryHandler; ProductService потрібен для вибірки продуктів категорії
+
+func NewCategoryHandler(s services.CategoryService, products services.ProductService) *CategoryHandler {
+	return &CategoryHandler{svc: s, products: products}
+}
+
+// RegisterRoutes реєструє маршрути категорій. Читання публічне,
+// а зміни (POST/PUT/DELETE і категорії продукту) проходять через protect (авторизація + роль admin)
+
+func (h *CategoryHandler) RegisterRoutes(rg *gin.RouterGroup, protect ...gin.HandlerFunc) {
+	grp := rg.Group("/categories")
+	grp.GET("", h.List)
+	grp.GET("/:slug", h.Get)
+	grp.GET("/:slug/products", h.Products)
+
+	write := grp.Group("", protect...)
+	write.POST("", h.Create)
+	write.PUT("/:id", h.Update)
+	write.DELETE("/:id", h.Delete)
+
+	rg.Group("/products", protect...).PUT("/:id/categories", h.SetProductCategories)
+}
+
+// categoryRequest — дані категорії для створення або оновлення; slug генерується з назви, якщо порожній.
+// PUT замінює і схему атрибутів: без attributes схема категорії стає порожньою.
+
+type categoryRequest struct {
+	Name        string                 `json:"name" binding:"required,min=2,max=100"`
+	Slug        string                 `json:"slug" binding:"omitempty,max=100"`
+	Description string                 `json:"description" binding:"omitempty,max=2000"`
+	ParentID    *uint                  `json:"parent_id" binding:"omitempty,gt=0"`
+	Attributes  models.AttributeSchema `json:"attributes" binding:"omitempty,max=30"` // схема атрибутів продуктів категорії (успадковується підкатегоріями)
+}
+
+// List (Дерево категорій)
+
+func (h *CategoryHandler) List(c *gin.Context) {
+	items, err := h.svc.ListCategories(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// Get (Категорія за slug)
+
+func (h *CategoryHandler) Get(c *gin.Context) {
+	cat, err := h.svc.GetCategory(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, cat)
+}
+
+// Products (Продукти категорії разом з усіма підкатегоріями)
+// Підтримує ті ж фільтри, сортування і пагінацію, що й GET /api/products.
+
+func (h *CategoryHandler) Products(c *gin.Context) {
+	ids, err := h.svc.SubtreeIDs(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	f, cursorMode, ok := productQuery(c)
+	if !ok {
+		return
+	}
+	f.CategoryIDs = ids
+	respondProducts(c, h.products, f, cursorMode)
+}
+
+// Create (Створення категорії)
+
+func (h *CategoryHandler) Create(c *gin.Context) {
+	var req categoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	created, err := h.svc.CreateCategory(c.Request.Context(), &models.Category{
+		Name: req.Name, Slug: req.Slug, Description: req.Description, ParentID: req.ParentID, Attributes: req.Attributes,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// Update (Оновлення або перенесення категорії; parent_id null — зробити кореневою)
+
+func (h *CategoryHandler) Update(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req categoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	updated, err := h.svc.UpdateCategory(c.Request.Context(), &models.Category{
+		ID: id, Name: req.Name, Slug: req.Slug, Description: req.Description, ParentID: req.ParentID, Attributes: req.Attributes,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// Delete (Видалення категорії без підкатегорій)
+
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteCategory(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// productCategoriesRequest — повний список категорій продукту (замінює попередній)
+
+type productCategoriesRequest struct {
+	CategoryIDs []uint `json:"category_ids" binding:"omitempty,max=20"`
+}
+
+// SetProductCategories (Призначення категорій продукту; відповідь — оновлений продукт з ETag)
+
+func (h *CategoryHandler) SetProductCategories(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req productCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	if err := h.svc.SetProductCategories(c.Request.Context(), id, req.CategoryIDs); err != nil {
+		c.Error(err)
+		return
+	}
+	p, err := h.products.GetProduct(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Header("ETag", versionETag(p.Version))
+	c.JSON(http.StatusOK, p)
+}
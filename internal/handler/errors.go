@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/gin-gonic/gin"
+)
+
+// Помилки вхідних даних HTTP-рівня. Handlers не пишуть відповідь з помилкою самі:
+// вони викликають c.Error(err) і виходять, а middleware.ErrorHandler рендерить problem+json.
+
+var (
+	errInvalidID    = apperr.Validation("invalid_id", "invalid id")                           // ID у шляху не є додатним числом
+	errInvalidBody  = apperr.Validation("invalid_request_body", "invalid request body")       // тіло не пройшло прив'язку/валідацію
+	errInvalidQuery = apperr.Validation("invalid_query_parameter", "invalid query parameter") // некоректний query-параметр
+
+	errUnsupportedMediaType = apperr.New(apperr.KindUnsupportedMediaType, "unsupported_media_type", "unsupported content type") // Content-Type тіла не підтримується
+)
+
+// invalidBody повертає errInvalidBody з поясненням валідатора (які поля некоректні)
+
+func invalidBody(err error) error {
+	return errInvalidBody.WithDetail(err.Error())
+}
+
+// invalidQuery повертає errInvalidQuery з назвою параметра
+
+func invalidQuery(param string) error {
+	return errInvalidQuery.WithDetail(param)
+}
+
+// paramID читає додатний ID з параметра шляху; якщо він некоректний — додає errInvalidID і повертає false
+
+func paramID(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		c.Error(errInvalidID.WithDetail(name))
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/gin-gonic/gin"
+)
+
+// Умовні запити (RFC 9110): ETag у відповідях, If-None-Match для кешованого читання (304)
+// та If-Match для змін (412, якщо ресурс вже змінили).
+
+// errInvalidIfMatch — If-Match не містить жодного сильного ETag версії, отже умова не може виконатися
+
+var errInvalidIfMatch = apperr.PreconditionFailed("invalid_if_match", "If-Match must be a single strong ETag")
+
+// versionETag будує сильний ETag з версії ресурсу ("<version>")
+
+func versionETag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// ifMatchVersion читає очікувану версію з If-Match. 0 — заголовка немає або "*" (будь-яка версія).
+// Підтримується один сильний ETag, виданий versionETag; інакше додає errInvalidIfMatch і повертає false.
+
+func ifMatchVersion(c *gin.Context) (uint, bool) {
+	v := strings.TrimSpace(c.GetHeader("If-Match"))
+	if v == "" || v == "*" {
+		return 0, true
+	}
+	if len(v) < 2 || v[0] != '"' || v[len(v)-1] != '"' {
+		c.Error(errInvalidIfMatch)
+		return 0, false
+	}
+	n, err := strconv.ParseUint(v[1:len(v)-1], 10, 64)
+	if err != nil || n == 0 {
+		c.Error(errInvalidIfMatch)
+		return 0, false
+	}
+	return uint(n), true
+}
+
+// notModified перевіряє If-None-Match (слабке порівняння: W/ ігнорується) і, якщо тег збігся,
+// відповідає 304 без тіла. Повертає true, якщо відповідь вже надіслано.
+
+func notModified(c *gin.Context, etag string) bool {
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// jsonWithETag відповідає 200 з JSON-тілом і слабким ETag від його вмісту (для списків без однієї версії).
+// Якщо клієнт вже має цю відповідь (If-None-Match), повертає 304 без тіла.
+
+func jsonWithETag(c *gin.Context, obj interface{}) {
+	body, err := json.Marshal(obj)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	if notModified(c, etag) {
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
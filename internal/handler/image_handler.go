@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead — запас понад ліміт файлу на заголовки multipart і межі частин
+const multipartOverhead = 64 << 10
+
+// ImageHandler обробляє HTTP-запити зображень продукту (/api/products/:id/images)
+
+type ImageHandler struct {
+	svc     services.ImageService
+	maxBody int64 // ліміт тіла запиту завантаження (файл + multipartOverhead)
+}
+
+// NewImageHandler створює новий ImageHandler; maxUploadSize — ліміт розміру файлу (STORAGE_MAX_UPLOAD_SIZE)
+
+func NewImageHandler(s services.ImageService, maxUploadSize int64) *ImageHandler {
+	return &ImageHandler{svc: s, maxBody: maxUploadSize + multipartOverhead}
+}
+
+// RegisterRoutes реєструє маршрути зображень: перегляд публічний, зміни — через protect (авторизація + роль admin)
+
+func (h *ImageHandler) RegisterRoutes(rg *gin.RouterGroup, protect ...gin.HandlerFunc) {
+	grp := rg.Group("/products/:id/images")
+	grp.GET("", h.List)
+
+	write := grp.Group("", protect...)
+	write.POST("", h.Upload)
+	write.PUT("", h.Reorder)
+	write.DELETE("/:imageId", h.Delete)
+}
+
+// List (Зображення продукту в порядку показу)
+
+func (h *ImageHandler) List(c *gin.Context) {
+	productID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	items, err := h.svc.ListImages(c.Request.Context(), productID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// Upload (Завантаження зображення: multipart/form-data з файлом у полі image)
+// Файл читається потоком з тіла запиту без тимчасових файлів; тип визначається за вмістом, а не за заголовками.
+
+func (h *ImageHandler) Upload(c *gin.Context) {
+	productID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBody)
+	mr, err := c.Request.MultipartReader()
+	if err != nil {
+		c.Error(errUnsupportedMediaType.WithDetail("use multipart/form-data"))
+		return
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			c.Error(errInvalidBody.WithDetail("file field image is required"))
+			return
+		}
+		if err != nil {
+			c.Error(multipartError(err))
+			return
+		}
+		if part.FormName() != "image" {
+			continue
+		}
+		img, err := h.svc.UploadImage(c.Request.Context(), productID, part)
+		if err != nil {
+			if bodyTooLarge(err) {
+				err = services.ErrImageTooLarge
+			}
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusCreated, img)
+		return
+	}
+}
+
+// multipartError перетворює помилку розбору multipart: перевищення ліміту тіла — ErrImageTooLarge,
+// інакше тіло пошкоджене — errInvalidBody
+
+func multipartError(err error) error {
+	if bodyTooLarge(err) {
+		return services.ErrImageTooLarge
+	}
+	return errInvalidBody.WithDetail(err.Error())
+}
+
+// bodyTooLarge перевіряє, чи помилка спричинена лімітом http.MaxBytesReader
+
+func bodyTooLarge(err error) bool {
+	var tooLarge *http.MaxBytesError
+	return errors.As(err, &tooLarge)
+}
+
+// imageOrderRequest — усі зображення продукту в новому порядку (перше стає головним)
+
+type imageOrderRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required,max=20"`
+}
+
+// Reorder (Зміна порядку зображень продукту)
+
+func (h *ImageHandler) Reorder(c *gin.Context) {
+	productID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req imageOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	items, err := h.svc.ReorderImages(c.Request.Context(), productID, req.ImageIDs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// Delete (Видалення зображення разом з мініатюрами)
+
+func (h *ImageHandler) Delete(c *gin.Context) {
+	productID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	id, ok := paramID(c, "imageId")
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteImage(c.Request.Context(), productID, id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,86 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/handler"
+	"github.com/AlexRijikov/go-petshop-api/internal/middleware"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// stubImageService читає завантажений файл повністю, як справжній сервіс, і запам'ятовує його
+
+type stubImageService struct {
+	services.ImageService
+	uploaded []byte
+}
+
+func (s *stubImageService) UploadImage(ctx context.Context, productID uint, r io.Reader) (*models.ProductImage, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	s.uploaded = data
+	return &models.ProductImage{ID: 1, ProductID: productID, URL: "/media/x/original.png"}, nil
+}
+
+// multipartBody створює тіло multipart/form-data з одним файлом у полі field
+
+func multipartBody(t *testing.T, field string, data []byte) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField("alt", "photo"))
+	fw, err := mw.CreateFormFile(field, "photo.png")
+	require.NoError(t, err)
+	_, err = fw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	return &body, mw.FormDataContentType()
+}
+
+// Тест завантаження: файл з поля image передається сервісу потоком, ліміт тіла дає 413
+
+func TestUploadImageHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &stubImageService{}
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	handlers.NewImageHandler(svc, 1000).RegisterRoutes(r.Group("/api"))
+
+	upload := func(body io.Reader, contentType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/products/7/images", body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	body, ct := multipartBody(t, "image", []byte("png bytes"))
+	w := upload(body, ct)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "png bytes", string(svc.uploaded))
+	assert.Contains(t, w.Body.String(), `"product_id":7`)
+
+	body, ct = multipartBody(t, "file", []byte("png bytes"))
+	w = upload(body, ct)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, ct = multipartBody(t, "image", make([]byte, 100<<10))
+	w = upload(body, ct)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"image_too_large"`)
+
+	w = upload(bytes.NewBufferString(`{"image": "x"}`), "application/json")
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
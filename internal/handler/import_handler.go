@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportBody — найбільший файл імпорту; файл читається повністю до початку запису,
+// щоб завеликий файл відхилявся без часткового імпорту
+const maxImportBody = 32 << 20
+
+// errImportTooLarge — файл імпорту більший за maxImportBody
+
+var errImportTooLarge = apperr.PayloadTooLarge("import_too_large", "import file is too large")
+
+// importMediaTypes — Content-Type тіла імпорту → формат (якщо format не задано в query)
+
+var importMediaTypes = map[string]string{
+	"text/csv":              services.FormatCSV,
+	"application/csv":       services.FormatCSV,
+	"application/x-ndjson":  services.FormatNDJSON,
+	"application/jsonl":     services.FormatNDJSON,
+	"application/jsonlines": services.FormatNDJSON,
+}
+
+// exportContentTypes — Content-Type відповіді експорту за форматом
+
+var exportContentTypes = map[string]string{
+	services.FormatCSV:    "text/csv; charset=utf-8",
+	services.FormatNDJSON: "application/x-ndjson",
+}
+
+// ImportHandler обробляє масовий імпорт і експорт каталогу (/api/admin/products/import, /export)
+
+type ImportHandler struct {
+	svc services.ImportService
+}
+
+// NewImportHandler створює новий ImportHandler з наданим сервісом
+
+func NewImportHandler(s services.ImportService) *ImportHandler {
+	return &ImportHandler{svc: s}
+}
+
+// RegisterAdminRoutes реєструє маршрути імпорту та експорту в групі адміністратора (/api/admin)
+
+func (h *ImportHandler) RegisterAdminRoutes(rg *gin.RouterGroup) {
+	grp := rg.Group("/products")
+	grp.POST("/import", h.Import)
+	grp.GET("/export", h.Export)
+}
+
+// Import (Масовий імпорт продуктів з CSV або NDJSON з оновленням за артикулом)
+// Формат — з query format=csv|ndjson або з Content-Type (text/csv, application/x-ndjson).
+// Відповідь 200 з кількістю створених, оновлених і пропущених рядків та помилками рядків.
+
+func (h *ImportHandler) Import(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if format = importMediaTypes[mediaType]; format == "" {
+			c.Error(errUnsupportedMediaType.WithDetail("use text/csv or application/x-ndjson"))
+			return
+		}
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBody))
+	if err != nil {
+		if bodyTooLarge(err) {
+			err = errImportTooLarge.WithDetail("max 32 MiB")
+		}
+		c.Error(err)
+		return
+	}
+	res, err := h.svc.ImportProducts(c.Request.Context(), format, bytes.NewReader(data))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// Export (Потоковий експорт усіх продуктів: format=csv (за замовчуванням) або ndjson)
+// Файл придатний для повторного імпорту. Помилка посеред потоку лише логується — відповідь уже почалась.
+
+func (h *ImportHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", services.FormatCSV)
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		c.Error(invalidQuery("format"))
+		return
+	}
+
+	// Експорт великого каталогу триває довше за SERVER_WRITE_TIMEOUT, тож для цієї відповіді дедлайн запису знімається,
+	// інакше сервер мовчки обірвав би файл посередині
+
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("Експорт продуктів: не вдалося зняти дедлайн запису: %v", err)
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="products-`+time.Now().UTC().Format("20060102")+"."+format+`"`)
+	w := &flushWriter{ResponseWriter: c.Writer}
+	if err := h.svc.ExportProducts(c.Request.Context(), format, w); err != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			c.Error(err)
+			return
+		}
+		log.Printf("Експорт продуктів перервано: %v", err)
+	}
+}
+
+// flushWriter надсилає клієнту кожен записаний шматок одразу (потокова відповідь без буферизації всього файлу)
+
+type flushWriter struct {
+	gin.ResponseWriter
+}
+
+func (w *flushWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.ResponseWriter.Flush()
+	return n, err
+}
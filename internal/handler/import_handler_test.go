@@ -0,0 +1,100 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/handler"
+	"github.com/AlexRijikov/go-petshop-api/internal/middleware"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// stubImportService запам'ятовує формат імпорту і віддає фіксований експорт
+
+type stubImportService struct {
+	format string
+	pause  time.Duration // пауза посеред експорту (довгий потік)
+}
+
+func (s *stubImportService) ImportProducts(ctx context.Context, format string, r io.Reader) (*services.ImportResult, error) {
+	s.format = format
+	return &services.ImportResult{Created: 1, Errors: []services.ImportRowError{}}, nil
+}
+
+func (s *stubImportService) ExportProducts(ctx context.Context, format string, w io.Writer) error {
+	if _, err := io.WriteString(w, "sku,name\n"); err != nil {
+		return err
+	}
+	time.Sleep(s.pause)
+	_, err := io.WriteString(w, "DOG-1,Корм\n")
+	return err
+}
+
+// Тест: формат імпорту визначається з query або Content-Type, експорт віддається файлом
+
+func TestImportExportHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &stubImportService{}
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	handlers.NewImportHandler(svc).RegisterAdminRoutes(r.Group("/api/admin"))
+
+	post := func(url, contentType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, url, bytes.NewBufferString("sku\nDOG-1\n"))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/api/admin/products/import", "text/csv; charset=utf-8")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, services.FormatCSV, svc.format)
+	assert.Contains(t, w.Body.String(), `"created":1`)
+
+	post("/api/admin/products/import?format=ndjson", "text/plain")
+	assert.Equal(t, services.FormatNDJSON, svc.format)
+
+	w = post("/api/admin/products/import", "application/json")
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/products/export", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".csv")
+	assert.Equal(t, "sku,name\nDOG-1,Корм\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/products/export?format=xlsx", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// Тест: експорт, довший за WriteTimeout сервера, доходить до клієнта повністю
+
+func TestExportOutlivesWriteTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handlers.NewImportHandler(&stubImportService{pause: 300 * time.Millisecond}).RegisterAdminRoutes(r.Group("/api/admin"))
+
+	srv := httptest.NewUnstartedServer(r)
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/admin/products/export")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "sku,name\nDOG-1,Корм\n", string(body))
+}
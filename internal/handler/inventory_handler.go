@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/auth"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// InventoryHandler обробляє HTTP-запити журналу складу (/api/admin/products/:id/stock)
+// і список продуктів для дозамовлення (/api/admin/products/reorder)
+
+type InventoryHandler struct {
+	svc services.InventoryService
+}
+
+// NewInventoryHandler створює новий InventoryHandler з наданим сервісом
+
+func NewInventoryHandler(s services.InventoryService) *InventoryHandler {
+	return &InventoryHandler{svc: s}
+}
+
+// RegisterAdminRoutes реєструє маршрути складу в групі адміністратора (/api/admin)
+
+func (h *InventoryHandler) RegisterAdminRoutes(rg *gin.RouterGroup) {
+	rg.GET("/products/reorder", h.Reorder)
+
+	grp := rg.Group("/products/:id/stock")
+	grp.GET("", h.StockAt)
+	grp.GET("/movements", h.History)
+	grp.POST("/movements", h.RecordMovement)
+	grp.POST("/transfers", h.Transfer)
+}
+
+// RecordMovement (Надходження, продаж, повернення, коригування або списання товару)
+
+func (h *InventoryHandler) RecordMovement(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req services.MovementInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	m, err := h.svc.RecordMovement(c.Request.Context(), id, req, auth.UserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, m)
+}
+
+// Transfer (Переміщення товару між локаціями; відповідь — пара рухів transfer)
+
+func (h *InventoryHandler) Transfer(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req services.TransferInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	movements, err := h.svc.Transfer(c.Request.Context(), id, req, auth.UserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"items": movements})
+}
+
+// History (Рухи товару за період ?from=&to= (RFC 3339), нові першими, з пагінацією)
+
+func (h *InventoryHandler) History(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	f := repositories.MovementFilter{Limit: 20}
+	if l := c.Query("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 {
+			f.Limit = v
+		}
+	}
+	if o := c.Query("offset"); o != "" {
+		if v, err := strconv.Atoi(o); err == nil && v >= 0 {
+			f.Offset = v
+		}
+	}
+	if f.From, ok = queryTime(c, "from"); !ok {
+		return
+	}
+	if f.To, ok = queryTime(c, "to"); !ok {
+		return
+	}
+
+	items, total, err := h.svc.History(c.Request.Context(), id, f)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total, "limit": f.Limit, "offset": f.Offset})
+}
+
+// StockAt (Залишок продукту на момент ?at= (RFC 3339), за замовчуванням — зараз)
+
+func (h *InventoryHandler) StockAt(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	at, ok := queryTime(c, "at")
+	if !ok {
+		return
+	}
+	var moment time.Time
+	if at != nil {
+		moment = *at
+	}
+
+	level, err := h.svc.StockAt(c.Request.Context(), id, moment)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, level)
+}
+
+// Reorder (Продукти, залишок яких опустився до порогу дозамовлення, з пагінацією)
+
+func (h *InventoryHandler) Reorder(c *gin.Context) {
+	limit := 20
+	offset := 0
+	if l := c.Query("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 {
+			limit = v
+		}
+	}
+	if o := c.Query("offset"); o != "" {
+		if v, err := strconv.Atoi(o); err == nil && v >= 0 {
+			offset = v
+		}
+	}
+	items, total, err := h.svc.ListReorder(c.Request.Context(), limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total, "limit": limit, "offset": offset})
+}
+
+// queryTime читає момент часу у форматі RFC 3339 (nil — параметр не задано);
+// якщо він некоректний — додає errInvalidQuery і повертає false
+
+func queryTime(c *gin.Context, param string) (*time.Time, bool) {
+	v := c.Query(param)
+	if v == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		c.Error(invalidQuery(param))
+		return nil, false
+	}
+	return &t, true
+}
@@ -0,0 +1,108 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/handler"
+	"github.com/AlexRijikov/go-petshop-api/internal/middleware"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// stubInventoryService запам'ятовує параметри останнього виклику
+
+type stubInventoryService struct {
+	input    services.MovementInput
+	transfer services.TransferInput
+	filter   repositories.MovementFilter
+	at       time.Time
+}
+
+func (s *stubInventoryService) RecordMovement(ctx context.Context, productID uint, in services.MovementInput, actorID uint) (*models.StockMovement, error) {
+	s.input = in
+	return &models.StockMovement{ID: 1, ProductID: productID, Type: in.Type, Quantity: in.Quantity, StockAfter: in.Quantity}, nil
+}
+
+func (s *stubInventoryService) Transfer(ctx context.Context, productID uint, in services.TransferInput, actorID uint) ([]models.StockMovement, error) {
+	s.transfer = in
+	return []models.StockMovement{
+		{ID: 2, ProductID: productID, Type: models.StockTransfer, Quantity: -in.Quantity, WarehouseID: &in.FromWarehouseID},
+		{ID: 3, ProductID: productID, Type: models.StockTransfer, Quantity: in.Quantity, WarehouseID: &in.ToWarehouseID},
+	}, nil
+}
+
+func (s *stubInventoryService) History(ctx context.Context, productID uint, f repositories.MovementFilter) ([]models.StockMovement, int64, error) {
+	s.filter = f
+	return []models.StockMovement{}, 0, nil
+}
+
+func (s *stubInventoryService) StockAt(ctx context.Context, productID uint, at time.Time) (*services.StockLevel, error) {
+	s.at = at
+	return &services.StockLevel{ProductID: productID, At: at, Stock: 3}, nil
+}
+
+func (s *stubInventoryService) ListReorder(ctx context.Context, limit, offset int) ([]models.Product, int64, error) {
+	return []models.Product{{ID: 2, Name: "Корм", Stock: 1, ReorderPoint: 5, ReorderQuantity: 20}}, 1, nil
+}
+
+// Тест маршрутів складу: рух з тіла, період історії та момент часу з query (RFC 3339)
+
+func TestInventoryHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &stubInventoryService{}
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	handlers.NewInventoryHandler(svc).RegisterAdminRoutes(r.Group("/api/admin"))
+
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/api/admin/products/1/stock/movements", `{"type": "receipt", "quantity": 5, "reason": "накладна"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, services.MovementInput{Type: "receipt", Quantity: 5, Reason: "накладна"}, svc.input)
+
+	w = do(http.MethodPost, "/api/admin/products/1/stock/movements", `{"quantity": 5}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do(http.MethodPost, "/api/admin/products/1/stock/transfers", `{"from_warehouse_id": 1, "to_warehouse_id": 2, "quantity": 4}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, services.TransferInput{FromWarehouseID: 1, ToWarehouseID: 2, Quantity: 4}, svc.transfer)
+	assert.Contains(t, w.Body.String(), `"warehouse_id":2`)
+
+	w = do(http.MethodGet, "/api/admin/products/1/stock/movements?from=2025-10-01T00:00:00Z&limit=5&offset=10", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotNil(t, svc.filter.From)
+	assert.True(t, svc.filter.From.Equal(time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Nil(t, svc.filter.To)
+	assert.Equal(t, 5, svc.filter.Limit)
+	assert.Equal(t, 10, svc.filter.Offset)
+
+	w = do(http.MethodGet, "/api/admin/products/1/stock?at=2025-10-02T12:00:00%2B03:00", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.True(t, svc.at.Equal(time.Date(2025, 10, 2, 9, 0, 0, 0, time.UTC)))
+	assert.Contains(t, w.Body.String(), `"stock":3`)
+
+	w = do(http.MethodGet, "/api/admin/products/reorder?limit=10", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"reorder_quantity":20`)
+	assert.Contains(t, w.Body.String(), `"total":1`)
+
+	w = do(http.MethodGet, "/api/admin/products/1/stock?at=yesterday", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_query_parameter"`)
+}
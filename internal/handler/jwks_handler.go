@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/keys"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler публікує публічні ключі JWT, щоб інші сервіси могли перевіряти токени petshop без спільного секрету
+
+type JWKSHandler struct {
+	keys *keys.Manager
+}
+
+// NewJWKSHandler створює новий JWKSHandler з менеджером ключів
+
+func NewJWKSHandler(m *keys.Manager) *JWKSHandler {
+	return &JWKSHandler{keys: m}
+}
+
+// RegisterRoutes реєструє GET /.well-known/jwks.json (поза префіксом /api)
+
+func (h *JWKSHandler) RegisterRoutes(r gin.IRoutes) {
+	r.GET("/.well-known/jwks.json", h.JWKS)
+}
+
+// JWKS повертає набір активних публічних ключів (RFC 7517).
+// Кешування коротке, щоб клієнти швидко підхоплювали нові ключі після ротації.
+
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/auth"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// OrderHandler обробляє HTTP-запити замовлень поточного користувача (/api/orders)
+
+type OrderHandler struct {
+	svc services.OrderService
+}
+
+// NewOrderHandler створює новий OrderHandler з наданим сервісом OrderService
+
+func NewOrderHandler(s services.OrderService) *OrderHandler {
+	return &OrderHandler{svc: s}
+}
+
+// RegisterRoutes реєструє маршрути замовлень; всі вони потребують авторизації (protect)
+
+func (h *OrderHandler) RegisterRoutes(rg *gin.RouterGroup, protect ...gin.HandlerFunc) {
+	grp := rg.Group("/orders", protect...)
+	grp.POST("", h.Checkout)
+	grp.GET("", h.List)
+	grp.GET("/:id", h.GetByID)
+}
+
+// RegisterAdminRoutes реєструє маршрути керування замовленнями в групі адміністратора (/api/admin)
+
+func (h *OrderHandler) RegisterAdminRoutes(rg *gin.RouterGroup) {
+	grp := rg.Group("/orders")
+	grp.GET("", h.AdminList)
+	grp.GET("/:id", h.AdminGetByID)
+	grp.POST("/:id/status", h.ChangeStatus)
+}
+
+// checkoutRequest — позиції замовлення; якщо items порожній, оформлюється кошик
+
+type checkoutRequest struct {
+	Items []services.CheckoutItem `json:"items" binding:"omitempty,dive"`
+}
+
+// Checkout (Оформлення замовлення з кошика або переданих позицій)
+
+func (h *OrderHandler) Checkout(c *gin.Context) {
+	var req checkoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	order, err := h.svc.Checkout(c.Request.Context(), auth.UserID(c), req.Items)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, order)
+}
+
+// List (Список замовлень користувача з пагінацією)
+
+func (h *OrderHandler) List(c *gin.Context) {
+	limit := 20
+	offset := 0
+	if l := c.Query("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 {
+			limit = v
+		}
+	}
+	if o := c.Query("offset"); o != "" {
+		if v, err := strconv.Atoi(o); err == nil && v >= 0 {
+			offset = v
+		}
+	}
+	items, total, err := h.svc.ListOrders(c.Request.Context(), auth.UserID(c), limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total, "limit": limit, "offset": offset})
+}
+
+// GetByID (Отримання замовлення за ID — лише власного)
+
+func (h *OrderHandler) GetByID(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	order, err := h.svc.GetOrder(c.Request.Context(), auth.UserID(c), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// AdminList (Список усіх замовлень для адміністратора, фільтр ?status=)
+
+func (h *OrderHandler) AdminList(c *gin.Context) {
+	limit := 20
+	offset := 0
+	if l := c.Query("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 {
+			limit = v
+		}
+	}
+	if o := c.Query("offset"); o != "" {
+		if v, err := strconv.Atoi(o); err == nil && v >= 0 {
+			offset = v
+		}
+	}
+	status := c.Query("status")
+	items, total, err := h.svc.ListAllOrders(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total, "limit": limit, "offset": offset})
+}
+
+// AdminGetByID (Отримання будь-якого замовлення з історією статусів)
+
+func (h *OrderHandler) AdminGetByID(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	order, err := h.svc.GetAnyOrder(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// changeStatusRequest — новий статус замовлення і необов'язковий коментар
+
+type changeStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note" binding:"omitempty,max=500"`
+}
+
+// ChangeStatus (Зміна статусу замовлення адміністратором)
+
+func (h *OrderHandler) ChangeStatus(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req changeStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	order, err := h.svc.ChangeStatus(c.Request.Context(), id, req.Status, auth.UserID(c), req.Note)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/pagination"
+	"github.com/gin-gonic/gin"
+)
+
+// Спільні query-параметри пагінації для списків.
+// Режим курсорів вмикається параметром cursor (порожній — перша сторінка) або pagination=cursor;
+// без них списки працюють як раніше через limit/offset.
+
+// queryCursor повертає курсор з ?cursor= і чи увімкнено режим курсорів
+
+func queryCursor(c *gin.Context) (*pagination.Cursor, bool, error) {
+	v, ok := c.GetQuery("cursor")
+	if !ok {
+		return nil, c.Query("pagination") == "cursor", nil
+	}
+	if v == "" {
+		return nil, true, nil
+	}
+	cursor, err := pagination.Decode(v)
+	if err != nil {
+		return nil, true, err
+	}
+	return cursor, true, nil
+}
+
+// queryWithTotal читає ?with_total=true|false; def — значення за замовчуванням (false — некоректне значення)
+
+func queryWithTotal(c *gin.Context, def bool) (bool, bool) {
+	v := c.Query("with_total")
+	if v == "" {
+		return def, true
+	}
+	withTotal, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, false
+	}
+	return withTotal, true
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/auth"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// PetHandler обробляє HTTP-запити профілів тварин поточного користувача (/api/users/me/pets)
+
+type PetHandler struct {
+	svc services.PetService
+}
+
+// NewPetHandler створює новий PetHandler з наданим сервісом
+
+func NewPetHandler(s services.PetService) *PetHandler {
+	return &PetHandler{svc: s}
+}
+
+// RegisterRoutes реєструє маршрути тварин; всі вони потребують авторизації (protect)
+
+func (h *PetHandler) RegisterRoutes(rg *gin.RouterGroup, protect ...gin.HandlerFunc) {
+	grp := rg.Group("/users/me/pets", protect...)
+	grp.GET("", h.List)
+	grp.POST("", h.Create)
+	grp.GET("/:id", h.Get)
+	grp.PUT("/:id", h.Update)
+	grp.DELETE("/:id", h.Delete)
+}
+
+// petRequest — профіль тварини для створення або оновлення (PUT замінює всі поля)
+
+type petRequest struct {
+	Name      string   `json:"name" binding:"required,max=50"`
+	Species   string   `json:"species" binding:"required"` // один з models.PetSpecies
+	Breed     string   `json:"breed" binding:"omitempty,max=100"`
+	BirthDate string   `json:"birth_date"` // YYYY-MM-DD, порожня — невідома
+	WeightKg  float64  `json:"weight_kg" binding:"gte=0"`
+	Allergies []string `json:"allergies" binding:"omitempty,max=20"`
+}
+
+// pet будує модель тварини із запиту; некоректна дата народження — errInvalidBody
+
+func (r *petRequest) pet(userID, id uint) (*models.Pet, error) {
+	p := &models.Pet{
+		ID: id, UserID: userID, Name: r.Name, Species: r.Species, Breed: r.Breed, WeightKg: r.WeightKg, Allergies: r.Allergies,
+	}
+	if r.BirthDate != "" {
+		d, err := time.Parse(time.DateOnly, r.BirthDate)
+		if err != nil {
+			return nil, errInvalidBody.WithDetail("birth_date must be in YYYY-MM-DD format")
+		}
+		p.BirthDate = &d
+	}
+	return p, nil
+}
+
+// List (Тварини поточного користувача)
+
+func (h *PetHandler) List(c *gin.Context) {
+	items, err := h.svc.ListPets(c.Request.Context(), auth.UserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// Get (Тварина за ID)
+
+func (h *PetHandler) Get(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	p, err := h.svc.GetPet(c.Request.Context(), auth.UserID(c), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// Create (Додавання тварини)
+
+func (h *PetHandler) Create(c *gin.Context) {
+	var req petRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	p, err := req.pet(auth.UserID(c), 0)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	created, err := h.svc.CreatePet(c.Request.Context(), p)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// Update (Оновлення профілю тварини)
+
+func (h *PetHandler) Update(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req petRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	p, err := req.pet(auth.UserID(c), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	updated, err := h.svc.UpdatePet(c.Request.Context(), p)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// Delete (Видалення тварини)
+
+func (h *PetHandler) Delete(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.svc.DeletePet(c.Request.Context(), auth.UserID(c), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -2,9 +2,13 @@ package handlers
 
 import (
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 
+	"github.com/AlexRijikov/go-petshop-api/internal/mergepatch"
 	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
 	"github.com/AlexRijikov/go-petshop-api/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -13,9 +17,13 @@ import (
 
 // Використовуємо binding для базової валідації.
 
-// List підтримує limit і offset (пагінація).
+// List підтримує фільтри (category, min_price, max_price, in_stock), пошук q, sort, limit і offset (пагінація)
+// або cursor (keyset-пагінація з next_cursor/prev_cursor).
 
-// Помилки переводимо в HTTP-статуси.
+// Помилки передаються в middleware.ErrorHandler через c.Error (problem+json).
+
+// Відповіді з продуктом мають ETag з його версії; PUT/PATCH/DELETE з If-Match змінюють продукт,
+// лише якщо версія не змінилася (інакше 412), а GET з If-None-Match повертає 304 для актуального кешу.
 
 // ProductHandler обробляє HTTP-запити, пов'язані з продуктами
 type ProductHandler struct {
@@ -28,31 +36,48 @@ func NewProductHandler(s services.ProductService) *ProductHandler {
 }
 
 // RegisterRoutes реєструє маршрути продуктів у вказаній групі маршрутизатора (rg *gin.RouterGroup)
-func (h *ProductHandler) RegisterRoutes(rg *gin.RouterGroup) {
+// Читання каталогу публічне, а зміни (POST/PUT/PATCH/DELETE) проходять через middleware з protect (авторизація + роль admin)
+func (h *ProductHandler) RegisterRoutes(rg *gin.RouterGroup, protect ...gin.HandlerFunc) {
 	grp := rg.Group("/products")
 	grp.GET("", h.List)
-	grp.POST("", h.Create)
 	grp.GET("/:id", h.GetByID)
-	grp.PUT("/:id", h.Update)
-	grp.DELETE("/:id", h.Delete)
+
+	write := grp.Group("", protect...)
+	write.POST("", h.Create)
+	write.PUT("/:id", h.Update)
+	write.PATCH("/:id", h.Patch)
+	write.DELETE("/:id", h.Delete)
 }
 
 // createProductRequest використовується для прив'язки та валідації вхідних даних при створенні або оновленні продукту
 
 type createProductRequest struct {
-	Name        string `json:"name" binding:"required,min=2,max=255"`
-	Description string `json:"description" binding:"omitempty,max=2000"`
-	PriceCents  int64  `json:"price_cents" binding:"required,gt=0"`
-	Stock       int    `json:"stock" binding:"gte=0"`
-	SKU         string `json:"sku" binding:"omitempty,max=100"`
+	Name        string            `json:"name" binding:"required,min=2,max=255"`
+	Description string            `json:"description" binding:"omitempty,max=2000"`
+	PriceCents  int64             `json:"price_cents" binding:"required,gt=0"`
+	Stock       int               `json:"stock" binding:"gte=0"` // початковий залишок; при оновленні ігнорується (рухи — /api/admin/products/:id/stock/movements)
+	SKU         string            `json:"sku" binding:"omitempty,max=100"`
+	Attributes  models.Attributes `json:"attributes"` // значення атрибутів за схемою категорій продукту
+
+	ReorderPoint    int `json:"reorder_point" binding:"gte=0"`    // поріг сповіщення про низький залишок (0 — не відстежується)
+	ReorderQuantity int `json:"reorder_quantity" binding:"gte=0"` // рекомендована кількість дозамовлення
+}
+
+// newProductRequest — дані нового продукту разом з необов'язковими варіантами і категоріями
+// (після створення вони змінюються через /api/products/:id/variants і /api/products/:id/categories)
+
+type newProductRequest struct {
+	createProductRequest
+	Variants    []variantRequest `json:"variants" binding:"omitempty,max=50,dive"`
+	CategoryIDs []uint           `json:"category_ids" binding:"omitempty,max=20"` // категорії визначають схему атрибутів
 }
 
 // Create (Створення нового продукту)
 
 func (h *ProductHandler) Create(c *gin.Context) {
-	var req createProductRequest
+	var req newProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(invalidBody(err))
 		return
 	}
 	p := &models.Product{
@@ -61,18 +86,45 @@ func (h *ProductHandler) Create(c *gin.Context) {
 		PriceCents:  req.PriceCents,
 		Stock:       req.Stock,
 		SKU:         req.SKU,
+		Attributes:  req.Attributes,
+
+		ReorderPoint:    req.ReorderPoint,
+		ReorderQuantity: req.ReorderQuantity,
+	}
+	for _, v := range req.Variants {
+		p.Variants = append(p.Variants, v.toModel())
+	}
+	for _, id := range req.CategoryIDs {
+		p.Categories = append(p.Categories, models.Category{ID: id})
 	}
 	created, err := h.svc.CreateProduct(c.Request.Context(), p)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
+	c.Header("ETag", versionETag(created.Version))
 	c.JSON(http.StatusCreated, created)
 }
 
-// List (Список продуктів з пагінацією)
+// List (Список продуктів з фільтрами, пошуком, сортуванням і пагінацією)
+// Параметри: category, min_price, max_price (у копійках), in_stock=true, q, sort, limit, offset.
+// Атрибути: attr.<name>=<value> (рівність), attr.<name>.min / attr.<name>.max (для числових).
+// Режим курсорів: cursor (або pagination=cursor для першої сторінки) замість offset.
+// with_total — чи рахувати total (за замовчуванням true для offset і false для курсорів).
+// ETag будується з вмісту сторінки; If-None-Match з ним повертає 304.
 
 func (h *ProductHandler) List(c *gin.Context) {
+	f, cursorMode, ok := productQuery(c)
+	if !ok {
+		return
+	}
+	respondProducts(c, h.svc, f, cursorMode)
+}
+
+// productQuery читає параметри фільтрації, сортування і пагінації каталогу (спільні для
+// /api/products і /api/categories/:slug/products). Якщо параметр некоректний — додає помилку і повертає false.
+
+func productQuery(c *gin.Context) (repositories.ProductFilter, bool, bool) {
 	limit := 20
 	offset := 0
 	if l := c.Query("limit"); l != "" {
@@ -85,73 +137,219 @@ func (h *ProductHandler) List(c *gin.Context) {
 			offset = v
 		}
 	}
-	items, total, err := h.svc.ListProducts(c.Request.Context(), limit, offset)
+
+	f := repositories.ProductFilter{
+		Category: c.Query("category"),
+		Query:    c.Query("q"),
+		Sort:     c.Query("sort"),
+		Limit:    limit,
+		Offset:   offset,
+	}
+	var ok bool
+	if f.MinPriceCents, ok = queryCents(c, "min_price"); !ok {
+		c.Error(invalidQuery("min_price"))
+		return f, false, false
+	}
+	if f.MaxPriceCents, ok = queryCents(c, "max_price"); !ok {
+		c.Error(invalidQuery("max_price"))
+		return f, false, false
+	}
+	if v := c.Query("in_stock"); v != "" {
+		inStock, err := strconv.ParseBool(v)
+		if err != nil {
+			c.Error(invalidQuery("in_stock"))
+			return f, false, false
+		}
+		f.InStock = inStock
+	}
+
+	cursor, cursorMode, err := queryCursor(c)
+	if err != nil {
+		c.Error(invalidQuery("cursor"))
+		return f, false, false
+	}
+	withTotal, ok := queryWithTotal(c, !cursorMode)
+	if !ok {
+		c.Error(invalidQuery("with_total"))
+		return f, false, false
+	}
+	f.SkipTotal = !withTotal
+	f.Cursor = cursor
+	f.Attributes = queryAttributes(c)
+	return f, cursorMode, true
+}
+
+// queryAttributes читає фільтри за атрибутами з параметрів attr.<name>[.min|.max] у стабільному порядку.
+// Значення лишаються рядками — тип і назву перевіряє сервіс за схемою атрибутів.
+
+func queryAttributes(c *gin.Context) []repositories.AttributeFilter {
+	params := c.Request.URL.Query()
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if strings.HasPrefix(key, "attr.") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var filters []repositories.AttributeFilter
+	for _, key := range keys {
+		a := repositories.AttributeFilter{Name: strings.TrimPrefix(key, "attr."), Op: repositories.AttrEq}
+		if name, ok := strings.CutSuffix(a.Name, ".min"); ok {
+			a.Name, a.Op = name, repositories.AttrMin
+		} else if name, ok := strings.CutSuffix(a.Name, ".max"); ok {
+			a.Name, a.Op = name, repositories.AttrMax
+		}
+		a.Value = params.Get(key)
+		filters = append(filters, a)
+	}
+	return filters
+}
+
+// respondProducts відповідає сторінкою продуктів: у режимі курсорів — pagination.Page,
+// інакше — items, limit, offset і total (якщо його рахували)
+
+func respondProducts(c *gin.Context, svc services.ProductService, f repositories.ProductFilter, cursorMode bool) {
+	if cursorMode {
+		page, err := svc.ListProductsPage(c.Request.Context(), f)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		jsonWithETag(c, page)
+		return
+	}
+
+	items, total, err := svc.ListProducts(c.Request.Context(), f)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"items": items, "total": total, "limit": limit, "offset": offset})
+	resp := gin.H{"items": items, "limit": f.Limit, "offset": f.Offset}
+	if !f.SkipTotal {
+		resp["total"] = total
+	}
+	jsonWithETag(c, resp)
+}
+
+// queryCents читає необов'язкову невід'ємну суму в копійках з query-параметра (nil — параметр відсутній)
+
+func queryCents(c *gin.Context, param string) (*int64, bool) {
+	v := c.Query(param)
+	if v == "" {
+		return nil, true
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return nil, false
+	}
+	return &n, true
 }
 
-// GetByID (Отримання продукту за ID)
+// GetByID (Отримання продукту за ID; ETag — версія продукту, If-None-Match → 304)
 
 func (h *ProductHandler) GetByID(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.Atoi(idParam)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+	id, ok := paramID(c, "id")
+	if !ok {
 		return
 	}
-	p, err := h.svc.GetProduct(c.Request.Context(), uint(id))
+	p, err := h.svc.GetProduct(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		c.Error(err)
+		return
+	}
+	etag := versionETag(p.Version)
+	c.Header("ETag", etag)
+	if notModified(c, etag) {
 		return
 	}
 	c.JSON(http.StatusOK, p)
 }
 
-// Update (Оновлення продукту)
+// Update (Оновлення продукту; If-Match — ETag версії, яку бачив клієнт)
 
 func (h *ProductHandler) Update(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.Atoi(idParam)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
-	
+
 	var req createProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(invalidBody(err))
 		return
 	}
 	p := &models.Product{
-		ID:          uint(id),
+		ID:          id,
 		Name:        req.Name,
 		Description: req.Description,
 		PriceCents:  req.PriceCents,
 		Stock:       req.Stock,
 		SKU:         req.SKU,
+		Attributes:  req.Attributes,
+		Version:     version,
+
+		ReorderPoint:    req.ReorderPoint,
+		ReorderQuantity: req.ReorderQuantity,
 	}
 	updated, err := h.svc.UpdateProduct(c.Request.Context(), p)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
+	c.Header("ETag", versionETag(updated.Version))
 	c.JSON(http.StatusOK, updated)
 }
 
-// Delete (Видалення продукту)
+// Patch (Часткове оновлення продукту — JSON Merge Patch, RFC 7396)
+// Тіло: лише поля, які потрібно змінити; null очищає поле. Відповідь — повний оновлений продукт.
+// If-Match — як в Update.
 
-func (h *ProductHandler) Delete(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.Atoi(idParam)
+func (h *ProductHandler) Patch(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	if ct := c.ContentType(); ct != mergepatch.ContentType && ct != gin.MIMEJSON {
+		c.Error(errUnsupportedMediaType.WithDetail("use " + mergepatch.ContentType))
+		return
+	}
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	updated, err := h.svc.PatchProduct(c.Request.Context(), id, patch, version)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		c.Error(err)
+		return
+	}
+	c.Header("ETag", versionETag(updated.Version))
+	c.JSON(http.StatusOK, updated)
+}
+
+// Delete (Видалення продукту; If-Match — як в Update)
+
+func (h *ProductHandler) Delete(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
-	if err := h.svc.DeleteProduct(c.Request.Context(), uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.svc.DeleteProduct(c.Request.Context(), id, version); err != nil {
+		c.Error(err)
 		return
 	}
 	c.Status(http.StatusNoContent)
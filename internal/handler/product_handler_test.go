@@ -0,0 +1,149 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/handler"
+	"github.com/AlexRijikov/go-petshop-api/internal/middleware"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// stubProductService — ProductService з одним продуктом; невикористані методи не реалізовані (nil embed)
+
+type stubProductService struct {
+	services.ProductService
+	product models.Product
+	filter  repositories.ProductFilter // фільтр останнього ListProducts
+}
+
+func (s *stubProductService) ListProducts(ctx context.Context, f repositories.ProductFilter) ([]models.Product, int64, error) {
+	s.filter = f
+	return []models.Product{s.product}, 1, nil
+}
+
+func (s *stubProductService) GetProduct(ctx context.Context, id uint) (*models.Product, error) {
+	if id != s.product.ID {
+		return nil, services.ErrNotFound
+	}
+	p := s.product
+	return &p, nil
+}
+
+func (s *stubProductService) PatchProduct(ctx context.Context, id uint, patch []byte, version uint) (*models.Product, error) {
+	if version != 0 && version != s.product.Version {
+		return nil, services.ErrVersionMismatch
+	}
+	s.product.Version++
+	p := s.product
+	return &p, nil
+}
+
+func setupProductRouter(svc services.ProductService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	handlers.NewProductHandler(svc).RegisterRoutes(r.Group("/api"))
+	return r
+}
+
+// Тест умовних запитів: ETag у GET, 304 для актуального кешу, 412 для застарілого If-Match
+
+func TestProductETag(t *testing.T) {
+	svc := &stubProductService{product: models.Product{ID: 1, Name: "Корм", PriceCents: 100, Version: 3}}
+	r := setupProductRouter(svc)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/products/1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/products/1", nil)
+	req.Header.Set("If-None-Match", `W/"3"`)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	patch := func(ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/api/products/1", bytes.NewBufferString(`{"stock": 2}`))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		req.Header.Set("If-Match", ifMatch)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w = patch(`"2"`)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"version_mismatch"`)
+
+	w = patch(`W/"3"`)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_if_match"`)
+
+	w = patch(`"3"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+
+	// Після зміни старий ETag вже не актуальний — повна відповідь замість 304
+	req = httptest.NewRequest(http.MethodGet, "/api/products/1", nil)
+	req.Header.Set("If-None-Match", `"3"`)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+}
+
+// stubCategoryService — дерево dogs → dog-food (ID 1 і 2)
+
+type stubCategoryService struct {
+	services.CategoryService
+}
+
+func (s stubCategoryService) SubtreeIDs(ctx context.Context, slug string) ([]uint, error) {
+	switch slug {
+	case "dogs":
+		return []uint{1, 2}, nil
+	case "dog-food":
+		return []uint{2}, nil
+	}
+	return nil, services.ErrCategoryNotFound
+}
+
+// Тест: продукти категорії включають підкатегорії, фільтри каталогу (і за атрибутами) передаються далі
+
+func TestCategoryProducts(t *testing.T) {
+	products := &stubProductService{product: models.Product{ID: 1, Name: "Корм", PriceCents: 100, Version: 1}}
+	r := setupProductRouter(products)
+	handlers.NewCategoryHandler(stubCategoryService{}, products).RegisterRoutes(r.Group("/api"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/categories/dogs/products?sort=price&in_stock=true", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []uint{1, 2}, products.filter.CategoryIDs)
+	assert.Equal(t, "price", products.filter.Sort)
+	assert.True(t, products.filter.InStock)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/products?attr.weight_kg.max=10&attr.species=dog&attr.weight_kg.min=2", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []repositories.AttributeFilter{
+		{Name: "species", Op: repositories.AttrEq, Value: "dog"},
+		{Name: "weight_kg", Op: repositories.AttrMax, Value: "10"},
+		{Name: "weight_kg", Op: repositories.AttrMin, Value: "2"},
+	}, products.filter.Attributes)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/categories/birds/products", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"category_not_found"`)
+}
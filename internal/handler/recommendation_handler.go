@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/auth"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// RecommendationHandler обробляє HTTP-запити персональних рекомендацій (/api/users/me/recommendations)
+
+type RecommendationHandler struct {
+	svc services.RecommendationService
+}
+
+// NewRecommendationHandler створює новий RecommendationHandler з наданим сервісом
+
+func NewRecommendationHandler(s services.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{svc: s}
+}
+
+// RegisterRoutes реєструє маршрут рекомендацій; він потребує авторизації (protect)
+
+func (h *RecommendationHandler) RegisterRoutes(rg *gin.RouterGroup, protect ...gin.HandlerFunc) {
+	rg.Group("/users/me", protect...).GET("/recommendations", h.List)
+}
+
+// List (Рекомендовані продукти для поточного користувача, ?limit= — до 50, за замовчуванням 10)
+
+func (h *RecommendationHandler) List(c *gin.Context) {
+	limit := 10
+	if l := c.Query("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 {
+			limit = v
+		}
+	}
+	items, err := h.svc.Recommend(c.Request.Context(), auth.UserID(c), limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
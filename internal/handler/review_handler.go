@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/auth"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewHandler обробляє HTTP-запити відгуків про продукти (/api/products/:id/reviews)
+// і їх модерацію (/api/admin/reviews)
+
+type ReviewHandler struct {
+	svc services.ReviewService
+}
+
+// NewReviewHandler створює новий ReviewHandler з наданим сервісом
+
+func NewReviewHandler(s services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{svc: s}
+}
+
+// RegisterRoutes реєструє маршрути відгуків. Читання схвалених відгуків публічне,
+// а власний відгук (/me) потребує авторизації (protect)
+
+func (h *ReviewHandler) RegisterRoutes(rg *gin.RouterGroup, protect ...gin.HandlerFunc) {
+	grp := rg.Group("/products/:id/reviews")
+	grp.GET("", h.List)
+
+	own := grp.Group("", protect...)
+	own.POST("", h.Create)
+	own.GET("/me", h.GetMine)
+	own.PUT("/me", h.UpdateMine)
+	own.DELETE("/me", h.DeleteMine)
+}
+
+// RegisterAdminRoutes реєструє маршрути модерації в групі адміністратора (/api/admin)
+
+func (h *ReviewHandler) RegisterAdminRoutes(rg *gin.RouterGroup) {
+	grp := rg.Group("/reviews")
+	grp.GET("", h.AdminList)
+	grp.PUT("/:id/moderation", h.Moderate)
+}
+
+// reviewFilter читає sort, limit і offset списку відгуків (limit за замовчуванням 20)
+
+func reviewFilter(c *gin.Context) repositories.ReviewFilter {
+	f := repositories.ReviewFilter{Sort: c.Query("sort"), Limit: 20}
+	if l := c.Query("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 {
+			f.Limit = v
+		}
+	}
+	if o := c.Query("offset"); o != "" {
+		if v, err := strconv.Atoi(o); err == nil && v >= 0 {
+			f.Offset = v
+		}
+	}
+	return f
+}
+
+// List (Схвалені відгуки продукту; sort: -created_at, created_at, rating, -rating)
+
+func (h *ReviewHandler) List(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	f := reviewFilter(c)
+	items, total, err := h.svc.ListProductReviews(c.Request.Context(), id, f)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total, "limit": f.Limit, "offset": f.Offset})
+}
+
+// Create (Відгук поточного користувача про продукт; публікується після модерації)
+
+func (h *ReviewHandler) Create(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req services.ReviewInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	rv, err := h.svc.CreateReview(c.Request.Context(), auth.UserID(c), id, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, rv)
+}
+
+// GetMine (Власний відгук про продукт у будь-якому стані модерації)
+
+func (h *ReviewHandler) GetMine(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	rv, err := h.svc.GetMyReview(c.Request.Context(), auth.UserID(c), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rv)
+}
+
+// UpdateMine (Зміна власного відгуку; відгук знову чекає модерації)
+
+func (h *ReviewHandler) UpdateMine(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req services.ReviewInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	rv, err := h.svc.UpdateMyReview(c.Request.Context(), auth.UserID(c), id, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rv)
+}
+
+// DeleteMine (Видалення власного відгуку)
+
+func (h *ReviewHandler) DeleteMine(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteMyReview(c.Request.Context(), auth.UserID(c), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AdminList (Відгуки для модерації; ?status=pending|approved|rejected, ?product_id=)
+
+func (h *ReviewHandler) AdminList(c *gin.Context) {
+	f := reviewFilter(c)
+	f.Status = c.Query("status")
+	if p := c.Query("product_id"); p != "" {
+		v, err := strconv.ParseUint(p, 10, 64)
+		if err != nil || v == 0 {
+			c.Error(invalidQuery("product_id"))
+			return
+		}
+		f.ProductID = uint(v)
+	}
+	items, total, err := h.svc.ListReviews(c.Request.Context(), f)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": total, "limit": f.Limit, "offset": f.Offset})
+}
+
+// Moderate (Схвалення або відхилення переглянутої версії відгуку за updated_at; 409, якщо автор її змінив)
+
+func (h *ReviewHandler) Moderate(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req services.ModerationInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	rv, err := h.svc.ModerateReview(c.Request.Context(), id, req, auth.UserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rv)
+}
@@ -6,38 +6,37 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/auth"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
 	"github.com/gin-gonic/gin"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // UserHandler відповідає за обробку HTTP-запитів, пов'язаних із користувачами (отримання профілю, оновлення профілю)
 
 type UserHandler struct {
-	repo repositories.UserRepository
+	svc services.UserService
 }
 
-// NewUserHandler створює новий екземпляр UserHandler з наданим репозиторієм користувачів (repository.UserRepository)
+// NewUserHandler створює новий екземпляр UserHandler з наданим сервісом користувачів (services.UserService)
 
-func NewUserHandler(r repositories.UserRepository) *UserHandler {
-	return &UserHandler{repo: r}
+func NewUserHandler(s services.UserService) *UserHandler {
+	return &UserHandler{svc: s}
 }
 
 // GetProfile — отримання профілю користувача
 
 func (h *UserHandler) GetProfile(c *gin.Context) {
-	userID := c.GetInt("user_id") // отримуємо user_id з контексту, встановленого AuthMiddleware
-
+	userID := auth.UserID(c) // отримуємо ID користувача з auth.Principal, встановленого AuthMiddleware
 	if userID == 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authorized"}) // якщо user_id відсутній, повертаємо 401 Unauthorized
+		c.Error(auth.ErrUnauthenticated) // якщо Principal відсутній, повертаємо 401 Unauthorized
 		return
 	}
 
-	// Отримуємо дані користувача з бази даних за допомогою репозиторію
+	// Отримуємо дані користувача за допомогою сервісу
 
-	user, err := h.repo.GetByID(uint(userID))
+	user, err := h.svc.GetProfile(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"}) // якщо користувача не знайдено, повертаємо 404 Not Found
+		c.Error(err) // якщо користувача не знайдено, сервіс повертає ErrUserNotFound (404 Not Found)
 		return
 	}
 
@@ -54,28 +53,29 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 // UpdateProfile — оновлення даних користувача (наприклад, email або username)
 
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
-	userID := c.GetInt("user_id") // отримуємо user_id з контексту, встановленого AuthMiddleware
+	userID := auth.UserID(c) // отримуємо ID користувача з auth.Principal, встановленого AuthMiddleware
 	if userID == 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authorized"}) // якщо user_id відсутній, повертаємо 401 Unauthorized
+		c.Error(auth.ErrUnauthenticated) // якщо Principal відсутній, повертаємо 401 Unauthorized
 		return
 	}
 
-	// Прив'язуємо вхідні дані (username, email) з JSON тіла запиту
+	// Прив'язуємо вхідні дані (username, email) з JSON тіла запиту; обидва поля обов'язкові —
+	// інакше порожній запит стер би email, за яким користувач входить
 
 	var req struct {
-		Username string `json:"username"`
-		Email    string `json:"email"`
+		Username string `json:"username" binding:"required,min=3,max=255"`
+		Email    string `json:"email" binding:"required,email,max=255"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"}) // якщо помилка прив'язки, повертаємо 400 Bad Request
+		c.Error(invalidBody(err)) // якщо помилка прив'язки, повертаємо 400 Bad Request
 		return
 	}
 
-	// Оновлюємо дані користувача в базі даних за допомогою репозиторію
+	// Оновлюємо дані користувача за допомогою сервісу
 
-	user, err := h.repo.UpdateProfile(uint(userID), req.Username, req.Email)
+	user, err := h.svc.UpdateProfile(c.Request.Context(), userID, req.Username, req.Email)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"}) // якщо сталася помилка при оновленні, повертаємо 500 Internal Server Error
+		c.Error(err) // username або email зайнятий — 409, інші помилки — 500
 		return
 	}
 
@@ -90,15 +90,44 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	})
 }
 
-// GetAllUsers отримує список усіх користувачів (для адміністраторів)
+// GetAllUsers отримує список усіх користувачів (для адміністраторів).
+// З параметром cursor (або pagination=cursor) повертає сторінку за id з next_cursor/prev_cursor;
+// limit — розмір сторінки (20 за замовчуванням), with_total=true — додати загальну кількість.
 
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second) // встановлюємо таймаут для контексту
-	defer cancel()                                                          // забезпечуємо скасування контексту після завершення функції
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second) // встановлюємо таймаут для контексту
+	defer cancel()                                                         // забезпечуємо скасування контексту після завершення функції
 
-	users, err := h.repo.GetAll(ctx) // отримуємо всіх користувачів з бази даних
+	cursor, cursorMode, err := queryCursor(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"}) // якщо сталася помилка при отриманні користувачів, повертаємо 500 Internal Server Error
+		c.Error(invalidQuery("cursor"))
+		return
+	}
+	if cursorMode {
+		limit := 20
+		if l := c.Query("limit"); l != "" {
+			if v, err := strconv.Atoi(l); err == nil && v > 0 {
+				limit = v
+			}
+		}
+		withTotal, ok := queryWithTotal(c, false)
+		if !ok {
+			c.Error(invalidQuery("with_total"))
+			return
+		}
+
+		page, err := h.svc.ListByCursor(ctx, cursor, limit, withTotal)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, page)
+		return
+	}
+
+	users, err := h.svc.List(ctx) // отримуємо всіх користувачів
+	if err != nil {
+		c.Error(err) // ErrorHandler поверне 500 без деталей помилки БД
 		return
 	}
 
@@ -108,20 +137,18 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 // DeleteUser видаляє користувача за його ID (для адміністраторів)
 
 func (h *UserHandler) DeleteUser(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := strconv.Atoi(idParam)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"}) // якщо ID некоректний, повертаємо 400 Bad Request
+	id, ok := paramID(c, "id") // якщо ID некоректний, повертаємо 400 Bad Request
+	if !ok {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second) // встановлюємо таймаут для контексту
-	defer cancel()                                                          // забезпечуємо скасування контексту після завершення функції
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second) // встановлюємо таймаут для контексту запиту
+	defer cancel()                                                         // забезпечуємо скасування контексту після завершення функції
 
-	// Викликаємо метод репозиторію для видалення користувача за його ID
+	// Викликаємо метод сервісу для видалення користувача за його ID
 
-	if err := h.repo.Delete(ctx, uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"}) // якщо сталася помилка при видаленні, повертаємо 500 Internal Server Error
+	if err := h.svc.Delete(ctx, id); err != nil {
+		c.Error(err) // якщо користувача не знайдено, сервіс повертає ErrUserNotFound (404 Not Found)
 		return
 	}
 
@@ -131,50 +158,28 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 // ChangePassword — зміна паролю користувача
 
 func (h *UserHandler) ChangePassword(c *gin.Context) {
-    userID := c.GetInt("user_id") // Отримуємо ID користувача з JWT middleware
-    if userID == 0 {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authorized"})
-        return
-    }
-
-    // Структура для отримання старого і нового паролю
-    var req struct {
-        OldPassword string `json:"old_password"`
-        NewPassword string `json:"new_password"`
-    }
-
-    if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
-        return
-    }
-
-    // Отримуємо користувача з бази
-    user, err := h.repo.GetByID(uint(userID))
-    if err != nil {
-        c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-        return
-    }
-
-    // Перевіряємо, чи старий пароль збігається
-    if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword)); err != nil {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "Incorrect old password"})
-        return
-    }
-
-    // Хешуємо новий пароль
-    hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
-        return
-    }
-
-    // Оновлюємо пароль у базі
-    if err := h.repo.UpdatePassword(uint(userID), string(hashedPassword)); err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
-        return
-    }
-
-    c.JSON(http.StatusOK, gin.H{"message": "Password updated successfully"})
-}
+	userID := auth.UserID(c) // Отримуємо ID користувача з auth.Principal (JWT middleware)
+	if userID == 0 {
+		c.Error(auth.ErrUnauthenticated)
+		return
+	}
 
+	// Структура для отримання старого і нового паролю (новий — за тими ж правилами, що при реєстрації)
+	var req struct {
+		OldPassword string `json:"old_password" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required,min=6"`
+	}
 
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+
+	// Сервіс перевіряє старий пароль і зберігає хеш нового (невірний старий пароль — 401)
+	if err := h.svc.ChangePassword(c.Request.Context(), userID, req.OldPassword, req.NewPassword); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password updated successfully"})
+}
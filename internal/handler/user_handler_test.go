@@ -0,0 +1,334 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/config"
+	"github.com/AlexRijikov/go-petshop-api/internal/handler"
+	"github.com/AlexRijikov/go-petshop-api/internal/keys"
+	"github.com/AlexRijikov/go-petshop-api/internal/middleware"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/pagination"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// Простий in-memory repo реалізує repositories.UserRepository для тестів.
+
+type memUserRepo struct {
+	data map[uint]*models.User
+}
+
+func (m *memUserRepo) Create(ctx context.Context, u *models.User) error {
+	u.ID = uint(len(m.data) + 1)
+	m.data[u.ID] = u
+	return nil
+}
+
+func (m *memUserRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	for _, u := range m.data {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memUserRepo) GetByUsername(username string) (*models.User, error) {
+	for _, u := range m.data {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memUserRepo) GetByID(id uint) (*models.User, error) {
+	u, ok := m.data[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return u, nil
+}
+
+func (m *memUserRepo) GetAll(ctx context.Context) ([]models.User, error) {
+	var out []models.User
+	for _, u := range m.data {
+		out = append(out, *u)
+	}
+	return out, nil
+}
+
+func (m *memUserRepo) ListByCursor(ctx context.Context, c *pagination.Cursor, limit int, withTotal bool) (*pagination.Page[models.User], error) {
+	var out []models.User
+	for _, u := range m.data {
+		if c == nil || (!c.Prev && u.ID > c.ID) || (c.Prev && u.ID < c.ID) {
+			out = append(out, *u)
+		}
+	}
+	backward := c != nil && c.Prev
+	sort.Slice(out, func(i, j int) bool { return (out[i].ID < out[j].ID) != backward })
+	if len(out) > limit+1 {
+		out = out[:limit+1]
+	}
+	page := pagination.BuildPage(out, c, limit, func(u models.User) pagination.Cursor {
+		return pagination.Cursor{ID: u.ID}
+	})
+	if withTotal {
+		total := int64(len(m.data))
+		page.Total = &total
+	}
+	return page, nil
+}
+
+func (m *memUserRepo) UpdatePassword(id uint, hashedPassword string) error {
+	m.data[id].Password = hashedPassword
+	return nil
+}
+
+func (m *memUserRepo) UpdateProfile(id uint, username, email string) (*models.User, error) {
+	u, ok := m.data[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	u.Username, u.Email = username, email
+	return u, nil
+}
+
+func (m *memUserRepo) Update(ctx context.Context, user *models.User) error {
+	m.data[user.ID] = user
+	return nil
+}
+
+func (m *memUserRepo) Delete(ctx context.Context, id uint) error {
+	delete(m.data, id)
+	return nil
+}
+
+// memTokenRepo — заглушка repositories.TokenRepository: UserService лише відкликає refresh токени при зміні пароля
+
+type memTokenRepo struct {
+	repositories.TokenRepository
+	revokedUsers []uint
+}
+
+func (m *memTokenRepo) RevokeAllRefreshTokens(ctx context.Context, userID uint) error {
+	m.revokedUsers = append(m.revokedUsers, userID)
+	return nil
+}
+
+// userTestEnv — роутер з реальним AuthMiddleware, менеджером ключів і in-memory користувачем
+
+type userTestEnv struct {
+	router *gin.Engine
+	repo   *memUserRepo
+	tokens *memTokenRepo
+	token  string
+}
+
+// newUserTestEnv реєструє маршрути /api/users/me так само, як routes.RegisterRoutes,
+// і видає токен з тими ж claims, що й AuthService.Login
+
+func newUserTestEnv(t *testing.T) *userTestEnv {
+	gin.SetMode(gin.TestMode)
+
+	km, err := keys.NewManager(config.JWTConfig{Algorithm: "HS256", Secret: "test-secret", AccessTTL: time.Minute})
+	require.NoError(t, err)
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	repo := &memUserRepo{data: map[uint]*models.User{}}
+	require.NoError(t, repo.Create(context.Background(), &models.User{
+		Username: "barsik", Email: "cat@example.com", Password: string(hashed), Role: "user",
+	}))
+
+	token, err := km.Sign(jwt.MapClaims{
+		"user_id": uint(1),
+		"role":    "user",
+		"jti":     "test-jti",
+		"exp":     time.Now().Add(time.Minute).Unix(),
+	})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	tokens := &memTokenRepo{}
+	userHandler := handlers.NewUserHandler(services.NewUserService(repo, tokens))
+	users := r.Group("/api/users")
+	users.Use(middleware.AuthMiddleware(km.Keyfunc, nil))
+	{
+		users.GET("/me", userHandler.GetProfile)
+		users.PUT("/me", userHandler.UpdateProfile)
+		users.PUT("/me/password", userHandler.ChangePassword)
+	}
+	r.GET("/api/admin/users", userHandler.GetAllUsers) // роль перевіряє RequireRole, тут — лише пагінація
+
+	return &userTestEnv{router: r, repo: repo, tokens: tokens, token: token}
+}
+
+// do виконує запит з токеном (якщо token не порожній) і повертає відповідь
+
+func (e *userTestEnv) do(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+	return w
+}
+
+// Тести для /api/users/me
+
+func TestGetProfile(t *testing.T) {
+	env := newUserTestEnv(t)
+
+	w := env.do(http.MethodGet, "/api/users/me", env.token, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, float64(1), resp["id"])
+	assert.Equal(t, "cat@example.com", resp["email"])
+}
+
+// Тест: без токена — 401 з кодом missing_token
+
+func TestGetProfileUnauthorized(t *testing.T) {
+	env := newUserTestEnv(t)
+
+	w := env.do(http.MethodGet, "/api/users/me", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"missing_token"`)
+}
+
+// Тест оновлення профілю
+
+func TestUpdateProfile(t *testing.T) {
+	env := newUserTestEnv(t)
+
+	w := env.do(http.MethodPut, "/api/users/me", env.token, map[string]string{
+		"username": "murzik", "email": "murzik@example.com",
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "murzik", env.repo.data[1].Username)
+}
+
+// Тест: порожні або некоректні username і email відхиляються з 400, профіль не змінюється
+
+func TestUpdateProfileValidation(t *testing.T) {
+	env := newUserTestEnv(t)
+
+	for _, body := range []map[string]string{
+		{},
+		{"username": "murzik"},
+		{"username": "murzik", "email": "not-an-email"},
+		{"username": "", "email": "murzik@example.com"},
+		{"username": "mu", "email": "murzik@example.com"},
+	} {
+		w := env.do(http.MethodPut, "/api/users/me", env.token, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	assert.Equal(t, "cat@example.com", env.repo.data[1].Email)
+}
+
+// Тест зміни пароля: невірний старий пароль — 401, правильний — 200
+
+func TestChangePassword(t *testing.T) {
+	env := newUserTestEnv(t)
+
+	w := env.do(http.MethodPut, "/api/users/me/password", env.token, map[string]string{
+		"old_password": "wrong", "new_password": "newsecret",
+	})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"code":"incorrect_password"`)
+
+	w = env.do(http.MethodPut, "/api/users/me/password", env.token, map[string]string{
+		"old_password": "secret123", "new_password": "newsecret",
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(env.repo.data[1].Password), []byte("newsecret")))
+	assert.Equal(t, []uint{1}, env.tokens.revokedUsers, "refresh токени відкликаються після зміни пароля")
+}
+
+// Тест: порожній або закороткий новий пароль відхиляється з 400 ще до перевірки старого
+
+func TestChangePasswordValidation(t *testing.T) {
+	env := newUserTestEnv(t)
+
+	for _, body := range []map[string]string{
+		{"old_password": "secret123"},
+		{"old_password": "secret123", "new_password": ""},
+		{"old_password": "secret123", "new_password": "12345"},
+		{"new_password": "newsecret"},
+	} {
+		w := env.do(http.MethodPut, "/api/users/me/password", env.token, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(env.repo.data[1].Password), []byte("secret123")))
+	assert.Empty(t, env.tokens.revokedUsers)
+}
+
+// Тест адмінського списку користувачів у режимі курсорів
+
+func TestGetAllUsersCursor(t *testing.T) {
+	env := newUserTestEnv(t)
+	for _, name := range []string{"murzik", "pushok"} {
+		require.NoError(t, env.repo.Create(context.Background(), &models.User{Username: name, Email: name + "@example.com"}))
+	}
+
+	type usersPage struct {
+		Items      []models.User `json:"items"`
+		NextCursor string        `json:"next_cursor"`
+		PrevCursor string        `json:"prev_cursor"`
+		Total      *int64        `json:"total"`
+	}
+	var page usersPage
+	w := env.do(http.MethodGet, "/api/admin/users?pagination=cursor&limit=2&with_total=true", env.token, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, uint(1), page.Items[0].ID)
+	assert.Empty(t, page.PrevCursor)
+	require.NotNil(t, page.Total)
+	assert.Equal(t, int64(3), *page.Total)
+	require.NotEmpty(t, page.NextCursor)
+
+	w = env.do(http.MethodGet, "/api/admin/users?limit=2&cursor="+page.NextCursor, env.token, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	page = usersPage{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "pushok", page.Items[0].Username)
+	assert.Empty(t, page.NextCursor)
+	assert.NotEmpty(t, page.PrevCursor)
+	assert.Nil(t, page.Total)
+
+	w = env.do(http.MethodGet, "/api/admin/users?cursor=not-a-cursor", env.token, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// limit обмежений сторінкою в 100 користувачів
+	w = env.do(http.MethodGet, "/api/admin/users?pagination=cursor&limit=1000000", env.token, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"limit":100`)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// VariantHandler обробляє HTTP-запити варіантів продукту (/api/products/:id/variants)
+
+type VariantHandler struct {
+	svc services.VariantService
+}
+
+// NewVariantHandler створює новий VariantHandler з наданим сервісом
+
+func NewVariantHandler(s services.VariantService) *VariantHandler {
+	return &VariantHandler{svc: s}
+}
+
+// RegisterRoutes реєструє маршрути варіантів: перегляд публічний, зміни — через protect (авторизація + роль admin)
+
+func (h *VariantHandler) RegisterRoutes(rg *gin.RouterGroup, protect ...gin.HandlerFunc) {
+	grp := rg.Group("/products/:id/variants")
+	grp.GET("", h.List)
+
+	write := grp.Group("", protect...)
+	write.POST("", h.Create)
+	write.PUT("/:variantId", h.Update)
+	write.DELETE("/:variantId", h.Delete)
+}
+
+// variantRequest — дані варіанту: артикул, атрибути ({"weight": "2 kg"}), ціна і залишок
+
+type variantRequest struct {
+	SKU        string            `json:"sku" binding:"required,max=100"`
+	Options    map[string]string `json:"options" binding:"omitempty,max=10"`
+	PriceCents int64             `json:"price_cents" binding:"required,gt=0"`
+	Stock      int               `json:"stock" binding:"gte=0"`
+}
+
+// toModel створює модель варіанту з запиту
+
+func (r variantRequest) toModel() models.ProductVariant {
+	return models.ProductVariant{SKU: r.SKU, Options: r.Options, PriceCents: r.PriceCents, Stock: r.Stock}
+}
+
+// List (Варіанти продукту)
+
+func (h *VariantHandler) List(c *gin.Context) {
+	productID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	items, err := h.svc.ListVariants(c.Request.Context(), productID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// Create (Додавання варіанту до продукту)
+
+func (h *VariantHandler) Create(c *gin.Context) {
+	productID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req variantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	v := req.toModel()
+	v.ProductID = productID
+	created, err := h.svc.CreateVariant(c.Request.Context(), &v)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// Update (Оновлення варіанту)
+
+func (h *VariantHandler) Update(c *gin.Context) {
+	productID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	id, ok := paramID(c, "variantId")
+	if !ok {
+		return
+	}
+	var req variantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	v := req.toModel()
+	v.ID, v.ProductID = id, productID
+	updated, err := h.svc.UpdateVariant(c.Request.Context(), &v)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// Delete (Видалення варіанту)
+
+func (h *VariantHandler) Delete(c *gin.Context) {
+	productID, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	id, ok := paramID(c, "variantId")
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteVariant(c.Request.Context(), productID, id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// WarehouseHandler обробляє HTTP-запити складських локацій (/api/warehouses)
+
+type WarehouseHandler struct {
+	svc services.WarehouseService
+}
+
+// NewWarehouseHandler створює новий WarehouseHandler з наданим сервісом
+
+func NewWarehouseHandler(s services.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{svc: s}
+}
+
+// RegisterRoutes реєструє маршрути локацій. Читання публічне (адреси магазинів),
+// а зміни (POST/PUT) проходять через protect (авторизація + роль admin)
+
+func (h *WarehouseHandler) RegisterRoutes(rg *gin.RouterGroup, protect ...gin.HandlerFunc) {
+	grp := rg.Group("/warehouses")
+	grp.GET("", h.List)
+	grp.GET("/:id", h.Get)
+
+	write := grp.Group("", protect...)
+	write.POST("", h.Create)
+	write.PUT("/:id", h.Update)
+}
+
+// warehouseRequest — дані локації для створення або оновлення; без active локація активна
+
+type warehouseRequest struct {
+	Code     string `json:"code" binding:"required,max=50"`
+	Name     string `json:"name" binding:"required,min=2,max=100"`
+	Kind     string `json:"kind" binding:"omitempty,oneof=warehouse store"`
+	Address  string `json:"address" binding:"omitempty,max=255"`
+	Priority int    `json:"priority" binding:"gte=0"` // менше значення — локація обирається першою при розподілі замовлень
+	Active   *bool  `json:"active"`
+}
+
+// warehouse будує модель локації із запиту
+
+func (r *warehouseRequest) warehouse(id uint) *models.Warehouse {
+	active := r.Active == nil || *r.Active
+	return &models.Warehouse{
+		ID: id, Code: r.Code, Name: r.Name, Kind: r.Kind, Address: r.Address, Priority: r.Priority, Active: active,
+	}
+}
+
+// List (Локації за пріоритетом; ?active=true — лише активні)
+
+func (h *WarehouseHandler) List(c *gin.Context) {
+	activeOnly := false
+	if v := c.Query("active"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			c.Error(invalidQuery("active"))
+			return
+		}
+		activeOnly = b
+	}
+	items, err := h.svc.ListWarehouses(c.Request.Context(), activeOnly)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// Get (Локація за ID)
+
+func (h *WarehouseHandler) Get(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	w, err := h.svc.GetWarehouse(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, w)
+}
+
+// Create (Створення локації)
+
+func (h *WarehouseHandler) Create(c *gin.Context) {
+	var req warehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	created, err := h.svc.CreateWarehouse(c.Request.Context(), req.warehouse(0))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// Update (Оновлення локації; active false — деактивація порожньої локації)
+
+func (h *WarehouseHandler) Update(c *gin.Context) {
+	id, ok := paramID(c, "id")
+	if !ok {
+		return
+	}
+	var req warehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(invalidBody(err))
+		return
+	}
+	updated, err := h.svc.UpdateWarehouse(c.Request.Context(), req.warehouse(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
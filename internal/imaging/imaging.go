@@ -0,0 +1,93 @@
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+
+	_ "image/gif" // реєструємо декодери форматів для image.Decode
+	_ "image/png"
+)
+
+// Обробка зображень продуктів лише стандартною бібліотекою: декодування з обмеженням
+// розміру (захист від "decompression bomb") і зменшені копії (мініатюри) у JPEG.
+
+// ErrTooManyPixels — зображення завелике для обробки (ширина × висота > ліміту)
+
+var ErrTooManyPixels = errors.New("image dimensions are too large")
+
+// Decode декодує JPEG, PNG або GIF; спершу читає лише заголовок і відхиляє зображення,
+// більші за maxPixels, щоб не виділяти пам'ять під гігантський растр
+
+func Decode(r io.ReadSeeker, maxPixels int) (image.Image, string, error) {
+	cfg, format, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, "", fmt.Errorf("invalid %s dimensions %dx%d", format, cfg.Width, cfg.Height)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, "", ErrTooManyPixels
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, "", err
+	}
+	return image.Decode(r)
+}
+
+// Thumbnail вписує зображення в квадрат size×size зі збереженням пропорцій.
+// Менші зображення не збільшуються. Зменшення — усередненням пікселів (box filter).
+
+func Thumbnail(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return src
+	}
+	dw, dh := size, size
+	if w >= h {
+		dh = max(1, h*size/w)
+	} else {
+		dw = max(1, w*size/h)
+	}
+
+	// Перетворюємо в RGBA (premultiplied alpha) — усереднення прозорих пікселів коректне
+	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*h/dh, max((y+1)*h/dh, y*h/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*w/dw, max((x+1)*w/dw, x*w/dw+1)
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride+x0*4 : sy*rgba.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += uint64(row[i])
+					g += uint64(row[i+1])
+					bl += uint64(row[i+2])
+					a += uint64(row[i+3])
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(bl / n), A: uint8(a / n)})
+		}
+	}
+	return dst
+}
+
+// EncodeJPEG кодує зображення в JPEG; прозорі ділянки (PNG, GIF) заливаються білим
+
+func EncodeJPEG(w io.Writer, img image.Image, quality int) error {
+	b := img.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, b.Min, draw.Over)
+	return jpeg.Encode(w, flat, &jpeg.Options{Quality: quality})
+}
@@ -0,0 +1,72 @@
+package imaging_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/imaging"
+)
+
+// encodePNG створює PNG w×h: ліва половина червона, права — прозора
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w/2; x++ {
+			img.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// Тест декодування: ліміт пікселів перевіряється до декодування растра
+
+func TestDecode(t *testing.T) {
+	data := encodePNG(t, 40, 20)
+
+	img, format, err := imaging.Decode(bytes.NewReader(data), 800)
+	require.NoError(t, err)
+	assert.Equal(t, "png", format)
+	assert.Equal(t, image.Rect(0, 0, 40, 20), img.Bounds())
+
+	_, _, err = imaging.Decode(bytes.NewReader(data), 799)
+	assert.ErrorIs(t, err, imaging.ErrTooManyPixels)
+
+	_, _, err = imaging.Decode(bytes.NewReader([]byte("not an image")), 800)
+	assert.Error(t, err)
+}
+
+// Тест мініатюр: пропорції зберігаються, малі зображення не збільшуються, прозорість — білий фон у JPEG
+
+func TestThumbnail(t *testing.T) {
+	img, _, err := imaging.Decode(bytes.NewReader(encodePNG(t, 400, 200)), 1<<20)
+	require.NoError(t, err)
+
+	thumb := imaging.Thumbnail(img, 100)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), thumb.Bounds())
+	assert.Equal(t, image.Rect(0, 0, 400, 200), imaging.Thumbnail(img, 1000).Bounds())
+	portrait, _, err := imaging.Decode(bytes.NewReader(encodePNG(t, 30, 300)), 1<<20)
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 10, 100), imaging.Thumbnail(portrait, 100).Bounds())
+
+	var buf bytes.Buffer
+	require.NoError(t, imaging.EncodeJPEG(&buf, thumb, 85))
+	out, err := jpeg.Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), out.Bounds())
+
+	r, g, b, _ := out.At(10, 25).RGBA()
+	assert.Greater(t, r>>8, uint32(200)) // червона половина
+	assert.Less(t, g>>8, uint32(60))
+	r, g, b, _ = out.At(90, 25).RGBA()
+	assert.Greater(t, r>>8+g>>8+b>>8, uint32(700)) // прозора половина стала білою
+}
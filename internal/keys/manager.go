@@ -0,0 +1,527 @@
+package keys
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/config"
+)
+
+// Помилки менеджера ключів
+
+var (
+	ErrUnknownKey    = errors.New("unknown signing key")                          // kid з токена не знайдено серед активних ключів
+	ErrUnexpectedAlg = errors.New("unexpected signing algorithm")                 // алгоритм токена не збігається з алгоритмом ключа
+	ErrNoKeyStore    = errors.New("key rotation requires a shared key directory") // ротація без JWT_KEYS_DIR розвела б репліки на різні ключі
+)
+
+// hmacPEMType — тип PEM блоку, в якому зберігається секрет HS256 у каталозі ключів
+
+const hmacPEMType = "HMAC SECRET KEY"
+
+// kidLayout — формат kid ключів, які створює ротація (час створення в UTC)
+
+const kidLayout = "20060102T150405.000000000"
+
+// Key — один ключ підпису JWT, ідентифікований kid
+
+type Key struct {
+	ID        string            // kid (заголовок JWT)
+	Method    jwt.SigningMethod // HS256, RS256 або EdDSA
+	Private   interface{}       // ключ для підпису ([]byte, *rsa.PrivateKey, ed25519.PrivateKey)
+	Public    interface{}       // ключ для перевірки ([]byte, *rsa.PublicKey, ed25519.PublicKey)
+	CreatedAt time.Time         // коли ключ з'явився у сховищі (час зміни файлу)
+	NotAfter  time.Time         // після цього часу ключ більше не перевіряє токени (нуль — безстроково)
+}
+
+// Manager — єдине місце зберігання ключів JWT для AuthService, AuthMiddleware та JWKS.
+// Підписує токени поточним ключем і перевіряє токени будь-яким активним ключем за kid.
+// Спільне сховище ключів — каталог JWT_KEYS_DIR (наприклад, спільний том для всіх реплік):
+// кожна репліка періодично перечитує його, а ротація лише додає туди новий kid.
+// Новий ключ спочатку публікується в JWKS і перевіряє токени, а підписує — лише через publishDelay,
+// коли його вже підхопили всі репліки; старі ключі перевіряють токени ще retention часу.
+
+type Manager struct {
+	mu           sync.RWMutex
+	keys         map[string]*Key
+	current      string            // kid ключа, яким підписуємо нові токени
+	method       jwt.SigningMethod // алгоритм для нових ключів при ротації
+	retention    time.Duration     // скільки старий ключ ще перевіряє токени (дорівнює часу життя access токена)
+	dir          string            // каталог спільного сховища ключів; порожній — ключі лише в пам'яті
+	signingKID   string            // kid, закріплений через JWT_SIGNING_KID
+	rotation     time.Duration     // як часто додавати новий ключ; 0 — без ротації
+	publishDelay time.Duration     // скільки новий ключ лише публікується, перш ніж ним почнуть підписувати
+}
+
+// NewManager створює Manager з конфігурації:
+// ключі з JWT_KEYS_DIR, або JWT_SECRET для HS256, або згенерований ключ для RS256/EdDSA.
+// Ротація (JWT_ROTATION_INTERVAL) можлива лише зі спільним каталогом ключів.
+
+func NewManager(cfg config.JWTConfig) (*Manager, error) {
+	method := jwt.GetSigningMethod(cfg.Algorithm)
+	if method == nil {
+		return nil, fmt.Errorf("непідтримуваний алгоритм JWT %q", cfg.Algorithm)
+	}
+	if cfg.RotationInterval > 0 && cfg.KeysDir == "" {
+		return nil, ErrNoKeyStore
+	}
+	m := &Manager{
+		keys:         map[string]*Key{},
+		method:       method,
+		retention:    cfg.AccessTTL,
+		dir:          cfg.KeysDir,
+		signingKID:   cfg.SigningKeyID,
+		rotation:     cfg.RotationInterval,
+		publishDelay: cfg.KeyPublishDelay,
+	}
+
+	switch {
+	case cfg.KeysDir != "":
+		if err := m.Sync(); err != nil {
+			return nil, err
+		}
+	case cfg.Algorithm == "HS256" && cfg.Secret != "":
+		m.add(&Key{ID: "default", Method: method, Private: []byte(cfg.Secret), Public: []byte(cfg.Secret), CreatedAt: time.Now()})
+	default:
+		// Ключ живе лише в пам'яті процесу: токени не переживають перезапуск і не перевіряються
+		// іншими репліками — підходить лише для розробки з одним екземпляром
+		log.Printf("JWT_KEYS_DIR не задано, генерую тимчасовий ключ %s", cfg.Algorithm)
+		key, err := generateKey(method, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		m.add(key)
+	}
+	return m, nil
+}
+
+// Sign підписує claims поточним ключем і додає kid у заголовок токена
+
+func (m *Manager) Sign(claims jwt.Claims) (string, error) {
+	m.mu.RLock()
+	key := m.keys[m.current]
+	m.mu.RUnlock()
+	if key == nil {
+		return "", ErrUnknownKey
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// Keyfunc повертає ключ перевірки для токена за kid (використовується в jwt.Parse).
+// Алгоритм токена має збігатися з алгоритмом ключа — це захищає від підміни alg.
+
+func (m *Manager) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	m.mu.RLock()
+	key, ok := m.keys[kid]
+	if !ok && kid == "" {
+		key, ok = m.keys["default"], m.keys["default"] != nil // токени, видані до появи kid
+	}
+	m.mu.RUnlock()
+
+	if !ok || (!key.NotAfter.IsZero() && time.Now().After(key.NotAfter)) {
+		return nil, ErrUnknownKey
+	}
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, ErrUnexpectedAlg
+	}
+	return key.Public, nil
+}
+
+// Rotate генерує новий ключ і додає його до спільного каталогу, після чого перечитує каталог.
+// Підписувати новим ключем Manager почне через publishDelay (див. Sync).
+
+func (m *Manager) Rotate() error {
+	if m.dir == "" {
+		return ErrNoKeyStore
+	}
+	if err := m.writeKey(time.Now()); err != nil {
+		return err
+	}
+	return m.Sync()
+}
+
+// Sync перечитує каталог ключів, за потреби додає новий ключ (ротація) і вибирає ключ для підпису.
+// Без каталогу нічого не робить.
+
+func (m *Manager) Sync() error {
+	if m.dir == "" {
+		return nil
+	}
+	now := time.Now()
+	keys, err := readDir(m.dir)
+	if err != nil {
+		return err
+	}
+
+	// Ротація: новий ключ, коли найновішому вже rotation часу. kid округлюється до інтервалу,
+	// тож репліки, що вирішили ротувати одночасно, пишуть той самий файл і лише одна з них перемагає
+
+	if m.rotation > 0 && (len(keys) == 0 || !now.Before(newest(keys).CreatedAt.Add(m.rotation))) {
+		if err := m.writeKey(now.Truncate(m.rotation)); err != nil {
+			return err
+		}
+		if keys, err = readDir(m.dir); err != nil {
+			return err
+		}
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("у каталозі %s немає *.pem ключів", m.dir)
+	}
+
+	current, err := m.pickCurrent(keys, now)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]*Key, len(keys))
+	for _, k := range keys {
+		byID[k.ID] = k
+	}
+	if m.rotation > 0 {
+		m.expire(keys, current, now, byID)
+	}
+
+	m.mu.Lock()
+	m.keys, m.current = byID, current
+	m.mu.Unlock()
+	return nil
+}
+
+// pickCurrent вибирає ключ для підпису: JWT_SIGNING_KID, або найновіший ключ,
+// що вже опублікований publishDelay часу. Якщо таких немає (щойно створений каталог),
+// підписує найстаріший ключ — його інші репліки найімовірніше вже знають.
+
+func (m *Manager) pickCurrent(keys []*Key, now time.Time) (string, error) {
+	if m.signingKID != "" {
+		for _, k := range keys {
+			if k.ID == m.signingKID {
+				return k.ID, nil
+			}
+		}
+		return "", fmt.Errorf("JWT_SIGNING_KID %q не знайдено в %s", m.signingKID, m.dir)
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		if !now.Before(keys[i].CreatedAt.Add(m.publishDelay)) {
+			return keys[i].ID, nil
+		}
+	}
+	oldest := keys[0]
+	for _, k := range keys[1:] {
+		if !k.CreatedAt.After(oldest.CreatedAt) {
+			oldest = k
+		}
+	}
+	return oldest.ID, nil
+}
+
+// expire виставляє NotAfter ключам, старшим за поточний: ключ перевіряє токени ще retention часу
+// після того, як наступний за ним почав підписувати. Прострочені ключі перестають перевіряти токени;
+// з каталогу видаляються лише ключі, створені ротацією, — файли оператора лишаються на місці.
+
+func (m *Manager) expire(keys []*Key, current string, now time.Time, byID map[string]*Key) {
+	for i := 0; i < len(keys)-1 && keys[i].ID != current; i++ {
+		next := keys[i+1]
+		keys[i].NotAfter = next.CreatedAt.Add(m.publishDelay).Add(m.retention)
+		if now.After(keys[i].NotAfter) {
+			delete(byID, keys[i].ID)
+			if !generatedKID(keys[i].ID) {
+				continue
+			}
+			if err := os.Remove(filepath.Join(m.dir, keys[i].ID+".pem")); err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Printf("Не вдалося видалити прострочений JWT ключ %s: %v", keys[i].ID, err)
+			}
+		}
+	}
+}
+
+// writeKey генерує ключ з kid за часом at і атомарно додає його до каталогу.
+// Існуючий файл з тим самим kid не перезаписується (його вже створила інша репліка).
+
+func (m *Manager) writeKey(at time.Time) error {
+	key, err := generateKey(m.method, at)
+	if err != nil {
+		return err
+	}
+	data, err := encodePrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(m.dir, ".jwt-key-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// Link не перезаписує існуючий файл, тож інші репліки ніколи не побачать частково записаний ключ
+
+	if err := os.Link(tmp.Name(), filepath.Join(m.dir, key.ID+".pem")); err != nil && !errors.Is(err, os.ErrExist) {
+		return err
+	}
+	return nil
+}
+
+// Start запускає періодичне перечитування каталогу ключів і ротацію до скасування ctx.
+// Без каталогу ключів нічого не робить.
+
+func (m *Manager) Start(ctx context.Context) {
+	if m.dir == "" {
+		return
+	}
+	period := m.publishDelay / 2
+	if period <= 0 || period > time.Minute {
+		period = time.Minute
+	}
+	go func() {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				prev := m.CurrentKeyID()
+				if err := m.Sync(); err != nil {
+					log.Printf("Помилка синхронізації JWT ключів: %v", err)
+					continue
+				}
+				if kid := m.CurrentKeyID(); kid != prev {
+					log.Printf("JWT ключ для підпису змінено, новий kid: %s", kid)
+				}
+			}
+		}
+	}()
+}
+
+// CurrentKeyID повертає kid ключа, яким зараз підписуються токени
+
+func (m *Manager) CurrentKeyID() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// JWK — публічний ключ у форматі JSON Web Key (RFC 7517)
+
+type JWK struct {
+	Kty string `json:"kty"`           // RSA або OKP
+	Kid string `json:"kid"`           // ідентифікатор ключа
+	Use string `json:"use"`           // sig — ключ для підпису
+	Alg string `json:"alg"`           // RS256 або EdDSA
+	N   string `json:"n,omitempty"`   // модуль RSA (base64url)
+	E   string `json:"e,omitempty"`   // експонента RSA (base64url)
+	Crv string `json:"crv,omitempty"` // крива OKP (Ed25519)
+	X   string `json:"x,omitempty"`   // публічний ключ Ed25519 (base64url)
+}
+
+// JWKSet — набір публічних ключів для /.well-known/jwks.json
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS повертає публічні ключі всіх активних асиметричних ключів.
+// HMAC ключі не публікуються — вони секретні.
+
+func (m *Manager) JWKS() JWKSet {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set := JWKSet{Keys: []JWK{}}
+	now := time.Now()
+	for _, k := range m.keys {
+		if !k.NotAfter.IsZero() && now.After(k.NotAfter) {
+			continue
+		}
+		switch pub := k.Public.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA", Kid: k.ID, Use: "sig", Alg: k.Method.Alg(),
+				N: base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP", Kid: k.ID, Use: "sig", Alg: k.Method.Alg(),
+				Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+// add додає ключ і робить його поточним (ключ без каталогу)
+
+func (m *Manager) add(k *Key) {
+	m.keys[k.ID] = k
+	m.current = k.ID
+}
+
+// readDir завантажує всі *.pem файли з каталогу, відсортовані за часом появи (CreatedAt, потім kid);
+// ім'я файлу без розширення стає kid, час зміни файлу — CreatedAt.
+// Порядок kid не означає порядок створення: ключ оператора може мати будь-яке ім'я
+
+func readDir(dir string) ([]*Key, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*Key, 0, len(files))
+	for _, f := range files {
+		kid := strings.TrimSuffix(filepath.Base(f), ".pem")
+		info, err := os.Stat(f)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		key, err := parsePrivateKey(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("ключ %s: %w", f, err)
+		}
+		key.CreatedAt = info.ModTime()
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys, nil
+}
+
+// generatedKID повідомляє, чи kid створений ротацією (час у форматі kidLayout), а не покладений оператором
+
+func generatedKID(kid string) bool {
+	_, err := time.Parse(kidLayout, kid)
+	return err == nil
+}
+
+// newest повертає ключ, що з'явився у сховищі останнім
+
+func newest(keys []*Key) *Key {
+	n := keys[0]
+	for _, k := range keys[1:] {
+		if k.CreatedAt.After(n.CreatedAt) {
+			n = k
+		}
+	}
+	return n
+}
+
+// parsePrivateKey розбирає PEM з приватним ключем RSA (PKCS#1/PKCS#8), Ed25519 (PKCS#8) або секретом HS256
+
+func parsePrivateKey(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("некоректний PEM")
+	}
+
+	var priv interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		priv, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		priv, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case hmacPEMType:
+		priv = block.Bytes
+	default:
+		return nil, fmt.Errorf("непідтримуваний тип PEM %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newKey(kid, priv)
+}
+
+// generateKey створює новий випадковий ключ для вказаного алгоритму з kid за часом at
+
+func generateKey(method jwt.SigningMethod, at time.Time) (*Key, error) {
+	kid := at.UTC().Format(kidLayout)
+
+	switch method.Alg() {
+	case "HS256":
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		return newKey(kid, secret)
+	case "RS256":
+		priv, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		return newKey(kid, priv)
+	case "EdDSA":
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return newKey(kid, priv)
+	}
+	return nil, fmt.Errorf("непідтримуваний алгоритм JWT %q", method.Alg())
+}
+
+// newKey визначає алгоритм за типом приватного ключа
+
+func newKey(kid string, priv interface{}) (*Key, error) {
+	k := &Key{ID: kid, Private: priv, CreatedAt: time.Now()}
+	switch p := priv.(type) {
+	case []byte:
+		k.Method, k.Public = jwt.SigningMethodHS256, p
+	case *rsa.PrivateKey:
+		k.Method, k.Public = jwt.SigningMethodRS256, &p.PublicKey
+	case ed25519.PrivateKey:
+		k.Method, k.Public = jwt.SigningMethodEdDSA, p.Public().(ed25519.PublicKey)
+	default:
+		return nil, fmt.Errorf("непідтримуваний тип ключа %T", priv)
+	}
+	return k, nil
+}
+
+// encodePrivateKey кодує ключ у PEM для каталогу ключів (PKCS#8 або секрет HS256)
+
+func encodePrivateKey(k *Key) ([]byte, error) {
+	if secret, ok := k.Private.([]byte); ok {
+		return pem.EncodeToMemory(&pem.Block{Type: hmacPEMType, Bytes: secret}), nil
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(k.Private)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
@@ -0,0 +1,200 @@
+package keys_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/config"
+	"github.com/AlexRijikov/go-petshop-api/internal/keys"
+)
+
+// signAndParse підписує токен менеджером і одразу перевіряє його через Keyfunc
+
+func signAndParse(t *testing.T, m *keys.Manager) (*jwt.Token, error) {
+	signed, err := m.Sign(jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+	return jwt.Parse(signed, m.Keyfunc)
+}
+
+// Тести для Manager: підпис і перевірка для кожного алгоритму
+
+func TestSignAndVerify(t *testing.T) {
+	for _, alg := range []string{"HS256", "RS256", "EdDSA"} {
+		t.Run(alg, func(t *testing.T) {
+			m, err := keys.NewManager(config.JWTConfig{Algorithm: alg, Secret: "secret", AccessTTL: time.Minute})
+			require.NoError(t, err)
+
+			token, err := signAndParse(t, m)
+			require.NoError(t, err)
+			assert.True(t, token.Valid)
+			assert.Equal(t, alg, token.Method.Alg())
+			assert.Equal(t, m.CurrentKeyID(), token.Header["kid"])
+		})
+	}
+}
+
+// Тест ротації: токен, підписаний старим ключем, і далі перевіряється
+
+func TestRotateKeepsPreviousKey(t *testing.T) {
+	m, err := keys.NewManager(config.JWTConfig{Algorithm: "EdDSA", KeysDir: t.TempDir(), RotationInterval: time.Hour, AccessTTL: time.Minute})
+	require.NoError(t, err)
+
+	oldKID := m.CurrentKeyID()
+	signed, err := m.Sign(jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+
+	time.Sleep(time.Millisecond) // kid — час створення ключа
+	require.NoError(t, m.Rotate())
+	assert.NotEqual(t, oldKID, m.CurrentKeyID())
+
+	_, err = jwt.Parse(signed, m.Keyfunc)
+	assert.NoError(t, err)
+	assert.Len(t, m.JWKS().Keys, 2)
+}
+
+// Тест спільного сховища: ключ, доданий однією реплікою, інша підхоплює з каталогу;
+// підписувати ним починають лише після JWT_KEY_PUBLISH_DELAY, а в JWKS він є одразу
+
+func TestRotateSharedKeyStore(t *testing.T) {
+	cfg := config.JWTConfig{Algorithm: "RS256", KeysDir: t.TempDir(), RotationInterval: 24 * time.Hour, KeyPublishDelay: time.Hour, AccessTTL: time.Minute}
+	a, err := keys.NewManager(cfg)
+	require.NoError(t, err)
+	b, err := keys.NewManager(cfg)
+	require.NoError(t, err)
+	oldKID := a.CurrentKeyID()
+	assert.Equal(t, oldKID, b.CurrentKeyID())
+
+	time.Sleep(time.Millisecond)
+	require.NoError(t, a.Rotate())
+	assert.Equal(t, oldKID, a.CurrentKeyID(), "новий ключ ще не опублікований достатньо довго")
+	require.Len(t, a.JWKS().Keys, 2)
+	newKID := a.JWKS().Keys[1].Kid
+
+	// Репліка B перечитує каталог і перевіряє токени нового ключа
+
+	require.NoError(t, b.Sync())
+	assert.Len(t, b.JWKS().Keys, 2)
+
+	// Після JWT_KEY_PUBLISH_DELAY обидві репліки підписують новим ключем, а токени старого ще дійсні
+
+	signed, err := a.Sign(jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+	past := time.Now().Add(-cfg.KeyPublishDelay - time.Second)
+	require.NoError(t, os.Chtimes(filepath.Join(cfg.KeysDir, newKID+".pem"), past, past))
+	for _, m := range []*keys.Manager{a, b} {
+		require.NoError(t, m.Sync())
+		assert.Equal(t, newKID, m.CurrentKeyID())
+		_, err = jwt.Parse(signed, m.Keyfunc)
+		assert.NoError(t, err)
+	}
+}
+
+// Тест: ключ оператора з kid, що за алфавітом іде після kid ротації, не лишається поточним назавжди
+// і не витісняє новіші ключі; після retention він перестає діяти, але файл оператора не видаляється
+
+func TestRotateMixedKeyNames(t *testing.T) {
+	cfg := config.JWTConfig{Algorithm: "RS256", KeysDir: t.TempDir(), RotationInterval: 24 * time.Hour, KeyPublishDelay: time.Hour, AccessTTL: time.Minute}
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	operatorFile := filepath.Join(cfg.KeysDir, "prod-2026.pem")
+	require.NoError(t, os.WriteFile(operatorFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)}), 0o600))
+	old := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(operatorFile, old, old))
+
+	// Ключ оператора старший за інтервал ротації — менеджер одразу додає новий, але підписує ще старим
+
+	m, err := keys.NewManager(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "prod-2026", m.CurrentKeyID())
+	signed, err := m.Sign(jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+	require.Len(t, m.JWKS().Keys, 2)
+	var rotatedKID string
+	for _, k := range m.JWKS().Keys {
+		if k.Kid != "prod-2026" {
+			rotatedKID = k.Kid
+		}
+	}
+	rotatedFile := filepath.Join(cfg.KeysDir, rotatedKID+".pem")
+
+	// Після JWT_KEY_PUBLISH_DELAY підписує ключ ротації, токени ключа оператора ще дійсні
+
+	published := time.Now().Add(-cfg.KeyPublishDelay - time.Second)
+	require.NoError(t, os.Chtimes(rotatedFile, published, published))
+	require.NoError(t, m.Sync())
+	assert.Equal(t, rotatedKID, m.CurrentKeyID())
+	assert.FileExists(t, rotatedFile)
+	_, err = jwt.Parse(signed, m.Keyfunc)
+	assert.NoError(t, err)
+
+	// Після retention ключ оператора перестає перевіряти токени, але його файл лишається
+
+	expired := time.Now().Add(-cfg.KeyPublishDelay - cfg.AccessTTL - time.Minute)
+	require.NoError(t, os.Chtimes(rotatedFile, expired, expired))
+	require.NoError(t, m.Sync())
+	assert.Equal(t, rotatedKID, m.CurrentKeyID())
+	_, err = jwt.Parse(signed, m.Keyfunc)
+	assert.ErrorIs(t, err, keys.ErrUnknownKey)
+	assert.FileExists(t, operatorFile)
+	assert.FileExists(t, rotatedFile)
+}
+
+// Тест: ротація без спільного каталогу ключів заборонена, Rotate без каталогу — помилка
+
+func TestRotationRequiresKeyStore(t *testing.T) {
+	_, err := keys.NewManager(config.JWTConfig{Algorithm: "HS256", Secret: "secret", RotationInterval: time.Hour, AccessTTL: time.Minute})
+	assert.ErrorIs(t, err, keys.ErrNoKeyStore)
+
+	m, err := keys.NewManager(config.JWTConfig{Algorithm: "HS256", Secret: "secret", AccessTTL: time.Minute})
+	require.NoError(t, err)
+	assert.ErrorIs(t, m.Rotate(), keys.ErrNoKeyStore)
+	assert.Equal(t, "default", m.CurrentKeyID())
+}
+
+// Тест: секрет HS256 з ротацією зберігається в каталозі й читається іншою реплікою
+
+func TestRotateHMACKeyStore(t *testing.T) {
+	cfg := config.JWTConfig{Algorithm: "HS256", KeysDir: t.TempDir(), RotationInterval: time.Hour, AccessTTL: time.Minute}
+	a, err := keys.NewManager(cfg)
+	require.NoError(t, err)
+	signed, err := a.Sign(jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+
+	b, err := keys.NewManager(cfg)
+	require.NoError(t, err)
+	_, err = jwt.Parse(signed, b.Keyfunc)
+	assert.NoError(t, err)
+	assert.Empty(t, b.JWKS().Keys)
+}
+
+// Тест: HMAC ключі не потрапляють у JWKS, а токен з іншим alg відхиляється
+
+func TestJWKSAndAlgMismatch(t *testing.T) {
+	hs, err := keys.NewManager(config.JWTConfig{Algorithm: "HS256", Secret: "secret", AccessTTL: time.Minute})
+	require.NoError(t, err)
+	assert.Empty(t, hs.JWKS().Keys)
+
+	rs, err := keys.NewManager(config.JWTConfig{Algorithm: "RS256", AccessTTL: time.Minute})
+	require.NoError(t, err)
+	assert.Len(t, rs.JWKS().Keys, 1)
+
+	// Токен HS256 з kid ключа RS256 не повинен пройти перевірку
+
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 1})
+	forged.Header["kid"] = rs.CurrentKeyID()
+	signed, err := forged.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	_, err = jwt.Parse(signed, rs.Keyfunc)
+	assert.ErrorIs(t, err, keys.ErrUnexpectedAlg)
+}
@@ -0,0 +1,77 @@
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// JSON Merge Patch (RFC 7396): патч — це JSON-документ з тими полями, які потрібно змінити.
+// Значення null видаляє поле, вкладені об'єкти зливаються рекурсивно, все інше замінюється.
+
+// ErrNotObject повертається, якщо патч не є JSON-об'єктом (часткове оновлення ресурсу неможливе)
+
+var ErrNotObject = errors.New("merge patch must be a JSON object")
+
+// ContentType — тип вмісту для запитів PATCH з JSON Merge Patch
+
+const ContentType = "application/merge-patch+json"
+
+// Apply застосовує patch до документа doc і повертає новий документ
+
+func Apply(doc, patch []byte) ([]byte, error) {
+	var target, p interface{}
+	if err := decode(doc, &target); err != nil {
+		return nil, err
+	}
+	if err := decode(patch, &p); err != nil {
+		return nil, err
+	}
+	if _, ok := p.(map[string]interface{}); !ok {
+		return nil, ErrNotObject
+	}
+	return json.Marshal(merge(target, p))
+}
+
+// Keys повертає імена полів верхнього рівня, які змінює patch
+
+func Keys(patch []byte) ([]string, error) {
+	var p map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, ErrNotObject
+	}
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// merge реалізує алгоритм MergePatch(Target, Patch) з RFC 7396, розділ 2
+
+func merge(target, patch interface{}) interface{} {
+	pm, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	tm, ok := target.(map[string]interface{})
+	if !ok {
+		tm = map[string]interface{}{}
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+			continue
+		}
+		tm[k] = merge(tm[k], v)
+	}
+	return tm
+}
+
+// decode розбирає JSON, зберігаючи числа як json.Number (без втрати точності великих цілих)
+
+func decode(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
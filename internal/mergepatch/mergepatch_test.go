@@ -0,0 +1,53 @@
+package mergepatch_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/mergepatch"
+)
+
+// Приклади з RFC 7396, додаток A (лише патчі-об'єкти)
+
+func TestApply(t *testing.T) {
+	cases := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{`{"price_cents":9007199254740993}`, `{"name":"x"}`, `{"name":"x","price_cents":9007199254740993}`},
+	}
+	for _, tc := range cases {
+		got, err := mergepatch.Apply([]byte(tc.doc), []byte(tc.patch))
+		require.NoError(t, err, tc.patch)
+		assert.JSONEq(t, tc.want, string(got), "%s + %s", tc.doc, tc.patch)
+	}
+}
+
+// Тест: патч, що не є об'єктом, відхиляється
+
+func TestApplyNotObject(t *testing.T) {
+	for _, patch := range []string{`["a"]`, `"text"`, `null`, `42`} {
+		_, err := mergepatch.Apply([]byte(`{"a":"b"}`), []byte(patch))
+		assert.ErrorIs(t, err, mergepatch.ErrNotObject, patch)
+	}
+}
+
+// Тест: Keys повертає поля верхнього рівня патча
+
+func TestKeys(t *testing.T) {
+	keys, err := mergepatch.Keys([]byte(`{"name":"x","meta":{"a":null}}`))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"name", "meta"}, keys)
+}
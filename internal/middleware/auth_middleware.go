@@ -1,25 +1,40 @@
 package middleware
 
 import (
-	"net/http"
+	"context"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/auth"
 )
 
-// Секретний ключ для підпису JWT токенів - (у реальному застосунку зберігайте його в безпечному місці )
+// Помилки автентифікації (рендеряться ErrorHandler як 401)
+
+var (
+	ErrMissingToken = apperr.Unauthorized("missing_token", "authorization header missing") // немає заголовка Authorization
+	ErrInvalidToken = apperr.Unauthorized("invalid_token", "invalid token")                // неправильний формат, підпис, термін дії або claims
+	ErrTokenRevoked = apperr.Unauthorized("token_revoked", "token revoked")                // токен відкликано через logout
+)
 
-var jwtKey = []byte("supersecretkey") // потім винесемо у .env
+// RevocationChecker перевіряє, чи access токен (за jti) було відкликано (реалізується AuthService)
+
+type RevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
 
 // AuthMiddleware перевіряє JWT токен в заголовку Authorization
-// Якщо токен дійсний, додає user_id в контекст запиту
+// Якщо токен дійсний і не відкликаний, додає auth.Principal в контекст запиту
+// keyfunc — повертає ключ перевірки підпису за kid (keys.Manager.Keyfunc)
+// revocations — список відкликаних токенів (nil вимикає перевірку)
 
-func AuthMiddleware() gin.HandlerFunc {
+func AuthMiddleware(keyfunc jwt.Keyfunc, revocations RevocationChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header missing"})
+			c.Error(ErrMissingToken)
 			c.Abort()
 			return
 		}
@@ -28,34 +43,85 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token format"})
+			c.Error(ErrInvalidToken)
 			c.Abort()
 			return
 		}
 
-		// Парсимо токен і перевіряємо його дійсність (підпис, термін дії тощо)
+		// Парсимо токен і перевіряємо його дійсність (підпис ключем з kid, алгоритм, термін дії тощо)
 
-		token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return jwtKey, nil
-		})
+		token, err := jwt.Parse(tokenString, keyfunc)
 
 		// Якщо токен недійсний або сталася помилка, повертаємо 401 Unauthorized
 
 		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Error(ErrInvalidToken)
 			c.Abort()
 			return
 		}
 
-		// Отримуємо claims і додаємо user_id в контекст запиту для подальшого використання в обробниках запитів (handlers)
+		// Будуємо типізований Principal з claims (user_id у JSON — це число float64)
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			c.Set("user_id", claims["user_id"])
+		principal, ok := principalFromClaims(token.Claims)
+		if !ok {
+			c.Error(ErrInvalidToken)
+			c.Abort()
+			return
+		}
+
+		// Перевіряємо, чи токен не було відкликано (logout або компрометація)
+
+		if revocations != nil {
+			if principal.TokenID == "" {
+				c.Error(ErrInvalidToken)
+				c.Abort()
+				return
+			}
+			revoked, err := revocations.IsTokenRevoked(c.Request.Context(), principal.TokenID)
+			if err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+			if revoked {
+				c.Error(ErrTokenRevoked)
+				c.Abort()
+				return
+			}
 		}
 
+		// Додаємо Principal у gin.Context і context.Context запиту для handlers та services
+
+		auth.SetPrincipal(c, principal)
+
 		c.Next()
 	}
 }
+
+// principalFromClaims перетворює JWT claims на auth.Principal.
+// Повертає false, якщо user_id відсутній або некоректний.
+
+func principalFromClaims(c jwt.Claims) (*auth.Principal, bool) {
+	claims, ok := c.(jwt.MapClaims)
+	if !ok {
+		return nil, false
+	}
+
+	// Після JSON-декодування числа мають тип float64
+
+	id, ok := claims["user_id"].(float64)
+	if !ok || id <= 0 || id != float64(uint(id)) {
+		return nil, false
+	}
+
+	p := &auth.Principal{UserID: uint(id)}
+	p.Role, _ = claims["role"].(string)
+	p.TokenID, _ = claims["jti"].(string)
+	if scope, ok := claims["scope"].(string); ok {
+		p.Scopes = strings.Fields(scope)
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		p.ExpiresAt = exp.Time
+	}
+	return p, true
+}
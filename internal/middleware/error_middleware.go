@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+)
+
+// Problem — тіло відповіді з помилкою у форматі RFC 7807 (application/problem+json).
+// Code — стабільний код помилки, за яким клієнти розрізняють випадки (не за текстом).
+
+type Problem struct {
+	Type     string `json:"type"`             // URI типу помилки (urn:petshop:problem:<code>)
+	Title    string `json:"title"`            // короткий опис типу помилки
+	Status   int    `json:"status"`           // HTTP-статус
+	Detail   string `json:"detail,omitempty"` // подробиці конкретного випадку
+	Instance string `json:"instance"`         // шлях запиту
+	Code     string `json:"code"`             // стабільний код помилки
+}
+
+// problemContentType — Content-Type відповідей з помилками
+
+const problemContentType = "application/problem+json"
+
+// ErrorHandler — центральний обробник помилок. Handlers і middleware додають помилку через
+// c.Error(err) і перериваються, а ErrorHandler після виконання ланцюжка відповідає problem+json.
+// Помилки, що не є *apperr.Error, логуються і повертаються як 500 без деталей.
+// Реєструється глобально першим: r.Use(middleware.ErrorHandler()).
+
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		p := problemFor(err)
+		p.Instance = c.Request.URL.Path
+		if p.Status == http.StatusInternalServerError {
+			log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		}
+
+		c.Header("Content-Type", problemContentType)
+		c.JSON(p.Status, p)
+	}
+}
+
+// problemFor будує Problem з помилки (без Instance)
+
+func problemFor(err error) Problem {
+	var ae *apperr.Error
+	if !errors.As(err, &ae) || ae.Kind == apperr.KindInternal {
+		return Problem{
+			Type:   "urn:petshop:problem:internal_error",
+			Title:  "internal server error",
+			Status: http.StatusInternalServerError,
+			Code:   "internal_error",
+		}
+	}
+	return Problem{
+		Type:   "urn:petshop:problem:" + ae.Code,
+		Title:  ae.Message,
+		Status: ae.Kind.Status(),
+		Detail: ae.Detail,
+		Code:   ae.Code,
+	}
+}
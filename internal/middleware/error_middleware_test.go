@@ -0,0 +1,70 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/middleware"
+)
+
+// serveError виконує запит до handler, який повертає err через c.Error
+
+func serveError(err error) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.GET("/things/:id", func(c *gin.Context) {
+		c.Error(err)
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/things/7", nil))
+	return w
+}
+
+// Тест: помилка домену рендериться як problem+json зі статусом і стабільним кодом
+
+func TestErrorHandlerDomainError(t *testing.T) {
+	errThingNotFound := apperr.NotFound("thing_not_found", "thing not found")
+
+	w := serveError(errThingNotFound.WithDetail("id 7"))
+	require.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	var p middleware.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Equal(t, middleware.Problem{
+		Type:     "urn:petshop:problem:thing_not_found",
+		Title:    "thing not found",
+		Status:   http.StatusNotFound,
+		Detail:   "id 7",
+		Instance: "/things/7",
+		Code:     "thing_not_found",
+	}, p)
+}
+
+// Тест: невідома помилка (наприклад, з SQL) — 500 без деталей для клієнта
+
+func TestErrorHandlerHidesInternalErrors(t *testing.T) {
+	w := serveError(errors.New(`pq: relation "products" does not exist`))
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "products")
+	assert.Contains(t, w.Body.String(), `"code":"internal_error"`)
+}
+
+// Тест: обгорнута помилка домену зберігає свій статус
+
+func TestErrorHandlerWrappedError(t *testing.T) {
+	errConflict := apperr.Conflict("thing_conflict", "thing changed")
+
+	w := serveError(errors.Join(errors.New("context"), errConflict))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"thing_conflict"`)
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/auth"
+)
+
+// Ролі користувачів (models.User.Role)
+
+const (
+	RoleUser  = "user"  // звичайний покупець
+	RoleAdmin = "admin" // адміністратор (керує каталогом і користувачами)
+)
+
+// ErrForbidden — роль користувача не дозволяє доступ (403)
+
+var ErrForbidden = apperr.Forbidden("insufficient_permissions", "insufficient permissions")
+
+// RequireRole дозволяє доступ лише користувачам з однією з вказаних ролей.
+// Має використовуватись після AuthMiddleware, який кладе auth.Principal (з роллю з JWT claims) у контекст.
+
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.Error(auth.ErrUnauthenticated)
+			c.Abort()
+			return
+		}
+
+		// Перевіряємо, чи роль користувача є серед дозволених
+
+		if !principal.HasRole(roles...) {
+			c.Error(ErrForbidden)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
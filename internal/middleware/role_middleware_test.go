@@ -0,0 +1,52 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/auth"
+	"github.com/AlexRijikov/go-petshop-api/internal/middleware"
+)
+
+// newRoleRouter створює тестовий роутер, де роль задається напряму (замість AuthMiddleware)
+
+func newRoleRouter(role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.GET("/admin", func(c *gin.Context) {
+		if role != "" {
+			auth.SetPrincipal(c, &auth.Principal{UserID: 1, Role: role})
+		}
+		c.Next()
+	}, middleware.RequireRole(middleware.RoleAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+// Тести для RequireRole
+
+func TestRequireRole(t *testing.T) {
+	cases := []struct {
+		name string
+		role string
+		want int
+	}{
+		{"admin allowed", middleware.RoleAdmin, http.StatusOK},
+		{"user forbidden", middleware.RoleUser, http.StatusForbidden},
+		{"no role unauthorized", "", http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			newRoleRouter(tc.role).ServeHTTP(w, req)
+			assert.Equal(t, tc.want, w.Code)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Типи атрибутів продукту
+
+const (
+	AttributeString  = "string"  // довільний рядок
+	AttributeNumber  = "number"  // число (вага, об'єм), підтримує фільтри min/max
+	AttributeBoolean = "boolean" // так/ні (grain_free)
+	AttributeEnum    = "enum"    // одне зі значень Values (species: dog, cat)
+)
+
+// AttributeDef — опис атрибута в схемі категорії.
+// Схема діє для продуктів категорії та всіх її підкатегорій (успадковується вниз по дереву).
+
+type AttributeDef struct {
+	Name     string   `json:"name"`             // ключ у Product.Attributes (snake_case: life_stage, weight_kg)
+	Label    string   `json:"label,omitempty"`  // назва для відображення (Вік тварини)
+	Type     string   `json:"type"`             // AttributeString, AttributeNumber, AttributeBoolean або AttributeEnum
+	Required bool     `json:"required"`         // продукт категорії має містити атрибут
+	Values   []string `json:"values,omitempty"` // допустимі значення для AttributeEnum
+}
+
+// AttributeSchema — схема атрибутів категорії; зберігається в jsonb
+
+type AttributeSchema []AttributeDef
+
+// Value серіалізує схему в JSON для БД (nil — порожній масив)
+
+func (s AttributeSchema) Value() (driver.Value, error) {
+	if s == nil {
+		return "[]", nil
+	}
+	return jsonValue(s)
+}
+
+// Scan читає схему з jsonb
+
+func (s *AttributeSchema) Scan(src interface{}) error {
+	*s = AttributeSchema{}
+	return scanJSON(src, s)
+}
+
+// Attributes — значення атрибутів продукту (назва → string, float64 або bool); зберігаються в jsonb
+
+type Attributes map[string]interface{}
+
+// Value серіалізує атрибути в JSON для БД (nil — порожній об'єкт)
+
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	return jsonValue(a)
+}
+
+// Scan читає атрибути з jsonb
+
+func (a *Attributes) Scan(src interface{}) error {
+	*a = Attributes{}
+	return scanJSON(src, a)
+}
+
+// jsonValue серіалізує значення в JSON-рядок для колонки json/jsonb
+
+func jsonValue(v interface{}) (driver.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// scanJSON читає колонку json/jsonb у dst (NULL залишає dst без змін)
+
+func scanJSON(src interface{}, dst interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dst)
+	case string:
+		return json.Unmarshal([]byte(v), dst)
+	}
+	return fmt.Errorf("json column: unsupported type %T", src)
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Cart — кошик користувача (один кошик на користувача).
+// TotalCents не зберігається в БД — сервіс рахує його з актуальних Product.PriceCents.
+
+type Cart struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`                     // Primary key (Первинний ключ)
+	CreatedAt  time.Time  `json:"created_at"`                               // Час створення кошика
+	UpdatedAt  time.Time  `json:"updated_at"`                               // Час останньої зміни кошика
+	UserID     uint       `gorm:"not null;uniqueIndex" json:"user_id"`      // Власник кошика (models.User.ID)
+	Items      []CartItem `gorm:"constraint:OnDelete:CASCADE" json:"items"` // Позиції кошика
+	TotalCents int64      `gorm:"-" json:"total_cents"`                     // Загальна сума в копійках (рахується сервісом)
+}
+
+// CartItem — позиція в кошику: продукт (і, якщо обрано, його варіант) та кількість.
+// Ціна не фіксується — вона береться з варіанту або продукту в момент перегляду кошика.
+
+type CartItem struct {
+	ID            uint            `gorm:"primaryKey" json:"id"`                                     // Primary key (Первинний ключ)
+	CreatedAt     time.Time       `json:"created_at"`                                               // Час додавання в кошик
+	UpdatedAt     time.Time       `json:"updated_at"`                                               // Час останньої зміни кількості
+	CartID        uint            `gorm:"not null;uniqueIndex:idx_cart_product" json:"-"`           // Кошик (Cart.ID)
+	ProductID     uint            `gorm:"not null;uniqueIndex:idx_cart_product" json:"product_id"`  // Продукт (Product.ID), один рядок на пару продукт + варіант
+	Product       Product         `gorm:"constraint:OnDelete:CASCADE" json:"product"`               // Продукт (підвантажується через Preload)
+	VariantID     *uint           `gorm:"uniqueIndex:idx_cart_product" json:"variant_id,omitempty"` // Варіант продукту (ProductVariant.ID); nil — продукт без варіанту
+	Variant       *ProductVariant `gorm:"constraint:OnDelete:CASCADE" json:"variant,omitempty"`     // Варіант (підвантажується через Preload)
+	Quantity      int             `gorm:"not null" json:"quantity"`                                 // Кількість одиниць
+	SubtotalCents int64           `gorm:"-" json:"subtotal_cents"`                                  // Ціна × кількість (рахується сервісом)
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Category — категорія каталогу з ієрархією (батьківська категорія → підкатегорії).
+// Slug — стабільний ідентифікатор для URL (/api/categories/dog-food/products), унікальний.
+// Продукти пов'язані з категоріями через таблицю product_categories (many-to-many).
+
+type Category struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`                                         // Primary key (Первинний ключ)
+	CreatedAt   time.Time       `json:"created_at"`                                                   // Час створення категорії
+	UpdatedAt   time.Time       `json:"updated_at"`                                                   // Час останньої зміни
+	Name        string          `gorm:"size:100;not null" json:"name"`                                // Назва для відображення (Корм для собак)
+	Slug        string          `gorm:"size:100;not null;uniqueIndex" json:"slug"`                    // Унікальний slug: малі латинські літери, цифри і дефіси (dog-food)
+	Description string          `gorm:"type:text" json:"description,omitempty"`                       // Опис категорії (опціонально)
+	ParentID    *uint           `gorm:"index" json:"parent_id"`                                       // Батьківська категорія (nil — коренева)
+	Attributes  AttributeSchema `gorm:"type:jsonb;not null;default:'[]'" json:"attributes,omitempty"` // Схема атрибутів продуктів категорії (успадковується підкатегоріями)
+	Children    []Category      `gorm:"foreignKey:ParentID" json:"children,omitempty"`                // Підкатегорії (заповнюються сервісом при побудові дерева)
+}
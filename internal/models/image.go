@@ -0,0 +1,44 @@
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// ProductImage — завантажене зображення продукту. Файли лежать у сховищі (storage.Storage):
+// оригінал за Key і мініатюри фіксованих розмірів поруч з ним. Position задає порядок показу,
+// перше зображення — головне (його URL дублюється в Product.ImageURL).
+
+type ProductImage struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`                                                              // Primary key (Первинний ключ)
+	CreatedAt   time.Time       `json:"created_at"`                                                                        // Час завантаження
+	ProductID   uint            `gorm:"not null;index:idx_product_images_product_id,priority:1" json:"product_id"`         // Продукт (Product.ID)
+	Position    int             `gorm:"not null;default:0;index:idx_product_images_product_id,priority:2" json:"position"` // Порядок показу (0 — головне зображення)
+	Key         string          `gorm:"size:255;not null" json:"-"`                                                        // Ключ оригіналу в сховищі
+	URL         string          `gorm:"size:255;not null" json:"url"`                                                      // Публічний URL оригіналу
+	ContentType string          `gorm:"size:50;not null" json:"content_type"`                                              // Визначений за вмістом тип (image/jpeg, image/png, image/gif)
+	SizeBytes   int64           `gorm:"not null" json:"size_bytes"`                                                        // Розмір оригіналу в байтах
+	Width       int             `gorm:"not null" json:"width"`                                                             // Ширина оригіналу в пікселях
+	Height      int             `gorm:"not null" json:"height"`                                                            // Висота оригіналу в пікселях
+	Thumbnails  ImageThumbnails `gorm:"type:jsonb;not null;default:'{}'" json:"thumbnails"`                                // URL мініатюр за назвою розміру (small, medium, large)
+}
+
+// ImageThumbnails — URL мініатюр (назва розміру → URL); зберігаються в jsonb
+
+type ImageThumbnails map[string]string
+
+// Value серіалізує мініатюри в JSON для БД (nil — порожній об'єкт)
+
+func (t ImageThumbnails) Value() (driver.Value, error) {
+	if t == nil {
+		return "{}", nil
+	}
+	return jsonValue(map[string]string(t))
+}
+
+// Scan читає мініатюри з jsonb
+
+func (t *ImageThumbnails) Scan(src interface{}) error {
+	*t = ImageThumbnails{}
+	return scanJSON(src, (*map[string]string)(t))
+}
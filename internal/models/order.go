@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// Статуси замовлення
+
+const (
+	OrderStatusPending   = "pending"   // створено, товар зарезервовано, очікує оплати
+	OrderStatusPaid      = "paid"      // оплачено
+	OrderStatusPacked    = "packed"    // зібрано на складі
+	OrderStatusShipped   = "shipped"   // передано службі доставки
+	OrderStatusDelivered = "delivered" // доставлено покупцю
+	OrderStatusCancelled = "cancelled" // скасовано, резерв повернуто на склад
+	OrderStatusRefunded  = "refunded"  // кошти повернуто
+)
+
+// Order — замовлення користувача.
+// Ціни та назви продуктів фіксуються в OrderItem на момент покупки (snapshot),
+// тому подальші зміни каталогу не впливають на вже оформлені замовлення.
+
+type Order struct {
+	ID         uint                 `gorm:"primaryKey" json:"id"`                                   // Primary key (Первинний ключ)
+	CreatedAt  time.Time            `json:"created_at"`                                             // Час оформлення замовлення
+	UpdatedAt  time.Time            `json:"updated_at"`                                             // Час останньої зміни
+	UserID     uint                 `gorm:"not null;index" json:"user_id"`                          // Покупець (models.User.ID)
+	Status     string               `gorm:"size:20;not null;default:'pending';index" json:"status"` // Статус замовлення
+	TotalCents int64                `gorm:"not null" json:"total_cents"`                            // Загальна сума в копійках
+	Items      []OrderItem          `gorm:"constraint:OnDelete:CASCADE" json:"items"`               // Позиції замовлення
+	History    []OrderStatusHistory `gorm:"constraint:OnDelete:CASCADE" json:"history,omitempty"`   // Історія змін статусу (підвантажується при перегляді одного замовлення)
+}
+
+// OrderItem — позиція замовлення зі знімком назви, артикулу та ціни продукту на момент покупки
+
+type OrderItem struct {
+	ID             uint   `gorm:"primaryKey" json:"id"`                  // Primary key (Первинний ключ)
+	OrderID        uint   `gorm:"not null;index" json:"-"`               // Замовлення (Order.ID)
+	ProductID      uint   `gorm:"not null;index" json:"product_id"`      // Продукт (Product.ID)
+	VariantID      *uint  `gorm:"index" json:"variant_id,omitempty"`     // Варіант продукту (ProductVariant.ID); артикул і ціна тоді — з варіанту
+	ProductName    string `gorm:"size:255;not null" json:"product_name"` // Назва продукту на момент покупки
+	SKU            string `gorm:"size:100" json:"sku,omitempty"`         // Артикул продукту або варіанту на момент покупки
+	UnitPriceCents int64  `gorm:"not null" json:"unit_price_cents"`      // Ціна за одиницю на момент покупки
+	Quantity       int    `gorm:"not null" json:"quantity"`              // Кількість одиниць
+	SubtotalCents  int64  `gorm:"not null" json:"subtotal_cents"`        // Ціна × кількість
+	WarehouseID    *uint  `gorm:"index" json:"warehouse_id,omitempty"`   // Локація, з якої зарезервовано товар (для варіантів — nil: залишок варіанту не поділено на локації)
+}
+
+// OrderStatusHistory — запис про зміну статусу замовлення: хто, коли, з якого статусу в який
+
+type OrderStatusHistory struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`              // Primary key (Первинний ключ)
+	CreatedAt  time.Time `json:"created_at"`                        // Час зміни статусу
+	OrderID    uint      `gorm:"not null;index" json:"-"`           // Замовлення (Order.ID)
+	FromStatus string    `gorm:"size:20" json:"from_status"`        // Попередній статус (порожній при створенні замовлення)
+	ToStatus   string    `gorm:"size:20;not null" json:"to_status"` // Новий статус
+	ChangedBy  uint      `gorm:"not null" json:"changed_by"`        // Хто змінив (models.User.ID)
+	Note       string    `gorm:"size:500" json:"note,omitempty"`    // Коментар (наприклад, номер ТТН)
+}
+
+// TableName — таблиця order_status_history (замість множини за замовчуванням GORM)
+
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
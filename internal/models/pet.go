@@ -0,0 +1,58 @@
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// Види тварин, яких можна додати в профіль (збігаються зі значеннями атрибута продукту species)
+
+const (
+	SpeciesDog     = "dog"     // собака
+	SpeciesCat     = "cat"     // кішка
+	SpeciesBird    = "bird"    // птах
+	SpeciesFish    = "fish"    // риба
+	SpeciesRodent  = "rodent"  // гризун (хом'як, щур, морська свинка)
+	SpeciesRabbit  = "rabbit"  // кролик
+	SpeciesReptile = "reptile" // рептилія
+)
+
+// PetSpecies — відомі види тварин у порядку відображення
+
+var PetSpecies = []string{SpeciesDog, SpeciesCat, SpeciesBird, SpeciesFish, SpeciesRodent, SpeciesRabbit, SpeciesReptile}
+
+// Pet — тварина покупця (models.User). Профіль допомагає підбирати товари:
+// вид і вік — для корму та аксесуарів, алергії — щоб не пропонувати небезпечні інгредієнти.
+
+type Pet struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`                  // Primary key (Первинний ключ)
+	CreatedAt time.Time  `json:"created_at"`                            // Час створення
+	UpdatedAt time.Time  `json:"updated_at"`                            // Час останньої зміни
+	UserID    uint       `gorm:"not null;index" json:"-"`               // Власник (User.ID)
+	Name      string     `gorm:"size:50;not null" json:"name"`          // Кличка
+	Species   string     `gorm:"size:20;not null" json:"species"`       // Вид (PetSpecies)
+	Breed     string     `gorm:"size:100" json:"breed,omitempty"`       // Порода
+	BirthDate *time.Time `gorm:"type:date" json:"birth_date,omitempty"` // Дата народження (nil — невідома)
+	WeightKg  float64    `gorm:"type:numeric(6,2)" json:"weight_kg"`    // Вага в кілограмах (0 — невідома)
+	Allergies StringList `gorm:"type:jsonb;not null" json:"allergies"`  // Алергени в нижньому регістрі (chicken, grain)
+}
+
+// StringList — список рядків; зберігається в jsonb
+
+type StringList []string
+
+// Value серіалізує список в JSON для БД (nil — порожній масив)
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	return jsonValue(l)
+}
+
+// Scan читає список з jsonb
+
+func (l *StringList) Scan(src interface{}) error {
+	*l = StringList{}
+	return scanJSON(src, l)
+}
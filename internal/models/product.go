@@ -22,9 +22,20 @@ type Product struct {
 	PriceCents  int64          `gorm:"not null" json:"price_cents"`               // ціна в центі (копійки) (щоб уникнути float)
 	Stock       int            `gorm:"not null;default:0" json:"stock"`           // Кількість на складі (Stock - для відстеження кількості продуктів на складі)
 	SKU         string         `gorm:"size:100;uniqueIndex" json:"sku,omitempty"` // Унікальний артикул (Stock Keeping Unit - для відстеження запасів продуктів  в системі управління запасами або ERP  системі  наприклад SAP, Oracle і т.д.)
+	ReorderPoint    int        `gorm:"not null;default:0" json:"reorder_point"`    // Поріг дозамовлення: при Stock <= ReorderPoint надсилається сповіщення (0 — не відстежується)
+	ReorderQuantity int        `gorm:"not null;default:0" json:"reorder_quantity"` // Скільки одиниць рекомендовано дозамовити
 	ImageURL    string         `gorm:"size:255" json:"image_url,omitempty"`       // URL зображення продукту (опціонально - для відображення зображення продукту )
 	Category    string         `gorm:"size:100" json:"category,omitempty"`        // Категорія продукту (опціонально- для фільтрації та сортування  продуктів за категоріями наприклад корм, сушені смаколики і т.д. )
 	Metadata    string         `gorm:"type:json" json:"metadata,omitempty"`       // Додаткові метадані у форматі JSON (опціонально - для розширення інформації про продукт наприклад колір, розмір і т.д.)
+	Version     uint           `gorm:"not null;default:1" json:"version"`         // Версія запису для оптимістичного блокування (збільшується при кожній зміні, з неї будується ETag)
+	Categories  []Category     `gorm:"many2many:product_categories" json:"categories,omitempty"` // Категорії таксономії (many-to-many через product_categories); поле Category — застарілий вільний текст
+	Variants    []ProductVariant `gorm:"constraint:OnDelete:CASCADE" json:"variants,omitempty"` // Варіанти продукту (розмір, вага, смак) з власними артикулом, ціною і залишком
+	Attributes  Attributes     `gorm:"type:jsonb;not null;default:'{}'" json:"attributes,omitempty"` // Типізовані атрибути за схемою категорій (species, life_stage, weight_kg, grain_free); замінюють Metadata
+	Images      []ProductImage `gorm:"constraint:OnDelete:CASCADE" json:"images,omitempty"` // Завантажені зображення в порядку показу; URL першого дублюється в ImageURL
+	Availability []WarehouseStock `gorm:"constraint:OnDelete:CASCADE" json:"availability,omitempty"` // Залишки в активних локаціях (неактивні не продаються)
+	SellableStock int          `gorm:"-" json:"sellable_stock"`                     // Скільки можна продати: сума залишків активних локацій (рахує репозиторій з Availability)
+	RatingAvg   float64        `gorm:"type:numeric(3,2);not null;default:0" json:"rating_avg"` // Середня оцінка схвалених відгуків (0 — відгуків немає)
+	RatingCount int            `gorm:"not null;default:0" json:"rating_count"`                  // Кількість схвалених відгуків
 	
 
 
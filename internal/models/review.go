@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Стани модерації відгуку
+
+const (
+	ReviewPending  = "pending"  // очікує модерації (новий або змінений автором)
+	ReviewApproved = "approved" // опубліковано, враховується в рейтингу продукту
+	ReviewRejected = "rejected" // відхилено модератором
+)
+
+// Review — відгук покупця про продукт: оцінка 1–5 і текст. Один відгук на користувача і продукт.
+// Публікуються і враховуються в Product.RatingAvg / RatingCount лише схвалені відгуки.
+
+type Review struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`                                               // Primary key (Первинний ключ)
+	CreatedAt        time.Time  `json:"created_at"`                                                         // Час створення
+	UpdatedAt        time.Time  `json:"updated_at"`                                                         // Час останньої зміни
+	ProductID        uint       `gorm:"not null;uniqueIndex:idx_reviews_product_user" json:"product_id"`    // Продукт (models.Product.ID)
+	UserID           uint       `gorm:"not null;uniqueIndex:idx_reviews_product_user;index" json:"user_id"` // Автор (models.User.ID)
+	Username         string     `gorm:"->;-:migration" json:"username"`                                     // Ім'я автора (лише читання, з users)
+	Rating           int        `gorm:"not null" json:"rating"`                                             // Оцінка від 1 до 5
+	Text             string     `gorm:"type:text;not null" json:"text"`                                     // Текст відгуку
+	VerifiedPurchase bool       `gorm:"not null" json:"verified_purchase"`                                  // Автор купував продукт (оплачене замовлення)
+	Status           string     `gorm:"size:20;not null;default:'pending';index" json:"status"`             // Стан модерації
+	ModerationNote   string     `gorm:"size:500" json:"moderation_note,omitempty"`                          // Коментар модератора (причина відхилення)
+	ModeratedBy      *uint      `json:"moderated_by,omitempty"`                                             // Модератор (User.ID)
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty"`                                             // Час модерації
+}
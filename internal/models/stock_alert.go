@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// StockAlert — надіслане сповіщення про низький залишок продукту. Поки запис існує, повторне
+// сповіщення не надсилається; запис видаляється, коли залишок знову перевищує поріг дозамовлення.
+
+type StockAlert struct {
+	ProductID    uint      `gorm:"primaryKey" json:"product_id"`  // Продукт (Product.ID)
+	CreatedAt    time.Time `json:"created_at"`                    // Коли залишок опустився до порогу
+	Stock        int       `gorm:"not null" json:"stock"`         // Залишок на момент сповіщення
+	ReorderPoint int       `gorm:"not null" json:"reorder_point"` // Поріг на момент сповіщення
+}
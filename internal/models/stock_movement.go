@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Типи руху товару на складі
+
+const (
+	StockReceipt    = "receipt"    // надходження від постачальника (+)
+	StockSale       = "sale"       // продаж, зокрема оформлене замовлення (−)
+	StockReturn     = "return"     // повернення на склад, зокрема скасоване замовлення (+)
+	StockAdjustment = "adjustment" // коригування за інвентаризацією або імпортом (±)
+	StockDamage     = "damage"     // списання пошкодженого чи простроченого товару (−)
+	StockTransfer   = "transfer"   // переміщення між локаціями: пара рухів (− у джерелі, + у призначенні)
+)
+
+// StockMovement — запис журналу складу: на скільки і чому змінився Product.Stock.
+// Журнал лише доповнюється (UPDATE і DELETE заборонені тригером), тому за ним можна
+// відновити залишок продукту на будь-який момент часу.
+
+type StockMovement struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`                                                       // Primary key (Первинний ключ)
+	CreatedAt   time.Time `gorm:"index:idx_stock_movements_product_id,priority:2" json:"created_at"`          // Час руху
+	ProductID   uint      `gorm:"not null;index:idx_stock_movements_product_id,priority:1" json:"product_id"` // Продукт (Product.ID)
+	Type        string    `gorm:"size:20;not null" json:"type"`                                               // receipt, sale, return, adjustment, damage
+	Quantity    int       `gorm:"not null" json:"quantity"`                                                   // Зміна залишку зі знаком (продаж і списання — від'ємні)
+	StockAfter  int       `gorm:"not null" json:"stock_after"`                                                // Залишок продукту після руху
+	Reason      string    `gorm:"size:500" json:"reason,omitempty"`                                           // Причина (обов'язкова для коригування і списання)
+	ActorID     uint      `gorm:"not null;default:0" json:"actor_id"`                                         // Хто змінив (models.User.ID; 0 — системний запис)
+	OrderID     *uint     `gorm:"index" json:"order_id,omitempty"`                                            // Замовлення, якщо рух спричинило оформлення або скасування
+	WarehouseID *uint     `gorm:"index" json:"warehouse_id,omitempty"`                                        // Локація, де змінився залишок (nil — записи до появи локацій)
+}
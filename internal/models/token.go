@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// RefreshToken — довгоживучий токен для отримання нових access токенів.
+// У базі зберігається лише SHA-256 хеш токена, сам токен бачить тільки клієнт.
+// При кожному оновленні (rotation) старий токен відкликається і замінюється новим.
+
+type RefreshToken struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`                  // Primary key (Первинний ключ)
+	CreatedAt  time.Time  `json:"created_at"`                            // Час видачі токена
+	UserID     uint       `gorm:"not null;index" json:"user_id"`         // Власник токена (models.User.ID)
+	TokenHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"` // SHA-256 хеш токена (hex)
+	ExpiresAt  time.Time  `gorm:"not null;index" json:"expires_at"`      // Час закінчення дії
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`                  // Час відкликання (nil — токен активний)
+	ReplacedBy *uint      `json:"replaced_by,omitempty"`                 // ID токена, який замінив цей під час rotation
+}
+
+// RevokedToken — запис у списку відкликаних access токенів (за jti).
+// Зберігається до закінчення дії токена, після чого може бути видалений.
+
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey;size:64" json:"jti"`    // Унікальний ідентифікатор JWT (claim "jti")
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"` // Коли токен і так перестане бути дійсним
+	CreatedAt time.Time `json:"created_at"`                       // Час відкликання
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// ProductVariant — варіант продукту (розмір, вага, смак): власний артикул, ціна і залишок.
+// Options — атрибути варіанту ({"weight": "2 kg", "flavour": "chicken"}), унікальні в межах продукту.
+
+type ProductVariant struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`                            // Primary key (Первинний ключ)
+	CreatedAt  time.Time      `json:"created_at"`                                      // Час створення варіанту
+	UpdatedAt  time.Time      `json:"updated_at"`                                      // Час останньої зміни
+	ProductID  uint           `gorm:"not null;index" json:"product_id"`                // Продукт (Product.ID)
+	SKU        string         `gorm:"size:100;not null;uniqueIndex" json:"sku"`        // Унікальний артикул варіанту
+	Options    VariantOptions `gorm:"type:jsonb;not null;default:'{}'" json:"options"` // Атрибути варіанту (назва → значення)
+	PriceCents int64          `gorm:"not null" json:"price_cents"`                     // Ціна варіанту в копійках (> 0)
+	Stock      int            `gorm:"not null;default:0" json:"stock"`                 // Залишок варіанту на складі
+}
+
+// VariantOptions — атрибути варіанту; зберігаються в jsonb
+
+type VariantOptions map[string]string
+
+// Value серіалізує атрибути в JSON для БД (nil — порожній об'єкт)
+
+func (o VariantOptions) Value() (driver.Value, error) {
+	if o == nil {
+		return "{}", nil
+	}
+	return jsonValue(map[string]string(o))
+}
+
+// Scan читає атрибути з jsonb
+
+func (o *VariantOptions) Scan(src interface{}) error {
+	*o = VariantOptions{}
+	return scanJSON(src, (*map[string]string)(o))
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Типи складських локацій
+
+const (
+	WarehouseKindWarehouse = "warehouse" // склад без продажу на місці
+	WarehouseKindStore     = "store"     // фізичний магазин
+)
+
+// Warehouse — локація, де зберігається товар (склад або магазин).
+// Priority задає порядок, у якому локації обираються для замовлень (менше — раніше);
+// основний склад — активна локація з найменшим Priority, туди потрапляють рухи без явної локації.
+
+type Warehouse struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`                     // Primary key (Первинний ключ)
+	CreatedAt time.Time `json:"created_at"`                               // Час створення
+	UpdatedAt time.Time `json:"updated_at"`                               // Час останньої зміни
+	Code      string    `gorm:"size:50;not null;uniqueIndex" json:"code"` // Унікальний короткий код (main, store-podil)
+	Name      string    `gorm:"size:100;not null" json:"name"`            // Назва локації
+	Kind      string    `gorm:"size:20;not null" json:"kind"`             // warehouse або store
+	Address   string    `gorm:"size:255" json:"address,omitempty"`        // Адреса
+	Priority  int       `gorm:"not null" json:"priority"`                 // Порядок вибору для замовлень (менше — раніше)
+	Active    bool      `gorm:"not null" json:"active"`                   // Неактивна локація не отримує товар і не обслуговує замовлення
+}
+
+// WarehouseStock — залишок продукту в одній локації. Product.Stock — сума залишків усіх локацій;
+// обидва змінюються разом лише рухами журналу складу.
+
+type WarehouseStock struct {
+	WarehouseID uint       `gorm:"primaryKey" json:"warehouse_id"`                          // Локація (Warehouse.ID)
+	ProductID   uint       `gorm:"primaryKey;index" json:"-"`                               // Продукт (Product.ID)
+	Quantity    int        `gorm:"not null" json:"quantity"`                                // Кількість у локації
+	UpdatedAt   time.Time  `json:"-"`                                                       // Час останньої зміни
+	Warehouse   *Warehouse `gorm:"constraint:OnDelete:RESTRICT" json:"warehouse,omitempty"` // Локація (підвантажується з продуктом)
+}
+
+// TableName — таблиця warehouse_stock (замість множини за замовчуванням GORM)
+
+func (WarehouseStock) TableName() string {
+	return "warehouse_stock"
+}
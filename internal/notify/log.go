@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"context"
+	"log"
+)
+
+// Log записує сповіщення в журнал сервера — варіант за замовчуванням і для локальної розробки
+
+type Log struct {
+	logger *log.Logger
+}
+
+// NewLog створює Log; nil — стандартний логер пакета log
+
+func NewLog(logger *log.Logger) *Log {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &Log{logger: logger}
+}
+
+// NotifyLowStock пише по рядку на кожен продукт
+
+func (l *Log) NotifyLowStock(ctx context.Context, alerts []LowStockAlert) error {
+	for _, a := range alerts {
+		l.logger.Printf("Низький залишок: %q (id %d, sku %q): %d шт. при порозі %d, дозамовити %d",
+			a.Name, a.ProductID, a.SKU, a.Stock, a.ReorderPoint, a.ReorderQuantity)
+	}
+	return nil
+}
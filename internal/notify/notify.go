@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/config"
+)
+
+// Сповіщення адміністраторів магазину про події складу. Сервіси знають лише інтерфейс Notifier;
+// реалізації: Log (журнал сервера) і Webhook (HTTP POST на зовнішню адресу — Slack, CRM тощо).
+
+// LowStockAlert — продукт, залишок якого опустився до порогу дозамовлення
+
+type LowStockAlert struct {
+	ProductID       uint      `json:"product_id"`
+	Name            string    `json:"name"`
+	SKU             string    `json:"sku,omitempty"`
+	Stock           int       `json:"stock"`            // поточний залишок
+	ReorderPoint    int       `json:"reorder_point"`    // поріг дозамовлення
+	ReorderQuantity int       `json:"reorder_quantity"` // рекомендована кількість дозамовлення
+	DetectedAt      time.Time `json:"detected_at"`      // коли виявлено перетин порогу
+}
+
+// Notifier доставляє сповіщення; помилка означає, що сповіщення не доставлено і його варто повторити
+
+type Notifier interface {
+	NotifyLowStock(ctx context.Context, alerts []LowStockAlert) error
+}
+
+// New створює Notifier за конфігурацією (ALERT_NOTIFIER)
+
+func New(cfg config.AlertsConfig) (Notifier, error) {
+	switch cfg.Notifier {
+	case "log":
+		return NewLog(nil), nil
+	case "webhook":
+		return NewWebhook(cfg.WebhookURL, cfg.WebhookSecret, cfg.WebhookTimeout), nil
+	}
+	return nil, fmt.Errorf("unsupported notifier %q", cfg.Notifier)
+}
@@ -0,0 +1,69 @@
+package notify_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/notify"
+)
+
+var alerts = []notify.LowStockAlert{{ProductID: 1, Name: "Корм", SKU: "CAT-1", Stock: 2, ReorderPoint: 5, ReorderQuantity: 20}}
+
+// Тест webhook: JSON з подією і сповіщеннями, підпис тіла секретом
+
+func TestWebhook(t *testing.T) {
+	var body []byte
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+	}))
+	defer srv.Close()
+
+	require.NoError(t, notify.NewWebhook(srv.URL, "secret", time.Second).NotifyLowStock(context.Background(), alerts))
+	assert.Equal(t, notify.EventLowStock, header.Get(notify.EventHeader))
+	assert.Equal(t, "sha256="+notify.Sign("secret", body), header.Get(notify.SignatureHeader))
+
+	var payload struct {
+		Event  string                 `json:"event"`
+		Alerts []notify.LowStockAlert `json:"alerts"`
+	}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "low_stock", payload.Event)
+	require.Len(t, payload.Alerts, 1)
+	assert.Equal(t, 20, payload.Alerts[0].ReorderQuantity)
+}
+
+// Тест: відповідь не 2xx — помилка доставки; без секрету підпис не додається
+
+func TestWebhookFailure(t *testing.T) {
+	var signature string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(notify.SignatureHeader)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	err := notify.NewWebhook(srv.URL, "", time.Second).NotifyLowStock(context.Background(), alerts)
+	assert.ErrorContains(t, err, "503")
+	assert.Empty(t, signature)
+}
+
+// Тест Log: рядок на кожен продукт
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, notify.NewLog(log.New(&buf, "", 0)).NotifyLowStock(context.Background(), alerts))
+	assert.Contains(t, buf.String(), `"Корм"`)
+	assert.Contains(t, buf.String(), "дозамовити 20")
+}
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Заголовки запиту webhook
+
+const (
+	EventHeader     = "X-Petshop-Event"     // тип події (low_stock)
+	SignatureHeader = "X-Petshop-Signature" // sha256=<hex HMAC-SHA256 тіла з секретом>, якщо секрет задано
+)
+
+// EventLowStock — тип події про низький залишок
+
+const EventLowStock = "low_stock"
+
+// Webhook надсилає сповіщення JSON-запитом POST на задану адресу.
+// Отримувач перевіряє підпис тіла спільним секретом; відповідь не 2xx вважається помилкою доставки.
+
+type Webhook struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhook створює Webhook; timeout обмежує весь запит (0 — без обмеження)
+
+func NewWebhook(url, secret string, timeout time.Duration) *Webhook {
+	return &Webhook{url: url, secret: secret, client: &http.Client{Timeout: timeout}}
+}
+
+// webhookPayload — тіло запиту webhook
+
+type webhookPayload struct {
+	Event  string          `json:"event"`
+	SentAt time.Time       `json:"sent_at"`
+	Alerts []LowStockAlert `json:"alerts"`
+}
+
+// NotifyLowStock надсилає всі сповіщення одним запитом
+
+func (w *Webhook) NotifyLowStock(ctx context.Context, alerts []LowStockAlert) error {
+	body, err := json.Marshal(webhookPayload{Event: EventLowStock, SentAt: time.Now().UTC(), Alerts: alerts})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, EventLowStock)
+	if w.secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(w.secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // дочитуємо, щоб з'єднання повернулося в пул
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook: unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign повертає hex HMAC-SHA256 тіла — отримувач порівнює його із заголовком SignatureHeader
+
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
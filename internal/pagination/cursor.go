@@ -0,0 +1,95 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+)
+
+// ErrInvalidCursor повертається, якщо курсор пошкоджений або не відповідає поточному сортуванню
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor — позиція в упорядкованому списку для keyset-пагінації.
+// Для клієнта це непрозорий рядок (base64url JSON), який він повертає в ?cursor=.
+
+type Cursor struct {
+	Sort string `json:"s,omitempty"` // сортування, для якого створено курсор
+	Key  string `json:"k,omitempty"` // значення ключа сортування останнього/першого рядка
+	ID   uint   `json:"id"`          // ID рядка — другий ключ для стабільного порядку
+	Prev bool   `json:"p,omitempty"` // true — сторінка перед цим рядком (prev_cursor)
+}
+
+// Encode перетворює курсор на непрозорий рядок для відповіді API
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode розбирає рядок курсора з запиту
+
+func Decode(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Page — сторінка результатів у режимі курсорів.
+// NextCursor/PrevCursor порожні, якщо в цьому напрямку більше немає записів.
+// Total заповнюється лише на запит (COUNT(*) дорогий для великих таблиць).
+
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Limit      int    `json:"limit"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// BuildPage формує сторінку з результату keyset-запиту.
+// items — до limit+1 рядків у порядку запиту (для c.Prev — у зворотному порядку);
+// зайвий рядок означає, що в цьому напрямку є ще записи. cursorFor будує курсор для рядка.
+
+func BuildPage[T any](items []T, c *Cursor, limit int, cursorFor func(T) Cursor) *Page[T] {
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+	backward := c != nil && c.Prev
+	if backward {
+		slices.Reverse(items)
+	}
+	if items == nil {
+		items = []T{}
+	}
+
+	page := &Page[T]{Items: items, Limit: limit}
+	if len(items) == 0 {
+		return page
+	}
+
+	// Рухаючись вперед, наступна сторінка є, якщо є зайвий рядок, а попередня — якщо запит був з курсором.
+	// Рухаючись назад — навпаки: ми прийшли з наступної сторінки, а зайвий рядок означає попередню.
+
+	hasNext, hasPrev := hasMore, c != nil
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+	if hasNext {
+		page.NextCursor = cursorFor(items[len(items)-1]).Encode()
+	}
+	if hasPrev {
+		prev := cursorFor(items[0])
+		prev.Prev = true
+		page.PrevCursor = prev.Encode()
+	}
+	return page
+}
@@ -0,0 +1,29 @@
+package pagination_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/pagination"
+)
+
+// Тест: курсор переживає кодування і декодування без змін
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := pagination.Cursor{Sort: "-price", Key: "1999", ID: 42, Prev: true}
+
+	got, err := pagination.Decode(c.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, c, *got)
+}
+
+// Тест: пошкоджений курсор або курсор без ID — ErrInvalidCursor
+
+func TestDecodeInvalidCursor(t *testing.T) {
+	for _, s := range []string{"not base64!", "bm90IGpzb24", pagination.Cursor{Key: "x"}.Encode()} {
+		_, err := pagination.Decode(s)
+		assert.ErrorIs(t, err, pagination.ErrInvalidCursor, s)
+	}
+}
@@ -0,0 +1,270 @@
+package recommend
+
+// Персональний підбір товарів. Модель будується з каталогу та історії замовлень і живе в пам'яті
+// до наступного оновлення; запит користувача лише ранжує вже підготовлені дані.
+//
+// Оцінка продукту складається з:
+//   - збігу виду тварини (атрибут species) і віку (атрибут life_stage) з профілями тварин;
+//   - спільних покупок: як часто продукт купували разом з тим, що вже купував користувач;
+//   - загальної популярності (вирішує, коли інших сигналів немає).
+// Товари з алергенами тварини (атрибут allergens), товари для видів, яких у користувача немає,
+// товари не в наявності та вже куплені користувачем не рекомендуються.
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+)
+
+// Атрибути продукту, які використовує підбір
+
+const (
+	AttrSpecies   = "species"    // вид тварини (dog, cat …); порожній або all — для всіх
+	AttrLifeStage = "life_stage" // junior (puppy, kitten), adult, senior; порожній або all — для будь-якого віку
+	AttrAllergens = "allergens"  // інгредієнти-алергени через кому (chicken, grain)
+)
+
+// Вікові групи тварин
+
+const (
+	StageJunior = "junior" // до року
+	StageAdult  = "adult"  // від року до семи
+	StageSenior = "senior" // від семи років
+)
+
+// Причини рекомендації (поле reasons відповіді)
+
+const (
+	ReasonSpecies        = "species"         // товар для виду однієї з тварин
+	ReasonLifeStage      = "life_stage"      // товар для віку однієї з тварин
+	ReasonBoughtTogether = "bought_together" // часто купують разом з покупками користувача
+	ReasonPopular        = "popular"         // популярний товар
+)
+
+// Ваги складових оцінки
+
+const (
+	weightSpecies        = 3.0
+	weightLifeStage      = 2.0
+	weightBoughtTogether = 4.0
+	weightPopular        = 1.0
+)
+
+// stageAliases — синоніми вікових груп у значеннях атрибута life_stage
+
+var stageAliases = map[string]string{
+	"puppy": StageJunior, "kitten": StageJunior, StageJunior: StageJunior,
+	StageAdult: StageAdult, StageSenior: StageSenior,
+}
+
+// Item — ознаки продукту для підбору
+
+type Item struct {
+	ProductID uint
+	Species   string   // "" — товар для всіх видів
+	LifeStage string   // StageJunior, StageAdult, StageSenior; "" — для будь-якого віку
+	Allergens []string // у нижньому регістрі
+	InStock   bool
+}
+
+// Pet — ознаки тварини користувача
+
+type Pet struct {
+	Species   string
+	Stage     string   // вікова група; "" — вік невідомий
+	Allergies []string // у нижньому регістрі
+}
+
+// Profile — усе, що відомо про користувача на момент запиту
+
+type Profile struct {
+	Pets      []Pet
+	Purchased []uint // продукти з попередніх замовлень
+}
+
+// Scored — продукт з оцінкою і причинами
+
+type Scored struct {
+	ProductID uint
+	Score     float64
+	Reasons   []string
+}
+
+// Model — незмінний знімок каталогу і статистики покупок; безпечний для одночасного читання
+
+type Model struct {
+	BuiltAt    time.Time
+	items      []Item                // у порядку ProductID
+	copurchase map[uint]map[uint]int // скільки замовлень містили обидва продукти
+	popularity map[uint]int          // у скількох замовленнях є продукт
+	maxPopular int
+}
+
+// ItemFromProduct витягує ознаки продукту з його атрибутів
+
+func ItemFromProduct(p models.Product) Item {
+	item := Item{ProductID: p.ID, InStock: p.SellableStock > 0}
+	if s, ok := p.Attributes[AttrSpecies].(string); ok && !strings.EqualFold(s, "all") {
+		item.Species = strings.ToLower(strings.TrimSpace(s))
+	}
+	if s, ok := p.Attributes[AttrLifeStage].(string); ok {
+		item.LifeStage = stageAliases[strings.ToLower(strings.TrimSpace(s))]
+	}
+	if s, ok := p.Attributes[AttrAllergens].(string); ok {
+		for _, a := range strings.Split(s, ",") {
+			if a = strings.ToLower(strings.TrimSpace(a)); a != "" {
+				item.Allergens = append(item.Allergens, a)
+			}
+		}
+	}
+	return item
+}
+
+// StageAt повертає вікову групу тварини, народженої birth, на момент now ("" — дата невідома)
+
+func StageAt(birth *time.Time, now time.Time) string {
+	if birth == nil {
+		return ""
+	}
+	switch {
+	case birth.AddDate(1, 0, 0).After(now):
+		return StageJunior
+	case birth.AddDate(7, 0, 0).After(now):
+		return StageAdult
+	}
+	return StageSenior
+}
+
+// Build будує модель з ознак продуктів і кошиків замовлень (продукти одного замовлення)
+
+func Build(items []Item, baskets [][]uint) *Model {
+	m := &Model{
+		BuiltAt:    time.Now(),
+		items:      append([]Item(nil), items...),
+		copurchase: map[uint]map[uint]int{},
+		popularity: map[uint]int{},
+	}
+	sort.Slice(m.items, func(i, j int) bool { return m.items[i].ProductID < m.items[j].ProductID })
+
+	for _, basket := range baskets {
+		seen := make(map[uint]bool, len(basket))
+		var unique []uint
+		for _, id := range basket {
+			if !seen[id] {
+				seen[id] = true
+				unique = append(unique, id)
+			}
+		}
+		for i, a := range unique {
+			m.popularity[a]++
+			if m.popularity[a] > m.maxPopular {
+				m.maxPopular = m.popularity[a]
+			}
+			for _, b := range unique[i+1:] {
+				m.addPair(a, b)
+				m.addPair(b, a)
+			}
+		}
+	}
+	return m
+}
+
+// addPair збільшує лічильник спільних покупок a → b
+
+func (m *Model) addPair(a, b uint) {
+	if m.copurchase[a] == nil {
+		m.copurchase[a] = map[uint]int{}
+	}
+	m.copurchase[a][b]++
+}
+
+// Rank повертає до limit найкращих продуктів для профілю (найвища оцінка першою, за рівності — менший ID)
+
+func (m *Model) Rank(p Profile, limit int) []Scored {
+	purchased := make(map[uint]bool, len(p.Purchased))
+	for _, id := range p.Purchased {
+		purchased[id] = true
+	}
+
+	// Спільні покупки: сума лічильників з усіма купленими продуктами, нормована на максимум
+
+	together := map[uint]int{}
+	maxTogether := 0
+	for id := range purchased {
+		for other, n := range m.copurchase[id] {
+			together[other] += n
+			if together[other] > maxTogether {
+				maxTogether = together[other]
+			}
+		}
+	}
+
+	var out []Scored
+	for _, item := range m.items {
+		if !item.InStock || purchased[item.ProductID] {
+			continue
+		}
+		s, ok := scorePets(item, p.Pets)
+		if !ok {
+			continue
+		}
+		if n := together[item.ProductID]; n > 0 {
+			s.Score += weightBoughtTogether * float64(n) / float64(maxTogether)
+			s.Reasons = append(s.Reasons, ReasonBoughtTogether)
+		}
+		if n := m.popularity[item.ProductID]; n > 0 {
+			s.Score += weightPopular * float64(n) / float64(m.maxPopular)
+			if len(s.Reasons) == 0 {
+				s.Reasons = append(s.Reasons, ReasonPopular)
+			}
+		}
+		s.ProductID = item.ProductID
+		out = append(out, s)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// scorePets оцінює продукт за тваринами користувача; false — продукт не підходить
+// (алерген тварини, для якої він призначений, або вид, якого в користувача немає)
+
+func scorePets(item Item, pets []Pet) (Scored, bool) {
+	var s Scored
+	speciesMatch, stageMatch := false, false
+	for _, pet := range pets {
+		if item.Species != "" && item.Species != pet.Species {
+			continue
+		}
+		for _, a := range pet.Allergies {
+			for _, allergen := range item.Allergens {
+				if a == allergen {
+					return s, false
+				}
+			}
+		}
+		if item.Species != "" {
+			speciesMatch = true
+		}
+		if item.LifeStage != "" && item.LifeStage == pet.Stage {
+			stageMatch = true
+		}
+	}
+	if len(pets) > 0 && item.Species != "" && !speciesMatch {
+		return s, false
+	}
+	if speciesMatch {
+		s.Score += weightSpecies
+		s.Reasons = append(s.Reasons, ReasonSpecies)
+	}
+	if stageMatch {
+		s.Score += weightLifeStage
+		s.Reasons = append(s.Reasons, ReasonLifeStage)
+	}
+	return s, true
+}
@@ -0,0 +1,93 @@
+package recommend_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/recommend"
+)
+
+// ids повертає ID продуктів у порядку рейтингу
+
+func ids(scored []recommend.Scored) []uint {
+	out := make([]uint, len(scored))
+	for i, s := range scored {
+		out[i] = s.ProductID
+	}
+	return out
+}
+
+// Тест ознак продукту з атрибутів
+
+func TestItemFromProduct(t *testing.T) {
+	item := recommend.ItemFromProduct(models.Product{ID: 7, SellableStock: 3, Attributes: models.Attributes{
+		"species": "Cat", "life_stage": "kitten", "allergens": "Chicken, grain, ",
+	}})
+	assert.Equal(t, recommend.Item{
+		ProductID: 7, Species: "cat", LifeStage: recommend.StageJunior, Allergens: []string{"chicken", "grain"}, InStock: true,
+	}, item)
+
+	item = recommend.ItemFromProduct(models.Product{ID: 8, Attributes: models.Attributes{"species": "all", "life_stage": 3.0}})
+	assert.Empty(t, item.Species)
+	assert.Empty(t, item.LifeStage)
+	assert.False(t, item.InStock)
+}
+
+// Тест вікової групи за датою народження
+
+func TestStageAt(t *testing.T) {
+	now := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	at := func(years, months int) *time.Time {
+		d := now.AddDate(-years, -months, 0)
+		return &d
+	}
+	assert.Equal(t, recommend.StageJunior, recommend.StageAt(at(0, 6), now))
+	assert.Equal(t, recommend.StageAdult, recommend.StageAt(at(1, 0), now))
+	assert.Equal(t, recommend.StageAdult, recommend.StageAt(at(6, 11), now))
+	assert.Equal(t, recommend.StageSenior, recommend.StageAt(at(7, 0), now))
+	assert.Empty(t, recommend.StageAt(nil, now))
+}
+
+// Тест рейтингу за тваринами: вид і вік піднімають товар, алергени та чужі види виключають
+
+func TestRankByPets(t *testing.T) {
+	m := recommend.Build([]recommend.Item{
+		{ProductID: 1, Species: "cat", LifeStage: recommend.StageSenior, InStock: true},
+		{ProductID: 2, Species: "cat", Allergens: []string{"chicken"}, InStock: true},
+		{ProductID: 3, Species: "dog", InStock: true},
+		{ProductID: 4, InStock: true},
+		{ProductID: 5, Species: "cat", InStock: false},
+		{ProductID: 6, Species: "cat", LifeStage: recommend.StageJunior, InStock: true},
+	}, nil)
+
+	got := m.Rank(recommend.Profile{Pets: []recommend.Pet{
+		{Species: "cat", Stage: recommend.StageSenior, Allergies: []string{"chicken"}},
+	}}, 10)
+	assert.Equal(t, []uint{1, 6, 4}, ids(got))
+	assert.Equal(t, []string{recommend.ReasonSpecies, recommend.ReasonLifeStage}, got[0].Reasons)
+	assert.Empty(t, got[2].Reasons)
+
+	// Без тварин підходять усі товари в наявності
+
+	got = m.Rank(recommend.Profile{}, 10)
+	assert.Equal(t, []uint{1, 2, 3, 4, 6}, ids(got))
+}
+
+// Тест спільних покупок: товар, який купують разом з покупками користувача, піднімається вище популярного
+
+func TestRankBoughtTogether(t *testing.T) {
+	items := []recommend.Item{{ProductID: 1, InStock: true}, {ProductID: 2, InStock: true}, {ProductID: 3, InStock: true}, {ProductID: 4, InStock: true}}
+	m := recommend.Build(items, [][]uint{
+		{1, 2}, {1, 2}, {3, 4}, {3, 4}, {3, 4}, {3, 3},
+	})
+
+	got := m.Rank(recommend.Profile{Purchased: []uint{1}}, 2)
+	require.Len(t, got, 2)
+	assert.Equal(t, []uint{2, 3}, ids(got))
+	assert.Equal(t, []string{recommend.ReasonBoughtTogether}, got[0].Reasons)
+	assert.Equal(t, []string{recommend.ReasonPopular}, got[1].Reasons)
+}
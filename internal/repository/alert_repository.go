@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// AlertRepository зберігає стан сповіщень про низький залишок (stock_alerts), щоб про кожен
+// перетин порогу дозамовлення сповіщати один раз, навіть якщо перевірку запускають кілька реплік.
+
+type AlertRepository interface {
+	ClaimLowStock(ctx context.Context, limit int) ([]models.Product, error) // позначає до limit нових продуктів нижче порогу і повертає їх
+	Release(ctx context.Context, productIDs []uint) error                   // знімає позначку (сповіщення не доставлено — повторити пізніше)
+	Resolve(ctx context.Context) (int64, error)                             // знімає позначки з продуктів, залишок яких знову вищий за поріг
+}
+
+// alertRepo реалізує AlertRepository
+
+type alertRepo struct {
+	db *gorm.DB
+}
+
+// NewAlertRepository створює новий AlertRepository
+
+func NewAlertRepository(db *gorm.DB) AlertRepository {
+	return &alertRepo{db: db}
+}
+
+// ClaimLowStock одним INSERT ... SELECT ... ON CONFLICT DO NOTHING записує в stock_alerts продукти,
+// що опустилися до порогу і ще не мають позначки. RETURNING повертає лише вставлені рядки,
+// тому паралельна перевірка з іншої репліки не отримає ті самі продукти.
+
+func (r *alertRepo) ClaimLowStock(ctx context.Context, limit int) ([]models.Product, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Raw(`INSERT INTO stock_alerts (product_id, created_at, stock, reorder_point)
+SELECT id, now(), stock, reorder_point FROM products
+WHERE deleted_at IS NULL AND `+belowReorder+`
+  AND NOT EXISTS (SELECT 1 FROM stock_alerts a WHERE a.product_id = products.id)
+ORDER BY id LIMIT ?
+ON CONFLICT (product_id) DO NOTHING
+RETURNING product_id`, limit).Scan(&ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	var items []models.Product
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Order("id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Release видаляє позначки продуктів
+
+func (r *alertRepo) Release(ctx context.Context, productIDs []uint) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("product_id IN ?", productIDs).Delete(&models.StockAlert{}).Error
+}
+
+// Resolve видаляє позначки продуктів, які більше не потребують дозамовлення
+// (залишок поповнено, поріг вимкнено або продукт видалено)
+
+func (r *alertRepo) Resolve(ctx context.Context) (int64, error) {
+	res := r.db.WithContext(ctx).Exec(`DELETE FROM stock_alerts a USING products p
+WHERE p.id = a.product_id AND (p.deleted_at IS NOT NULL OR p.reorder_point = 0 OR p.stock > p.reorder_point)`)
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// CartRepository визначає методи для роботи з кошиками та їх позиціями.
+// Всі методи використовують WithContext(ctx) — корисно для таймаутів/тестів.
+
+type CartRepository interface {
+	GetOrCreate(ctx context.Context, userID uint) (*models.Cart, error)                            // повертає кошик користувача з позиціями, продуктами і варіантами (створює, якщо немає)
+	AddItem(ctx context.Context, cartID, productID, variantID uint, quantity, available int) error // додає кількість до позиції атомарно; ErrOutOfStock якщо разом стане більше за available
+	SetItem(ctx context.Context, cartID, productID, variantID uint, quantity int) error            // встановлює кількість продукту (variantID 0 — без варіанту) у кошику (вставка або оновлення)
+	RemoveItem(ctx context.Context, cartID, productID, variantID uint) error                       // видаляє продукт (або його варіант) з кошика
+	Clear(ctx context.Context, cartID uint) error                                                  // видаляє всі позиції кошика
+}
+
+// cartRepo реалізує CartRepository
+
+type cartRepo struct {
+	db *gorm.DB
+}
+
+// NewCartRepository створює новий CartRepository
+
+func NewCartRepository(db *gorm.DB) CartRepository {
+	return &cartRepo{db: db}
+}
+
+// GetOrCreate шукає кошик користувача або створює порожній.
+// Позиції підвантажуються разом з продуктами і варіантами (soft-deleted продукти не підвантажуються).
+
+func (r *cartRepo) GetOrCreate(ctx context.Context, userID uint) (*models.Cart, error) {
+	var cart models.Cart
+	err := r.db.WithContext(ctx).
+		Where(models.Cart{UserID: userID}).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Items.Product").
+		Preload("Items.Variant").
+		FirstOrCreate(&cart).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// AddItem вставляє позицію або збільшує її кількість одним запитом, тож паралельні додавання не губляться.
+// Підсумкова кількість порівнюється з available у тій самій транзакції: якщо її перевищено — ErrOutOfStock
+// і зміна відкочується. Рядок позиції лишається заблокованим до кінця транзакції, тому паралельний запит
+// бачить уже збільшену кількість.
+
+func (r *cartRepo) AddItem(ctx context.Context, cartID, productID, variantID uint, quantity, available int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var total int
+		err := tx.Raw(`
+			INSERT INTO cart_items (created_at, updated_at, cart_id, product_id, variant_id, quantity)
+			VALUES (now(), now(), ?, ?, ?, ?)
+			ON CONFLICT (cart_id, product_id, (COALESCE(variant_id, 0)))
+			DO UPDATE SET quantity = cart_items.quantity + EXCLUDED.quantity, updated_at = EXCLUDED.updated_at
+			RETURNING quantity`,
+			cartID, productID, optionalID(variantID), quantity).Scan(&total).Error
+		if err != nil {
+			return err
+		}
+		if total > available {
+			return ErrOutOfStock
+		}
+		return nil
+	})
+}
+
+// SetItem вставляє позицію або оновлює кількість, якщо продукт (з тим самим варіантом) вже є в кошику.
+// Унікальний індекс idx_cart_product побудовано на COALESCE(variant_id, 0), тому конфлікт описано виразом.
+
+func (r *cartRepo) SetItem(ctx context.Context, cartID, productID, variantID uint, quantity int) error {
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO cart_items (created_at, updated_at, cart_id, product_id, variant_id, quantity)
+		VALUES (now(), now(), ?, ?, ?, ?)
+		ON CONFLICT (cart_id, product_id, (COALESCE(variant_id, 0)))
+		DO UPDATE SET quantity = EXCLUDED.quantity, updated_at = EXCLUDED.updated_at`,
+		cartID, productID, optionalID(variantID), quantity).Error
+}
+
+// RemoveItem видаляє продукт (variantID 0 — позицію без варіанту) з кошика
+
+func (r *cartRepo) RemoveItem(ctx context.Context, cartID, productID, variantID uint) error {
+	return r.db.WithContext(ctx).
+		Where("cart_id = ? AND product_id = ? AND COALESCE(variant_id, 0) = ?", cartID, productID, variantID).
+		Delete(&models.CartItem{}).Error
+}
+
+// Clear видаляє всі позиції кошика
+
+func (r *cartRepo) Clear(ctx context.Context, cartID uint) error {
+	return r.db.WithContext(ctx).Where("cart_id = ?", cartID).Delete(&models.CartItem{}).Error
+}
+
+// optionalID перетворює 0 на NULL для необов'язкових зовнішніх ключів
+
+func optionalID(id uint) *uint {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
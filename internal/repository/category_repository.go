@@ -0,0 +1,268 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CategoryRepository визначає методи для роботи з категоріями і зв'язком продуктів з категоріями.
+// Всі методи використовують WithContext(ctx).
+
+type CategoryRepository interface {
+	Create(ctx context.Context, c *models.Category) error                                       // c.ID заповнюється автоматично; gorm.ErrDuplicatedKey якщо slug зайнятий
+	GetByID(ctx context.Context, id uint) (*models.Category, error)                             // gorm.ErrRecordNotFound якщо не знайдено
+	GetBySlug(ctx context.Context, slug string) (*models.Category, error)                       // gorm.ErrRecordNotFound якщо не знайдено
+	List(ctx context.Context) ([]models.Category, error)                                        // всі категорії плоским списком, за назвою
+	Update(ctx context.Context, c *models.Category, check func([]CategoryProduct) error) error  // змінює назву, slug, опис, схему і батьківську категорію; check (якщо не nil) перевіряє продукти піддерева в тій самій транзакції
+	Delete(ctx context.Context, id uint) error                                                  // gorm.ErrRecordNotFound якщо не знайдено; версії продуктів категорії зростають
+	CountChildren(ctx context.Context, id uint) (int64, error)                                  // кількість прямих підкатегорій
+	SubtreeIDs(ctx context.Context, id uint) ([]uint, error)                                    // ID категорії та всіх її нащадків
+	SetProductCategories(ctx context.Context, productID uint, categoryIDs []uint) error         // замінює категорії продукту; gorm.ErrRecordNotFound якщо продукту немає
+	AttributeSchemas(ctx context.Context, categoryIDs []uint) ([]models.AttributeSchema, error) // схеми атрибутів цих категорій і всіх їхніх предків
+}
+
+// CategoryProduct — продукт з піддерева категорії разом зі схемами атрибутів усіх його категорій та їхніх предків
+// (вже з урахуванням зміненої категорії)
+
+type CategoryProduct struct {
+	ID         uint                     // ID продукту
+	Attributes models.Attributes        // атрибути продукту
+	Schemas    []models.AttributeSchema // схеми категорій продукту і всіх їхніх предків
+}
+
+// categoryRepo реалізує CategoryRepository
+
+type categoryRepo struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository створює новий CategoryRepository
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepo{db: db}
+}
+
+// Create додає нову категорію
+
+func (r *categoryRepo) Create(ctx context.Context, c *models.Category) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(c).Error
+}
+
+// GetByID шукає категорію за ID
+
+func (r *categoryRepo) GetByID(ctx context.Context, id uint) (*models.Category, error) {
+	var c models.Category
+	if err := r.db.WithContext(ctx).First(&c, id).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetBySlug шукає категорію за slug
+
+func (r *categoryRepo) GetBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	var c models.Category
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&c).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// List повертає всі категорії; дерево будує сервіс
+
+func (r *categoryRepo) List(ctx context.Context) ([]models.Category, error) {
+	var items []models.Category
+	if err := r.db.WithContext(ctx).Order("name, id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update змінює дані категорії (без підкатегорій) і в тій самій транзакції збільшує версії її продуктів —
+// назва і slug категорії входять у відповідь продукту, тож ETag має змінитися.
+// Якщо check не nil, він отримує продукти піддерева категорії зі схемами вже після зміни;
+// помилка check відкочує транзакцію і повертається як є.
+
+func (r *categoryRepo) Update(ctx context.Context, c *models.Category, check func([]CategoryProduct) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(c).Select("name", "slug", "description", "parent_id", "attributes", "updated_at").Updates(c).Error
+		if err != nil {
+			return err
+		}
+		if err := bumpCategoryProducts(tx, c.ID); err != nil {
+			return err
+		}
+		if check == nil {
+			return nil
+		}
+		products, err := subtreeProducts(tx, c.ID)
+		if err != nil {
+			return err
+		}
+		return check(products)
+	})
+}
+
+// subtreeProducts повертає продукти, прив'язані до категорії або її нащадків, зі схемами атрибутів усіх
+// їхніх категорій. Категорій небагато, тож предків обходимо в пам'яті, а не рекурсивним запитом на продукт.
+
+func subtreeProducts(tx *gorm.DB, categoryID uint) ([]CategoryProduct, error) {
+	var products []models.Product
+	err := tx.Select("id", "attributes").
+		Where(`id IN (
+			WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE id = ?
+				UNION
+				SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+			)
+			SELECT pc.product_id FROM product_categories pc JOIN tree ON pc.category_id = tree.id)`, categoryID).
+		Order("id").Find(&products).Error
+	if err != nil || len(products) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uint, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	var links []struct {
+		ProductID  uint
+		CategoryID uint
+	}
+	if err := tx.Raw("SELECT product_id, category_id FROM product_categories WHERE product_id IN ?", ids).Scan(&links).Error; err != nil {
+		return nil, err
+	}
+	var categories []models.Category
+	if err := tx.Select("id", "parent_id", "attributes").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Category, len(categories))
+	for i := range categories {
+		byID[categories[i].ID] = &categories[i]
+	}
+	productCategories := make(map[uint][]uint, len(products))
+	for _, l := range links {
+		productCategories[l.ProductID] = append(productCategories[l.ProductID], l.CategoryID)
+	}
+
+	out := make([]CategoryProduct, len(products))
+	for i, p := range products {
+		out[i] = CategoryProduct{ID: p.ID, Attributes: p.Attributes}
+		seen := make(map[uint]bool)
+		for _, id := range productCategories[p.ID] {
+			for c := byID[id]; c != nil && !seen[c.ID]; {
+				seen[c.ID] = true
+				out[i].Schemas = append(out[i].Schemas, c.Attributes)
+				if c.ParentID == nil {
+					break
+				}
+				c = byID[*c.ParentID]
+			}
+		}
+	}
+	return out, nil
+}
+
+// Delete видаляє категорію; зв'язки з продуктами видаляються каскадом (fk_product_categories_category).
+// Версії продуктів категорії збільшуються до видалення, поки зв'язки ще існують.
+
+func (r *categoryRepo) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bumpCategoryProducts(tx, id); err != nil {
+			return err
+		}
+		res := tx.Delete(&models.Category{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// bumpCategoryProducts збільшує версії всіх продуктів, пов'язаних з категорією
+
+func bumpCategoryProducts(tx *gorm.DB, categoryID uint) error {
+	return tx.Model(&models.Product{}).
+		Where("id IN (SELECT product_id FROM product_categories WHERE category_id = ?)", categoryID).
+		Updates(map[string]interface{}{"version": gorm.Expr("version + 1"), "updated_at": gorm.Expr("now()")}).Error
+}
+
+// CountChildren рахує прямі підкатегорії
+
+func (r *categoryRepo) CountChildren(ctx context.Context, id uint) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&models.Category{}).Where("parent_id = ?", id).Count(&n).Error
+	return n, err
+}
+
+// SubtreeIDs повертає ID категорії та всіх нащадків одним рекурсивним запитом.
+// UNION (а не UNION ALL) відкидає повтори, тому запит завершується навіть за наявності циклу.
+
+func (r *categoryRepo) SubtreeIDs(ctx context.Context, id uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE tree AS (
+			SELECT id FROM categories WHERE id = ?
+			UNION
+			SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+		)
+		SELECT id FROM tree ORDER BY id`, id).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// SetProductCategories атомарно замінює категорії продукту і збільшує його версію
+// (категорії входять у відповідь продукту, тож ETag має змінитися).
+// Неіснуючий ID категорії порушує fk_product_categories_category — gorm.ErrForeignKeyViolated.
+
+func (r *categoryRepo) SetProductCategories(ctx context.Context, productID uint, categoryIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bumpProductVersion(tx, productID); err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM product_categories WHERE product_id = ?", productID).Error; err != nil {
+			return err
+		}
+		for _, id := range categoryIDs {
+			if err := tx.Exec("INSERT INTO product_categories (product_id, category_id) VALUES (?, ?)", productID, id).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AttributeSchemas повертає схеми атрибутів категорій разом з усіма предками (схема успадковується вниз),
+// піднімаючись деревом рекурсивним запитом. Порожній categoryIDs — порожній результат.
+
+func (r *categoryRepo) AttributeSchemas(ctx context.Context, categoryIDs []uint) ([]models.AttributeSchema, error) {
+	if len(categoryIDs) == 0 {
+		return nil, nil
+	}
+	var rows []struct {
+		Attributes models.AttributeSchema
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE up AS (
+			SELECT id, parent_id, attributes FROM categories WHERE id IN ?
+			UNION
+			SELECT c.id, c.parent_id, c.attributes FROM categories c JOIN up ON c.id = up.parent_id
+		)
+		SELECT attributes FROM up ORDER BY id`, categoryIDs).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	schemas := make([]models.AttributeSchema, 0, len(rows))
+	for _, row := range rows {
+		schemas = append(schemas, row.Attributes)
+	}
+	return schemas, nil
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// ImageRepository визначає методи для роботи з записами зображень продуктів (самі файли — у storage.Storage).
+// Кожна зміна збільшує версію продукту і оновлює Product.ImageURL на URL першого зображення.
+
+type ImageRepository interface {
+	ListByProduct(ctx context.Context, productID uint) ([]models.ProductImage, error) // зображення продукту в порядку показу
+	GetByID(ctx context.Context, productID, id uint) (*models.ProductImage, error)    // gorm.ErrRecordNotFound якщо зображення немає в цього продукту
+	Create(ctx context.Context, img *models.ProductImage) error                       // додає в кінець списку; gorm.ErrRecordNotFound якщо продукту немає
+	Reorder(ctx context.Context, productID uint, ids []uint) error                    // ids — усі зображення продукту в новому порядку
+	Delete(ctx context.Context, productID, id uint) error                             // gorm.ErrRecordNotFound якщо зображення немає
+}
+
+// imageRepo реалізує ImageRepository
+
+type imageRepo struct {
+	db *gorm.DB
+}
+
+// NewImageRepository створює новий ImageRepository
+
+func NewImageRepository(db *gorm.DB) ImageRepository {
+	return &imageRepo{db: db}
+}
+
+// ListByProduct повертає зображення продукту за position
+
+func (r *imageRepo) ListByProduct(ctx context.Context, productID uint) ([]models.ProductImage, error) {
+	var items []models.ProductImage
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Scopes(orderByPosition).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetByID шукає зображення за ID в межах продукту
+
+func (r *imageRepo) GetByID(ctx context.Context, productID, id uint) (*models.ProductImage, error) {
+	var img models.ProductImage
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).First(&img, id).Error; err != nil {
+		return nil, err
+	}
+	return &img, nil
+}
+
+// Create додає зображення в кінець списку продукту. Версія продукту збільшується першою —
+// це блокує рядок продукту, тож паралельні завантаження не отримують однакову позицію.
+
+func (r *imageRepo) Create(ctx context.Context, img *models.ProductImage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bumpProductVersion(tx, img.ProductID); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", img.ProductID).
+			Select("COALESCE(MAX(position) + 1, 0)").Scan(&img.Position).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(img).Error; err != nil {
+			return err
+		}
+		return syncPrimaryImage(tx, img.ProductID)
+	})
+}
+
+// Reorder присвоює зображенням позиції за порядком ids в одній транзакції
+
+func (r *imageRepo) Reorder(ctx context.Context, productID uint, ids []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bumpProductVersion(tx, productID); err != nil {
+			return err
+		}
+		for i, id := range ids {
+			res := tx.Model(&models.ProductImage{}).Where("product_id = ? AND id = ?", productID, id).Update("position", i)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return syncPrimaryImage(tx, productID)
+	})
+}
+
+// Delete видаляє запис зображення і збільшує версію продукту в одній транзакції
+
+func (r *imageRepo) Delete(ctx context.Context, productID, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bumpProductVersion(tx, productID); err != nil {
+			return err
+		}
+		res := tx.Where("product_id = ?", productID).Delete(&models.ProductImage{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return syncPrimaryImage(tx, productID)
+	})
+}
+
+// syncPrimaryImage записує в products.image_url URL першого зображення (порожній рядок, якщо зображень немає),
+// щоб клієнти, які читають лише image_url, бачили головне зображення
+
+func syncPrimaryImage(tx *gorm.DB, productID uint) error {
+	return tx.Exec(`
+		UPDATE products SET image_url = COALESCE(
+			(SELECT url FROM product_images WHERE product_id = ? ORDER BY position, id LIMIT 1), '')
+		WHERE id = ?`, productID, productID).Error
+}
+
+// orderByPosition — порядок показу зображень (Preload і списки)
+
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position").Order("id")
+}
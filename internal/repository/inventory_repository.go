@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/auth"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrNoWarehouse — немає жодної активної локації, куди можна прийняти товар
+
+var ErrNoWarehouse = errors.New("no active warehouse")
+
+// MovementFilter — параметри історії руху товару
+
+type MovementFilter struct {
+	From   *time.Time // рухи не раніше (включно), nil — без обмеження
+	To     *time.Time // рухи не пізніше (включно), nil — без обмеження
+	Limit  int
+	Offset int
+}
+
+// InventoryRepository визначає методи журналу складу (stock_movements).
+// Журнал лише доповнюється: Product.Stock змінюється тільки разом із записом руху в одній транзакції.
+
+type InventoryRepository interface {
+	Apply(ctx context.Context, m *models.StockMovement) error                                                   // змінює залишок на m.Quantity і записує рух; ErrOutOfStock, gorm.ErrRecordNotFound
+	Transfer(ctx context.Context, out, in *models.StockMovement) error                                          // переміщення між локаціями: обидва рухи в одній транзакції
+	ListByProduct(ctx context.Context, productID uint, f MovementFilter) ([]models.StockMovement, int64, error) // returns items, totalCount; нові першими
+	LastBefore(ctx context.Context, productID uint, at time.Time) (*models.StockMovement, error)                // останній рух не пізніше at; nil, nil якщо рухів не було
+	ListBelowReorder(ctx context.Context, limit, offset int) ([]models.Product, int64, error)                   // продукти із Stock <= ReorderPoint; returns items, totalCount
+}
+
+// inventoryRepo реалізує InventoryRepository
+
+type inventoryRepo struct {
+	db *gorm.DB
+}
+
+// NewInventoryRepository створює новий InventoryRepository
+
+func NewInventoryRepository(db *gorm.DB) InventoryRepository {
+	return &inventoryRepo{db: db}
+}
+
+// Apply змінює залишок продукту і записує рух в одній транзакції
+
+func (r *inventoryRepo) Apply(ctx context.Context, m *models.StockMovement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return applyStockMovement(tx, m)
+	})
+}
+
+// Transfer застосовує пару рухів переміщення (списання з джерела і надходження в призначення) в одній транзакції
+
+func (r *inventoryRepo) Transfer(ctx context.Context, out, in *models.StockMovement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := applyStockMovement(tx, out); err != nil {
+			return err
+		}
+		return applyStockMovement(tx, in)
+	})
+}
+
+// ListByProduct повертає рухи продукту (нові першими) з фільтром за часом і пагінацією
+
+func (r *inventoryRepo) ListByProduct(ctx context.Context, productID uint, f MovementFilter) ([]models.StockMovement, int64, error) {
+	var items []models.StockMovement
+	var total int64
+	q := r.db.WithContext(ctx).Model(&models.StockMovement{}).Where("product_id = ?", productID)
+	if f.From != nil {
+		q = q.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("created_at <= ?", *f.To)
+	}
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Order("created_at DESC, id DESC").Limit(f.Limit).Offset(f.Offset).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// LastBefore шукає останній рух продукту до моменту at: його StockAfter — залишок на той момент
+
+func (r *inventoryRepo) LastBefore(ctx context.Context, productID uint, at time.Time) (*models.StockMovement, error) {
+	var m models.StockMovement
+	err := r.db.WithContext(ctx).Where("product_id = ? AND created_at <= ?", productID, at).
+		Order("created_at DESC, id DESC").Take(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// belowReorder — умова «продукт потребує дозамовлення» (поріг задано і залишок до нього опустився)
+
+const belowReorder = "reorder_point > 0 AND stock <= reorder_point"
+
+// ListBelowReorder повертає продукти, які потребують дозамовлення: спершу ті, де нестача
+// відносно порогу найбільша
+
+func (r *inventoryRepo) ListBelowReorder(ctx context.Context, limit, offset int) ([]models.Product, int64, error) {
+	var items []models.Product
+	var total int64
+	q := r.db.WithContext(ctx).Model(&models.Product{}).Where(belowReorder)
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Order("stock - reorder_point, id").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// applyStockMovement у транзакції tx змінює залишок продукту в локації m.WarehouseID (nil — основний склад)
+// і загальний Product.Stock на m.Quantity (разом з версією продукту), а потім записує рух із загальним
+// залишком після нього. UPDATE продукту першим блокує його рядок, тому паралельні рухи одного продукту
+// виконуються по черзі і StockAfter утворює послідовний ланцюжок.
+// Повертає ErrOutOfStock, якщо залишок локації став би від'ємним, gorm.ErrRecordNotFound — якщо продукту немає.
+
+func applyStockMovement(tx *gorm.DB, m *models.StockMovement) error {
+	if m.WarehouseID == nil {
+		id, err := primaryWarehouse(tx)
+		if err != nil {
+			return err
+		}
+		m.WarehouseID = &id
+	}
+	var after []int
+	if err := tx.Raw("UPDATE products SET stock = stock + ?, version = version + 1, updated_at = now() WHERE id = ? RETURNING stock",
+		m.Quantity, m.ProductID).Scan(&after).Error; err != nil {
+		return err
+	}
+	if len(after) == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	if err := changeWarehouseStock(tx, *m.WarehouseID, m.ProductID, m.Quantity); err != nil {
+		return err
+	}
+	m.StockAfter = after[0]
+	return tx.Create(m).Error
+}
+
+// changeWarehouseStock змінює залишок продукту в локації на delta; ErrOutOfStock, якщо його не вистачає
+
+func changeWarehouseStock(tx *gorm.DB, warehouseID, productID uint, delta int) error {
+	if delta >= 0 {
+		return tx.Exec(`INSERT INTO warehouse_stock (warehouse_id, product_id, quantity, updated_at) VALUES (?, ?, ?, now())
+ON CONFLICT (warehouse_id, product_id) DO UPDATE SET quantity = warehouse_stock.quantity + EXCLUDED.quantity, updated_at = now()`,
+			warehouseID, productID, delta).Error
+	}
+	res := tx.Exec("UPDATE warehouse_stock SET quantity = quantity + ?, updated_at = now() WHERE warehouse_id = ? AND product_id = ? AND quantity + ? >= 0",
+		delta, warehouseID, productID, delta)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrOutOfStock
+	}
+	return nil
+}
+
+// primaryWarehouse повертає ID основного складу — активної локації з найменшим пріоритетом
+
+func primaryWarehouse(tx *gorm.DB) (uint, error) {
+	var w models.Warehouse
+	err := tx.Where("active = ?", true).Order("priority, id").Take(&w).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrNoWarehouse
+	}
+	return w.ID, err
+}
+
+// activeWarehouses повертає ID активних локацій за пріоритетом
+
+func activeWarehouses(tx *gorm.DB) ([]uint, error) {
+	var ids []uint
+	if err := tx.Model(&models.Warehouse{}).Where("active = ?", true).Order("priority, id").Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// actorFromContext — ID автентифікованого користувача для записів журналу складу, які створюються
+// поза InventoryService (створення та імпорт продуктів); 0 — запит без користувача
+
+func actorFromContext(ctx context.Context) uint {
+	if p, ok := auth.FromContext(ctx); ok {
+		return p.UserID
+	}
+	return 0
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"github.com/AlexRijikov/go-petshop-api/internal/pagination"
+	"gorm.io/gorm"
+)
+
+// keysetOrder — колонка сортування для keyset-пагінації.
+// id завжди додається другим ключем у тому ж напрямку, тому пара (column, id) унікальна.
+
+type keysetOrder struct {
+	column string // колонка сортування; "id" — сортування лише за id
+	desc   bool   // за спаданням
+}
+
+// orderBy повертає ORDER BY; reverse — зворотний порядок (для сторінки перед курсором)
+
+func (o keysetOrder) orderBy(reverse bool) string {
+	dir := "ASC"
+	if o.desc != reverse {
+		dir = "DESC"
+	}
+	if o.column == "id" {
+		return "id " + dir
+	}
+	return o.column + " " + dir + ", id " + dir
+}
+
+// seek додає умову "після курсора" (або "перед курсором" для c.Prev), сортування і LIMIT limit+1.
+// key — значення колонки сортування з курсора. Замість OFFSET база переходить по індексу
+// одразу до потрібного рядка, тому глибокі сторінки не сповільнюються, а видалені записи не зсувають сторінки.
+
+func (o keysetOrder) seek(q *gorm.DB, c *pagination.Cursor, key interface{}, limit int) *gorm.DB {
+	reverse := c != nil && c.Prev
+	if c != nil {
+		op := ">"
+		if o.desc != c.Prev {
+			op = "<"
+		}
+		if o.column == "id" {
+			q = q.Where("id "+op+" ?", c.ID)
+		} else {
+			q = q.Where("("+o.column+", id) "+op+" (?, ?)", key, c.ID)
+		}
+	}
+	return q.Order(o.orderBy(reverse)).Limit(limit + 1)
+}
@@ -0,0 +1,325 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/allocation"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Помилки репозиторію замовлень
+
+var (
+	ErrOutOfStock     = errors.New("insufficient stock")                // під час резервування на складі недостатньо товару
+	ErrStatusConflict = errors.New("order status changed concurrently") // статус замовлення змінився між перевіркою і оновленням
+)
+
+// OrderRepository визначає методи для роботи з замовленнями.
+// Всі методи використовують WithContext(ctx) — корисно для таймаутів/тестів.
+
+type OrderRepository interface {
+	CreateWithStock(ctx context.Context, o *models.Order, cartID uint) error                       // резервує товар і створює замовлення в одній транзакції
+	GetByID(ctx context.Context, id uint) (*models.Order, error)                                   // повертає замовлення з позиціями
+	ListByUser(ctx context.Context, userID uint, limit, offset int) ([]models.Order, int64, error) // returns items, totalCount
+	List(ctx context.Context, status string, limit, offset int) ([]models.Order, int64, error)     // всі замовлення (status порожній — без фільтра)
+	UpdateStatus(ctx context.Context, h *models.OrderStatusHistory, restock bool) error            // змінює статус, пише історію і, якщо restock, повертає товар на склад
+}
+
+// orderRepo реалізує OrderRepository
+
+type orderRepo struct {
+	db *gorm.DB
+}
+
+// NewOrderRepository створює новий OrderRepository
+
+func NewOrderRepository(db *gorm.DB) OrderRepository {
+	return &orderRepo{db: db}
+}
+
+// CreateWithStock в одній транзакції:
+//  1. блокує рядки продуктів (SELECT ... FOR UPDATE) у порядку ID — щоб уникнути deadlock між покупцями;
+//  2. блокує рядки варіантів позицій з VariantID і фіксує назву, артикул і ціну (варіанту, якщо він є) та рахує суму;
+//  3. обирає для позицій без варіанту локацію з достатнім залишком (одну для всього замовлення, якщо можливо);
+//  4. створює замовлення, зменшує залишки записами продажу в журналі складу (з ID замовлення і локацією),
+//     а залишок варіантів — у product_variants, додає перший запис історії статусів
+//     і, якщо cartID != 0, прибирає куплені позиції з кошика.
+//
+// Повертає ErrOutOfStock або gorm.ErrRecordNotFound (продукт або варіант не знайдено) — тоді транзакція відкочується.
+
+func (r *orderRepo) CreateWithStock(ctx context.Context, o *models.Order, cartID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := make([]uint, 0, len(o.Items))
+		for _, item := range o.Items {
+			ids = append(ids, item.ProductID)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+		var products []models.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).Order("id").Find(&products).Error; err != nil {
+			return err
+		}
+		byID := make(map[uint]*models.Product, len(products))
+		for i := range products {
+			byID[products[i].ID] = &products[i]
+		}
+		variants, err := lockVariants(tx, o.Items)
+		if err != nil {
+			return err
+		}
+
+		o.TotalCents = 0
+		for i := range o.Items {
+			item := &o.Items[i]
+			p, ok := byID[item.ProductID]
+			if !ok {
+				return gorm.ErrRecordNotFound
+			}
+
+			item.ProductName = p.Name
+			item.SKU = p.SKU
+			item.UnitPriceCents = p.PriceCents
+			if item.VariantID != nil {
+				v, ok := variants[*item.VariantID]
+				if !ok || v.ProductID != item.ProductID {
+					return gorm.ErrRecordNotFound
+				}
+				if v.Stock < item.Quantity {
+					return ErrOutOfStock
+				}
+				item.SKU = v.SKU
+				item.UnitPriceCents = v.PriceCents
+			}
+			item.SubtotalCents = item.UnitPriceCents * int64(item.Quantity)
+			o.TotalCents += item.SubtotalCents
+		}
+
+		if err := allocateItems(tx, o.Items, ids); err != nil {
+			return err
+		}
+		if err := tx.Create(o).Error; err != nil {
+			return err
+		}
+		for _, item := range o.Items {
+			if item.VariantID != nil {
+				if err := changeVariantStock(tx, item.ProductID, *item.VariantID, -item.Quantity); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := applyStockMovement(tx, &models.StockMovement{
+				ProductID: item.ProductID, Type: models.StockSale, Quantity: -item.Quantity,
+				ActorID: o.UserID, OrderID: &o.ID, WarehouseID: item.WarehouseID,
+			}); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&models.OrderStatusHistory{
+			OrderID: o.ID, ToStatus: o.Status, ChangedBy: o.UserID,
+		}).Error; err != nil {
+			return err
+		}
+
+		if cartID != 0 {
+			for _, item := range o.Items {
+				variantID := uint(0)
+				if item.VariantID != nil {
+					variantID = *item.VariantID
+				}
+				if err := tx.Where("cart_id = ? AND product_id = ? AND COALESCE(variant_id, 0) = ?", cartID, item.ProductID, variantID).
+					Delete(&models.CartItem{}).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// lockVariants блокує рядки варіантів позицій (SELECT ... FOR UPDATE у порядку ID) і повертає їх за ID
+
+func lockVariants(tx *gorm.DB, items []models.OrderItem) (map[uint]models.ProductVariant, error) {
+	var ids []uint
+	for _, item := range items {
+		if item.VariantID != nil {
+			ids = append(ids, *item.VariantID)
+		}
+	}
+	byID := map[uint]models.ProductVariant{}
+	if len(ids) == 0 {
+		return byID, nil
+	}
+	var variants []models.ProductVariant
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).Order("id").Find(&variants).Error; err != nil {
+		return nil, err
+	}
+	for _, v := range variants {
+		byID[v.ID] = v
+	}
+	return byID, nil
+}
+
+// changeVariantStock змінює залишок варіанту на delta і збільшує версію продукту (ETag продукту включає варіанти).
+// Залишок не може стати від'ємним — тоді ErrOutOfStock; варіант, якого вже немає, пропускається.
+
+func changeVariantStock(tx *gorm.DB, productID, variantID uint, delta int) error {
+	res := tx.Exec("UPDATE product_variants SET stock = stock + ?, updated_at = now() WHERE id = ? AND stock + ? >= 0",
+		delta, variantID, delta)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		if delta < 0 {
+			return ErrOutOfStock
+		}
+		return nil
+	}
+	if err := bumpProductVersion(tx, productID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// allocateItems обирає для кожної позиції без варіанту активну локацію з достатнім залишком (allocation.Allocate)
+// і записує її в OrderItem.WarehouseID; ErrOutOfStock, якщо позицію не можна зарезервувати.
+// Залишки читаються після блокування рядків продуктів, тому паралельне замовлення їх не змінить.
+
+func allocateItems(tx *gorm.DB, items []models.OrderItem, productIDs []uint) error {
+	warehouses, err := activeWarehouses(tx)
+	if err != nil {
+		return err
+	}
+	var stock []allocation.Stock
+	if err := tx.Model(&models.WarehouseStock{}).Select("warehouse_id", "product_id", "quantity").
+		Where("product_id IN ? AND quantity > 0", productIDs).Scan(&stock).Error; err != nil {
+		return err
+	}
+	var lines []allocation.Line
+	var index []int // позиції, що розподіляються між локаціями
+	for i, item := range items {
+		if item.VariantID == nil {
+			lines = append(lines, allocation.Line{ProductID: item.ProductID, Quantity: item.Quantity})
+			index = append(index, i)
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	picked, ok := allocation.Allocate(lines, warehouses, stock)
+	if !ok {
+		return ErrOutOfStock
+	}
+	for k, i := range index {
+		items[i].WarehouseID = &picked[k]
+	}
+	return nil
+}
+
+// GetByID шукає замовлення за ID разом з позиціями та історією статусів
+
+func (r *orderRepo) GetByID(ctx context.Context, id uint) (*models.Order, error) {
+	var o models.Order
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Preload("History", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&o, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// ListByUser повертає замовлення користувача (нові першими) з пагінацією
+
+func (r *orderRepo) ListByUser(ctx context.Context, userID uint, limit, offset int) ([]models.Order, int64, error) {
+	var items []models.Order
+	var total int64
+	q := r.db.WithContext(ctx).Model(&models.Order{}).Where("user_id = ?", userID)
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Preload("Items").Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// List повертає всі замовлення (для адміністраторів), нові першими, з необов'язковим фільтром за статусом
+
+func (r *orderRepo) List(ctx context.Context, status string, limit, offset int) ([]models.Order, int64, error) {
+	var items []models.Order
+	var total int64
+	q := r.db.WithContext(ctx).Model(&models.Order{})
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Preload("Items").Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// UpdateStatus атомарно змінює статус замовлення з h.FromStatus на h.ToStatus:
+// блокує рядок замовлення, перевіряє, що статус не змінився паралельно (інакше ErrStatusConflict),
+// записує історію і, якщо restock, повертає зарезервовану кількість у Product.Stock записами повернення в журналі складу
+// (позиції з варіантом — у залишок варіанту).
+
+func (r *orderRepo) UpdateStatus(ctx context.Context, h *models.OrderStatusHistory, restock bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var o models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&o, h.OrderID).Error; err != nil {
+			return err
+		}
+		if o.Status != h.FromStatus {
+			return ErrStatusConflict
+		}
+
+		if err := tx.Model(&o).Update("status", h.ToStatus).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(h).Error; err != nil {
+			return err
+		}
+
+		if restock {
+			var items []models.OrderItem
+			if err := tx.Where("order_id = ?", o.ID).Order("product_id").Find(&items).Error; err != nil {
+				return err
+			}
+			active, err := activeWarehouses(tx)
+			if err != nil {
+				return err
+			}
+			for _, item := range items {
+				if item.VariantID != nil {
+					if err := changeVariantStock(tx, item.ProductID, *item.VariantID, item.Quantity); err != nil {
+						return err
+					}
+					continue
+				}
+				// Товар повертається в локацію резерву; якщо її деактивовано (або замовлення старіше за локації) — на основний склад
+				warehouseID := item.WarehouseID
+				if warehouseID != nil && !slices.Contains(active, *warehouseID) {
+					warehouseID = nil
+				}
+				if err := applyStockMovement(tx, &models.StockMovement{
+					ProductID: item.ProductID, Type: models.StockReturn, Quantity: item.Quantity,
+					Reason: "order " + h.ToStatus, ActorID: h.ChangedBy, OrderID: &o.ID, WarehouseID: warehouseID,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
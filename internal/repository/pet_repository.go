@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// PetRepository визначає методи для роботи з тваринами покупців.
+// Усі методи обмежені власником: чужа тварина поводиться як відсутня (gorm.ErrRecordNotFound).
+
+type PetRepository interface {
+	ListByUser(ctx context.Context, userID uint) ([]models.Pet, error) // тварини користувача в порядку додавання
+	CountByUser(ctx context.Context, userID uint) (int64, error)       // кількість тварин користувача
+	GetByID(ctx context.Context, userID, id uint) (*models.Pet, error) // gorm.ErrRecordNotFound якщо не знайдено або тварина чужа
+	Create(ctx context.Context, p *models.Pet) error                   // p.ID заповнюється автоматично
+	Update(ctx context.Context, p *models.Pet) error                   // змінює всі поля, крім власника і часу створення
+	Delete(ctx context.Context, userID, id uint) error                 // gorm.ErrRecordNotFound якщо не знайдено або тварина чужа
+}
+
+// petRepo реалізує PetRepository
+
+type petRepo struct {
+	db *gorm.DB
+}
+
+// NewPetRepository створює новий PetRepository
+
+func NewPetRepository(db *gorm.DB) PetRepository {
+	return &petRepo{db: db}
+}
+
+// ListByUser повертає тварин користувача
+
+func (r *petRepo) ListByUser(ctx context.Context, userID uint) ([]models.Pet, error) {
+	var items []models.Pet
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// CountByUser рахує тварин користувача
+
+func (r *petRepo) CountByUser(ctx context.Context, userID uint) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&models.Pet{}).Where("user_id = ?", userID).Count(&total).Error
+	return total, err
+}
+
+// GetByID шукає тварину користувача за ID
+
+func (r *petRepo) GetByID(ctx context.Context, userID, id uint) (*models.Pet, error) {
+	var p models.Pet
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&p, id).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Create додає тварину
+
+func (r *petRepo) Create(ctx context.Context, p *models.Pet) error {
+	return r.db.WithContext(ctx).Create(p).Error
+}
+
+// Update зберігає всі поля тварини (порожні значення теж — порода, дата народження)
+
+func (r *petRepo) Update(ctx context.Context, p *models.Pet) error {
+	res := r.db.WithContext(ctx).Model(p).Where("user_id = ?", p.UserID).
+		Select("*").Omit("id", "user_id", "created_at").Updates(p)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete видаляє тварину користувача
+
+func (r *petRepo) Delete(ctx context.Context, userID, id uint) error {
+	res := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.Pet{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
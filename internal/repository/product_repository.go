@@ -2,21 +2,98 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
 
 	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/pagination"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // List повертає і загальну кількість (потрібно для пагінації на фронті).
 
+// ErrVersionConflict — продукт змінили або видалили після того, як його прочитали (версія не збігається)
+
+var ErrVersionConflict = errors.New("product version conflict")
+
+// ProductFilter — параметри фільтрації, пошуку, сортування та пагінації каталогу
+
+type ProductFilter struct {
+	IDs           []uint            // лише продукти з цими ID
+	Category      string            // точний збіг категорії (застаріле текстове поле Product.Category)
+	CategoryIDs   []uint            // продукти хоча б з однієї з цих категорій таксономії (категорія разом з нащадками)
+	Attributes    []AttributeFilter // умови за атрибутами продукту (усі мають виконуватися)
+	MinPriceCents *int64            // мінімальна ціна (включно), nil — без обмеження
+	MaxPriceCents *int64            // максимальна ціна (включно), nil — без обмеження
+	InStock       bool              // лише товари, які можна продати (залишок в активній локації)
+	Query         string            // повнотекстовий пошук за назвою та описом
+	Sort          string            // price, -price, name, -name, created_at, -created_at, rating, -rating (мінус — за спаданням)
+	Limit         int
+	Offset        int                // пропускається в режимі курсорів
+	Cursor        *pagination.Cursor // позиція для ListByCursor, nil — перша сторінка
+	SkipTotal     bool               // не рахувати загальну кількість (COUNT(*) повільний на великому каталозі)
+}
+
+// Оператори фільтра за атрибутом
+
+const (
+	AttrEq  = "eq"  // значення дорівнює (attr.species=dog)
+	AttrMin = "min" // число не менше (attr.weight_kg.min=2)
+	AttrMax = "max" // число не більше (attr.weight_kg.max=10)
+)
+
+// AttributeFilter — умова за атрибутом продукту. Value — string, float64 або bool
+// (для AttrMin/AttrMax — float64); тип визначає сервіс за схемою атрибутів.
+
+type AttributeFilter struct {
+	Name  string
+	Op    string
+	Value interface{}
+}
+
+// productSortColumns — дозволені значення ProductFilter.Sort і відповідна колонка.
+// Порожнє значення — сортування за id. id додається другим ключем, щоб порядок був стабільним між сторінками.
+
+var productSortColumns = map[string]keysetOrder{
+	"":            {column: "id"},
+	"price":       {column: "price_cents"},
+	"-price":      {column: "price_cents", desc: true},
+	"name":        {column: "name"},
+	"-name":       {column: "name", desc: true},
+	"created_at":  {column: "created_at"},
+	"-created_at": {column: "created_at", desc: true},
+	"rating":      {column: "rating_avg"},
+	"-rating":     {column: "rating_avg", desc: true},
+}
+
+// IsValidProductSort перевіряє, чи підтримується значення сортування (порожнє — за замовчуванням)
+
+func IsValidProductSort(sort string) bool {
+	_, ok := productSortColumns[sort]
+	return ok
+}
+
+// productSearchVector — вираз tsvector для повнотекстового пошуку; має збігатися з GIN індексом idx_products_search.
+// Конфігурація 'simple' не залежить від мови (каталог українською та англійською).
+
+const productSearchVector = "to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(description, ''))"
+
 // Всі методи використовують WithContext(ctx) — корисно для таймаутів/тестів.
 
 type ProductRepository interface {
-	Create(ctx context.Context, p *models.Product) error                          // p.ID заповнюється автоматично
-	GetByID(ctx context.Context, id uint) (*models.Product, error)                // повертає nil, nil якщо не знайдено
-	List(ctx context.Context, limit, offset int) ([]models.Product, int64, error) // returns items, totalCount
-	Update(ctx context.Context, p *models.Product) error
-	Delete(ctx context.Context, id uint) error
+	Create(ctx context.Context, p *models.Product) error                                         // p.ID заповнюється автоматично
+	GetByID(ctx context.Context, id uint) (*models.Product, error)                               // повертає nil, nil якщо не знайдено
+	List(ctx context.Context, f ProductFilter) ([]models.Product, int64, error)                  // returns items, totalCount
+	ListByCursor(ctx context.Context, f ProductFilter) (*pagination.Page[models.Product], error) // keyset-пагінація за f.Cursor; pagination.ErrInvalidCursor для чужого курсора
+	Update(ctx context.Context, p *models.Product) error                                         // лише якщо версія в БД дорівнює p.Version, інакше ErrVersionConflict; p.Version збільшується
+	Delete(ctx context.Context, id uint, version uint) error                                     // version 0 — без перевірки версії; інакше ErrVersionConflict, якщо вона не збігається
+
+	FindBySKUs(ctx context.Context, skus []string) ([]models.Product, error)              // продукти з цими артикулами разом з видаленими (soft delete) і категоріями
+	UpsertBySKU(ctx context.Context, products []models.Product) (map[string]error, error) // вставляє або оновлює продукти за артикулом в одній транзакції; повертає відхилені артикули
+	Each(ctx context.Context, batchSize int, fn func([]models.Product) error) error       // обходить усі продукти пачками за id (експорт)
 }
 
 // productRepo реалізує ProductRepository
@@ -31,45 +108,437 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 	return &productRepo{db: db}
 }
 
-// Create додає новий продукт в базу даних
+// Create додає новий продукт разом з варіантами і категоріями (за Categories[i].ID) в одній транзакції.
+// Варіанти вставляються явно: автозбереження асоціацій GORM (ON CONFLICT DO NOTHING) мовчки пропустило б зайнятий артикул.
+// Початковий залишок потрапляє на основний склад і записується в журнал складу як надходження.
 
 func (r *productRepo) Create(ctx context.Context, p *models.Product) error {
-	return r.db.WithContext(ctx).Create(p).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(p).Error; err != nil {
+			return err
+		}
+		if p.Stock > 0 {
+			warehouseID, err := primaryWarehouse(tx)
+			if err != nil {
+				return err
+			}
+			if err := changeWarehouseStock(tx, warehouseID, p.ID, p.Stock); err != nil {
+				return err
+			}
+			if err := tx.Create(&models.StockMovement{
+				ProductID: p.ID, Type: models.StockReceipt, Quantity: p.Stock, StockAfter: p.Stock,
+				Reason: "initial stock", ActorID: actorFromContext(ctx), WarehouseID: &warehouseID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		for i := range p.Variants {
+			p.Variants[i].ProductID = p.ID
+			if err := tx.Create(&p.Variants[i]).Error; err != nil {
+				return err
+			}
+		}
+		for _, c := range p.Categories {
+			if err := tx.Exec("INSERT INTO product_categories (product_id, category_id) VALUES (?, ?)", p.ID, c.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // GetByID шукає продукт за ID
 
 func (r *productRepo) GetByID(ctx context.Context, id uint) (*models.Product, error) {
 	var p models.Product
-	if err := r.db.WithContext(ctx).First(&p, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Categories").Preload("Variants", orderByID).Preload("Images", orderByPosition).Preload("Availability", inStockByPriority).Preload("Availability.Warehouse").First(&p, id).Error; err != nil {
 		return nil, err
 	}
+	setSellable(&p)
 	return &p, nil
 }
 
-// List повертає продукти з пагінацією
+// List повертає продукти з фільтрами, пошуком, сортуванням і пагінацією (limit, offset)
 
-func (r *productRepo) List(ctx context.Context, limit, offset int) ([]models.Product, int64, error) {
+func (r *productRepo) List(ctx context.Context, f ProductFilter) ([]models.Product, int64, error) {
 	var items []models.Product
 	var total int64
-	q := r.db.WithContext(ctx).Model(&models.Product{})
-	if err := q.Count(&total).Error; err != nil {
-		return nil, 0, err
+	q := r.filtered(ctx, f)
+
+	if !f.SkipTotal {
+		if err := q.Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// Сортування: явне з білого списку, за релевантністю при пошуку, інакше за id
+
+	if f.Sort == "" && f.Query != "" {
+		q = q.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "ts_rank(" + productSearchVector + ", websearch_to_tsquery('simple', ?)) DESC, id ASC",
+			Vars: []interface{}{f.Query},
+		}})
+	} else {
+		q = q.Order(productSortColumns[f.Sort].orderBy(false))
 	}
-	if err := q.Limit(limit).Offset(offset).Find(&items).Error; err != nil {
+
+	if err := q.Preload("Categories").Preload("Variants", orderByID).Preload("Images", orderByPosition).Preload("Availability", inStockByPriority).Preload("Availability.Warehouse").Limit(f.Limit).Offset(f.Offset).Find(&items).Error; err != nil {
 		return nil, 0, err
 	}
+	for i := range items {
+		setSellable(&items[i])
+	}
 	return items, total, nil
 }
 
-// Update змінює дані продукту
+// ListByCursor повертає сторінку продуктів після (або перед) f.Cursor.
+// Сортування за релевантністю не підтримується: ts_rank не є стабільним ключем,
+// тому пошук без явного sort сортується за id.
+
+func (r *productRepo) ListByCursor(ctx context.Context, f ProductFilter) (*pagination.Page[models.Product], error) {
+	order := productSortColumns[f.Sort]
+	var key interface{}
+	if f.Cursor != nil {
+		if f.Cursor.Sort != f.Sort {
+			return nil, pagination.ErrInvalidCursor // курсор видано для іншого сортування
+		}
+		var err error
+		if key, err = parseProductSortKey(order.column, f.Cursor.Key); err != nil {
+			return nil, pagination.ErrInvalidCursor
+		}
+	}
+
+	var total *int64
+	if !f.SkipTotal {
+		var n int64
+		if err := r.filtered(ctx, f).Count(&n).Error; err != nil {
+			return nil, err
+		}
+		total = &n
+	}
+
+	var items []models.Product
+	if err := order.seek(r.filtered(ctx, f), f.Cursor, key, f.Limit).Preload("Categories").Preload("Variants", orderByID).Preload("Images", orderByPosition).Preload("Availability", inStockByPriority).Preload("Availability.Warehouse").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	for i := range items {
+		setSellable(&items[i])
+	}
+
+	page := pagination.BuildPage(items, f.Cursor, f.Limit, func(p models.Product) pagination.Cursor {
+		return pagination.Cursor{Sort: f.Sort, Key: productSortKey(order.column, p), ID: p.ID}
+	})
+	page.Total = total
+	return page, nil
+}
+
+// filtered будує запит з умовами фільтрів і пошуку (без сортування і пагінації)
+
+func (r *productRepo) filtered(ctx context.Context, f ProductFilter) *gorm.DB {
+	q := r.db.WithContext(ctx).Model(&models.Product{})
+	if len(f.IDs) > 0 {
+		q = q.Where("products.id IN ?", f.IDs)
+	}
+	if f.Category != "" {
+		q = q.Where("category = ?", f.Category)
+	}
+	if len(f.CategoryIDs) > 0 {
+		q = q.Where("EXISTS (SELECT 1 FROM product_categories pc WHERE pc.product_id = products.id AND pc.category_id IN ?)", f.CategoryIDs)
+	}
+	for _, a := range f.Attributes {
+		switch a.Op {
+		case AttrMin, AttrMax:
+			// CASE гарантує, що ::numeric не застосовується до нечислових значень (інакше помилка запиту)
+			op := ">="
+			if a.Op == AttrMax {
+				op = "<="
+			}
+			q = q.Where("CASE WHEN jsonb_typeof(attributes -> ?) = 'number' THEN (attributes ->> ?)::numeric END "+op+" ?", a.Name, a.Name, a.Value)
+		default:
+			// Рівність через @> використовує GIN індекс idx_products_attributes
+			doc, _ := json.Marshal(map[string]interface{}{a.Name: a.Value})
+			q = q.Where("attributes @> ?::jsonb", string(doc))
+		}
+	}
+	if f.MinPriceCents != nil {
+		q = q.Where("price_cents >= ?", *f.MinPriceCents)
+	}
+	if f.MaxPriceCents != nil {
+		q = q.Where("price_cents <= ?", *f.MaxPriceCents)
+	}
+	if f.InStock {
+		q = q.Where("EXISTS (SELECT 1 FROM warehouse_stock ws JOIN warehouses w ON w.id = ws.warehouse_id WHERE ws.product_id = products.id AND w.active AND ws.quantity > 0)")
+	}
+	if f.Query != "" {
+		q = q.Where(productSearchVector+" @@ websearch_to_tsquery('simple', ?)", f.Query)
+	}
+	return q
+}
+
+// productSortKey повертає значення колонки сортування продукту для курсора
+
+func productSortKey(column string, p models.Product) string {
+	switch column {
+	case "price_cents":
+		return strconv.FormatInt(p.PriceCents, 10)
+	case "name":
+		return p.Name
+	case "created_at":
+		return p.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case "rating_avg":
+		return strconv.FormatFloat(p.RatingAvg, 'f', -1, 64)
+	}
+	return "" // сортування за id — достатньо Cursor.ID
+}
+
+// parseProductSortKey розбирає значення колонки сортування з курсора
+
+func parseProductSortKey(column, key string) (interface{}, error) {
+	switch column {
+	case "price_cents":
+		return strconv.ParseInt(key, 10, 64)
+	case "created_at":
+		return time.Parse(time.RFC3339Nano, key)
+	case "rating_avg":
+		return strconv.ParseFloat(key, 64)
+	}
+	return key, nil
+}
+
+// Update змінює дані продукту з оптимістичним блокуванням: UPDATE ... WHERE id = ? AND version = ?.
+// Якщо рядок за цей час змінили (або видалили), нічого не оновлюється і повертається ErrVersionConflict —
+// так дві паралельні зміни не перезаписують одна одну мовчки. Категорії змінюються окремо (CategoryRepository),
+// залишок — лише через журнал складу (InventoryRepository), рейтинг — лише відгуками (ReviewRepository).
 
 func (r *productRepo) Update(ctx context.Context, p *models.Product) error {
-	return r.db.WithContext(ctx).Save(p).Error
+	expected := p.Version
+	p.Version = expected + 1
+	res := r.db.WithContext(ctx).Model(p).Where("version = ?", expected).
+		Select("*").Omit("id", "created_at", "deleted_at", "stock", "rating_avg", "rating_count", clause.Associations).Updates(p)
+	if res.Error == nil && res.RowsAffected == 0 {
+		res.Error = ErrVersionConflict
+	}
+	if res.Error != nil {
+		p.Version = expected
+		return res.Error
+	}
+	return nil
+}
+
+// bumpProductVersion збільшує версію продукту в транзакції tx, коли змінюються пов'язані дані,
+// що входять у відповідь продукту (категорії, варіанти) — так змінюється і ETag.
+// gorm.ErrRecordNotFound, якщо продукту немає або його видалено.
+
+func bumpProductVersion(tx *gorm.DB, productID uint) error {
+	res := tx.Model(&models.Product{}).Where("id = ?", productID).
+		Updates(map[string]interface{}{"version": gorm.Expr("version + 1"), "updated_at": gorm.Expr("now()")})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// inStockByPriority — ненульові залишки продукту в активних локаціях у порядку пріоритету (Preload).
+// Неактивні локації не потрапляють в availability: оформлення замовлення з них не резервує.
+
+func inStockByPriority(db *gorm.DB) *gorm.DB {
+	return db.Joins("JOIN warehouses ON warehouses.id = warehouse_stock.warehouse_id").
+		Where("warehouse_stock.quantity > 0 AND warehouses.active").Order("warehouses.priority, warehouses.id")
+}
+
+// setSellable рахує SellableStock продукту з підвантаженої Availability (лише активні локації)
+
+func setSellable(p *models.Product) {
+	p.SellableStock = 0
+	for _, ws := range p.Availability {
+		p.SellableStock += ws.Quantity
+	}
+}
+
+// orderByID — порядок підвантаження пов'язаних записів (Preload) за id
+
+func orderByID(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
+// Delete видаляє продукт за ID (gorm.ErrRecordNotFound, якщо продукту немає або його вже видалено).
+// Якщо version > 0, видаляє лише цю версію продукту (ErrVersionConflict, якщо рядок не знайдено).
+
+func (r *productRepo) Delete(ctx context.Context, id uint, version uint) error {
+	q := r.db.WithContext(ctx)
+	if version > 0 {
+		q = q.Where("version = ?", version)
+	}
+	res := q.Delete(&models.Product{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		if version > 0 {
+			return ErrVersionConflict
+		}
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// importColumns — колонки продукту, які оновлює UpsertBySKU (залишок, асоціації, версія і службові поля — окремо)
+
+var importColumns = []string{"name", "description", "price_cents", "category", "attributes", "updated_at"}
+
+// FindBySKUs шукає продукти за артикулами, включно з видаленими: імпорт з тим самим артикулом відновлює продукт
+
+func (r *productRepo) FindBySKUs(ctx context.Context, skus []string) ([]models.Product, error) {
+	if len(skus) == 0 {
+		return nil, nil
+	}
+	var items []models.Product
+	if err := r.db.WithContext(ctx).Unscoped().Preload("Categories").Where("sku IN ?", skus).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UpsertBySKU вставляє продукти одним INSERT ... ON CONFLICT (sku) DO UPDATE: наявні продукти отримують
+// нові значення importColumns, наступну версію і знімається позначка видалення. Асоціації не змінюються.
+// Stock — бажаний загальний залишок: наявні продукти блокуються до запису, а різниця з поточним
+// залишком застосовується рухом журналу складу на основному складі (новий продукт — надходження,
+// наявний — коригування). Продукти, залишок яких не можна змінити, не записуються і повертаються
+// як відхилені (артикул → помилка): ErrOutOfStock — на основному складі не вистачає товару для зменшення,
+// ErrNoWarehouse — немає активної локації.
+
+func (r *productRepo) UpsertBySKU(ctx context.Context, products []models.Product) (map[string]error, error) {
+	if len(products) == 0 {
+		return nil, nil
+	}
+	skus := make([]string, len(products))
+	for i, p := range products {
+		skus[i] = p.SKU
+	}
+	onConflict := clause.OnConflict{
+		Columns: []clause.Column{{Name: "sku"}},
+		DoUpdates: append(clause.AssignmentColumns(importColumns), clause.Assignments(map[string]interface{}{
+			"version":    gorm.Expr("products.version + 1"),
+			"deleted_at": nil,
+		})...),
+	}
+	rejected := make(map[string]error)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []models.Product
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "sku", "stock").
+			Where("sku IN ?", skus).Order("id").Find(&existing).Error; err != nil {
+			return err
+		}
+		before := make(map[string]int, len(existing))
+		ids := make(map[string]uint, len(existing))
+		for _, p := range existing {
+			before[p.SKU], ids[p.SKU] = p.Stock, p.ID
+		}
+
+		if err := rejectStockChanges(tx, products, before, ids, rejected); err != nil {
+			return err
+		}
+		accepted := make([]models.Product, 0, len(products))
+		for _, p := range products {
+			if rejected[p.SKU] == nil {
+				accepted = append(accepted, p)
+			}
+		}
+		if len(accepted) == 0 {
+			return nil
+		}
+
+		// Новий продукт вставляється з нульовим залишком, у наявного stock не оновлюється (немає в importColumns)
+
+		target := make([]int, len(accepted))
+		for i := range accepted {
+			target[i] = accepted[i].Stock
+			accepted[i].Stock = before[accepted[i].SKU]
+		}
+		if err := tx.Omit(clause.Associations).Clauses(onConflict).Create(&accepted).Error; err != nil {
+			return err
+		}
+
+		actor := actorFromContext(ctx)
+		for i := range accepted {
+			p := &accepted[i]
+			if target[i] == p.Stock {
+				continue
+			}
+			m := models.StockMovement{ProductID: p.ID, Type: models.StockAdjustment, Quantity: target[i] - p.Stock, Reason: "import", ActorID: actor}
+			if _, ok := before[p.SKU]; !ok {
+				m.Type = models.StockReceipt
+			}
+			if err := applyStockMovement(tx, &m); err != nil {
+				return err
+			}
+			p.Stock = m.StockAfter
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rejected, nil
+}
+
+// rejectStockChanges відбирає продукти, залишок яких не можна змінити на основному складі:
+// без активної локації — ErrNoWarehouse, зменшення більше за залишок основного складу — ErrOutOfStock.
+// Рядки залишків основного складу блокуються, тож перевірка дійсна до кінця транзакції.
+
+func rejectStockChanges(tx *gorm.DB, products []models.Product, before map[string]int, ids map[string]uint, rejected map[string]error) error {
+	var changed, decreased []models.Product
+	for _, p := range products {
+		if p.Stock != before[p.SKU] {
+			changed = append(changed, p)
+		}
+		if p.Stock < before[p.SKU] {
+			decreased = append(decreased, p)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	warehouseID, err := primaryWarehouse(tx)
+	if errors.Is(err, ErrNoWarehouse) {
+		for _, p := range changed {
+			rejected[p.SKU] = ErrNoWarehouse
+		}
+		return nil
+	}
+	if err != nil || len(decreased) == 0 {
+		return err
+	}
+
+	productIDs := make([]uint, len(decreased))
+	for i, p := range decreased {
+		productIDs[i] = ids[p.SKU]
+	}
+	var rows []models.WarehouseStock
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("warehouse_id = ? AND product_id IN ?", warehouseID, productIDs).
+		Order("product_id").Find(&rows).Error; err != nil {
+		return err
+	}
+	available := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		available[row.ProductID] = row.Quantity
+	}
+	for _, p := range decreased {
+		if available[ids[p.SKU]] < before[p.SKU]-p.Stock {
+			rejected[p.SKU] = ErrOutOfStock
+		}
+	}
+	return nil
 }
 
-// Delete видаляє продукт за ID
+// Each передає fn продукти пачками по batchSize у порядку id; помилка fn зупиняє обхід
 
-func (r *productRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&models.Product{}, id).Error
+func (r *productRepo) Each(ctx context.Context, batchSize int, fn func([]models.Product) error) error {
+	var batch []models.Product
+	return r.db.WithContext(ctx).Preload("Categories").FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
 }
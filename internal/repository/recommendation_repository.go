@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// excludedOrderStatuses — замовлення, які не вважаються покупками для рекомендацій
+
+var excludedOrderStatuses = []string{models.OrderStatusCancelled, models.OrderStatusRefunded}
+
+// RecommendationRepository читає дані для моделі рекомендацій: ознаки каталогу і кошики замовлень
+
+type RecommendationRepository interface {
+	Catalog(ctx context.Context) ([]models.Product, error)          // усі продукти лише з ID, залишком і атрибутами
+	Baskets(ctx context.Context, since time.Time) ([][]uint, error) // продукти кожного замовлення, оформленого не раніше since
+	PurchasedBy(ctx context.Context, userID uint) ([]uint, error)   // продукти з усіх замовлень користувача
+}
+
+// recommendationRepo реалізує RecommendationRepository
+
+type recommendationRepo struct {
+	db *gorm.DB
+}
+
+// NewRecommendationRepository створює новий RecommendationRepository
+
+func NewRecommendationRepository(db *gorm.DB) RecommendationRepository {
+	return &recommendationRepo{db: db}
+}
+
+// Catalog повертає ознаки всіх продуктів (без видалених) із залишком, який можна продати (SellableStock)
+
+func (r *recommendationRepo) Catalog(ctx context.Context) ([]models.Product, error) {
+	var items []models.Product
+	if err := r.db.WithContext(ctx).Select("id", "attributes").Preload("Availability", inStockByPriority).Order("id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	for i := range items {
+		setSellable(&items[i])
+	}
+	return items, nil
+}
+
+// Baskets повертає продукти замовлень за період (скасовані й повернуті не враховуються)
+
+func (r *recommendationRepo) Baskets(ctx context.Context, since time.Time) ([][]uint, error) {
+	var rows []struct {
+		OrderID   uint
+		ProductID uint
+	}
+	err := r.db.WithContext(ctx).Table("order_items").
+		Select("order_items.order_id, order_items.product_id").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.created_at >= ? AND orders.status NOT IN ?", since, excludedOrderStatuses).
+		Order("order_items.order_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var baskets [][]uint
+	for i, row := range rows {
+		if i == 0 || rows[i-1].OrderID != row.OrderID {
+			baskets = append(baskets, nil)
+		}
+		baskets[len(baskets)-1] = append(baskets[len(baskets)-1], row.ProductID)
+	}
+	return baskets, nil
+}
+
+// PurchasedBy повертає продукти, які користувач уже купував
+
+func (r *recommendationRepo) PurchasedBy(ctx context.Context, userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Table("order_items").
+		Distinct("order_items.product_id").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.user_id = ? AND orders.status NOT IN ?", userID, excludedOrderStatuses).
+		Pluck("order_items.product_id", &ids).Error
+	return ids, err
+}
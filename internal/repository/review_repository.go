@@ -0,0 +1,244 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrReviewChanged — відгук змінився після того, як модератор його переглянув
+
+var ErrReviewChanged = errors.New("review changed since it was read")
+
+// purchasedOrderStatuses — замовлення, після яких відгук вважається підтвердженою покупкою
+
+var purchasedOrderStatuses = []string{models.OrderStatusPaid, models.OrderStatusPacked, models.OrderStatusShipped, models.OrderStatusDelivered}
+
+// ReviewFilter — параметри списку відгуків
+
+type ReviewFilter struct {
+	ProductID uint   // 0 — відгуки всіх продуктів
+	Status    string // "" — усі стани
+	Sort      string // -created_at (за замовчуванням), created_at, rating, -rating
+	Limit     int
+	Offset    int
+}
+
+// reviewSortColumns — дозволені значення ReviewFilter.Sort
+
+var reviewSortColumns = map[string]string{
+	"":            "reviews.created_at DESC, reviews.id DESC",
+	"-created_at": "reviews.created_at DESC, reviews.id DESC",
+	"created_at":  "reviews.created_at, reviews.id",
+	"rating":      "reviews.rating, reviews.id",
+	"-rating":     "reviews.rating DESC, reviews.id DESC",
+}
+
+// IsValidReviewSort перевіряє, чи підтримується значення сортування відгуків
+
+func IsValidReviewSort(sort string) bool {
+	_, ok := reviewSortColumns[sort]
+	return ok
+}
+
+// ReviewRepository визначає методи для роботи з відгуками.
+// Зміни, що зачіпають схвалені відгуки, в тій самій транзакції перераховують Product.RatingAvg / RatingCount.
+
+type ReviewRepository interface {
+	Create(ctx context.Context, rv *models.Review) error                                  // gorm.ErrDuplicatedKey, якщо користувач уже залишив відгук про продукт
+	GetByID(ctx context.Context, id uint) (*models.Review, error)                         // gorm.ErrRecordNotFound якщо не знайдено
+	GetByUserProduct(ctx context.Context, userID, productID uint) (*models.Review, error) // gorm.ErrRecordNotFound якщо не знайдено
+	List(ctx context.Context, f ReviewFilter) ([]models.Review, int64, error)             // returns items, totalCount
+	Update(ctx context.Context, rv *models.Review) error                                  // зберігає зміни автора: оцінку, текст і стан модерації
+	Moderate(ctx context.Context, rv *models.Review, seen time.Time) error                // зберігає лише рішення модератора; ErrReviewChanged, якщо updated_at уже не seen
+	Delete(ctx context.Context, id uint) error                                            // gorm.ErrRecordNotFound якщо не знайдено
+	HasPurchased(ctx context.Context, userID, productID uint) (bool, error)               // чи є оплачене замовлення користувача з цим продуктом
+}
+
+// reviewRepo реалізує ReviewRepository
+
+type reviewRepo struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository створює новий ReviewRepository
+
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepo{db: db}
+}
+
+// withAuthor додає до запиту ім'я автора відгуку (Review.Username)
+
+func withAuthor(q *gorm.DB) *gorm.DB {
+	return q.Select("reviews.*, users.username").Joins("LEFT JOIN users ON users.id = reviews.user_id")
+}
+
+// Create додає відгук; схвалений одразу відгук враховується в рейтингу продукту
+
+func (r *reviewRepo) Create(ctx context.Context, rv *models.Review) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(rv).Error; err != nil {
+			return err
+		}
+		if rv.Status == models.ReviewApproved {
+			return refreshProductRating(tx, rv.ProductID)
+		}
+		return nil
+	})
+}
+
+// GetByID шукає відгук за ID
+
+func (r *reviewRepo) GetByID(ctx context.Context, id uint) (*models.Review, error) {
+	var rv models.Review
+	if err := withAuthor(r.db.WithContext(ctx).Model(&models.Review{})).Where("reviews.id = ?", id).First(&rv).Error; err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+// GetByUserProduct шукає відгук користувача про продукт
+
+func (r *reviewRepo) GetByUserProduct(ctx context.Context, userID, productID uint) (*models.Review, error) {
+	var rv models.Review
+	err := withAuthor(r.db.WithContext(ctx).Model(&models.Review{})).
+		Where("reviews.user_id = ? AND reviews.product_id = ?", userID, productID).First(&rv).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+// List повертає відгуки з фільтрами, сортуванням і пагінацією
+
+func (r *reviewRepo) List(ctx context.Context, f ReviewFilter) ([]models.Review, int64, error) {
+	var items []models.Review
+	var total int64
+	q := r.db.WithContext(ctx).Model(&models.Review{})
+	if f.ProductID != 0 {
+		q = q.Where("reviews.product_id = ?", f.ProductID)
+	}
+	if f.Status != "" {
+		q = q.Where("reviews.status = ?", f.Status)
+	}
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := withAuthor(q).Order(reviewSortColumns[f.Sort]).Limit(f.Limit).Offset(f.Offset).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// Update зберігає відгук. Рядок блокується, щоб паралельна модерація не розійшлася з рейтингом;
+// рейтинг перераховується, якщо відгук був або став схваленим.
+
+func (r *reviewRepo) Update(ctx context.Context, rv *models.Review) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.Review
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "product_id", "status").First(&current, rv.ID).Error; err != nil {
+			return err
+		}
+		err := tx.Model(rv).Select("rating", "text", "verified_purchase", "status", "moderation_note", "moderated_by", "moderated_at", "updated_at").Updates(rv).Error
+		if err != nil {
+			return err
+		}
+		if current.Status == models.ReviewApproved || rv.Status == models.ReviewApproved {
+			return refreshProductRating(tx, current.ProductID)
+		}
+		return nil
+	})
+}
+
+// Moderate зберігає рішення модератора (стан, коментар, хто і коли) — оцінка і текст не чіпаються.
+// Запис умовний: якщо автор змінив відгук після того, як модератор його побачив (updated_at ≠ seen),
+// повертає ErrReviewChanged. Після збереження rv містить актуальний відгук.
+
+func (r *reviewRepo) Moderate(ctx context.Context, rv *models.Review, seen time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.Review
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "product_id", "status", "updated_at").First(&current, rv.ID).Error; err != nil {
+			return err
+		}
+		if !current.UpdatedAt.Truncate(time.Microsecond).Equal(seen.Truncate(time.Microsecond)) {
+			return ErrReviewChanged
+		}
+		err := tx.Model(rv).Select("status", "moderation_note", "moderated_by", "moderated_at", "updated_at").Updates(rv).Error
+		if err != nil {
+			return err
+		}
+		if current.Status == models.ReviewApproved || rv.Status == models.ReviewApproved {
+			return refreshProductRating(tx, current.ProductID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return withAuthor(r.db.WithContext(ctx).Model(&models.Review{})).Where("reviews.id = ?", rv.ID).First(rv).Error
+}
+
+// Delete видаляє відгук; видалення схваленого відгуку перераховує рейтинг продукту
+
+func (r *reviewRepo) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.Review
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "product_id", "status").First(&current, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Review{}, id).Error; err != nil {
+			return err
+		}
+		if current.Status == models.ReviewApproved {
+			return refreshProductRating(tx, current.ProductID)
+		}
+		return nil
+	})
+}
+
+// HasPurchased перевіряє, чи купував користувач продукт (оплачене, не скасоване і не повернуте замовлення)
+
+func (r *reviewRepo) HasPurchased(ctx context.Context, userID, productID uint) (bool, error) {
+	var found bool
+	err := r.db.WithContext(ctx).Raw(
+		`SELECT EXISTS (SELECT 1 FROM order_items oi JOIN orders o ON o.id = oi.order_id
+		 WHERE o.user_id = ? AND oi.product_id = ? AND o.status IN ?)`,
+		userID, productID, purchasedOrderStatuses,
+	).Scan(&found).Error
+	return found, err
+}
+
+// refreshProductRating перераховує середню оцінку і кількість схвалених відгуків продукту
+// і збільшує його версію — рейтинг входить у відповідь продукту, тому змінюється і ETag.
+// Рядок продукту блокується до підрахунку: паралельна модерація іншого відгуку чекає на блокування,
+// а підрахунок окремим запитом уже бачить її зафіксовані зміни.
+
+func refreshProductRating(tx *gorm.DB, productID uint) error {
+	var locked struct{ ID uint }
+	if err := tx.Unscoped().Model(&models.Product{}).Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", productID).Take(&locked).Error; err != nil {
+		return err
+	}
+
+	var agg struct {
+		Avg   float64
+		Count int
+	}
+	err := tx.Model(&models.Review{}).
+		Select("COALESCE(ROUND(AVG(rating), 2), 0) AS avg, COUNT(*) AS count").
+		Where("product_id = ? AND status = ?", productID, models.ReviewApproved).
+		Scan(&agg).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Model(&models.Product{}).Where("id = ?", productID).Updates(map[string]interface{}{
+		"rating_avg":   agg.Avg,
+		"rating_count": agg.Count,
+		"version":      gorm.Expr("version + 1"),
+		"updated_at":   time.Now(),
+	}).Error
+}
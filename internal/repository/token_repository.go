@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TokenRepository визначає методи для роботи з refresh токенами та списком відкликаних access токенів (jti).
+// Всі методи використовують WithContext(ctx) — корисно для таймаутів/тестів.
+
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, t *models.RefreshToken) error                              // t.ID заповнюється автоматично
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error)              // шукає refresh токен за хешем
+	RotateRefreshToken(ctx context.Context, old *models.RefreshToken, next *models.RefreshToken) error // відкликає old і створює next в одній транзакції
+	RevokeRefreshToken(ctx context.Context, id uint) error                                             // відкликає refresh токен
+	RevokeAllRefreshTokens(ctx context.Context, userID uint) error                                     // відкликає всі активні refresh токени користувача
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error                      // додає jti до списку відкликаних
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)                                // перевіряє, чи jti відкликано
+	PurgeExpired(ctx context.Context) error                                                            // видаляє записи, термін дії яких минув
+}
+
+// tokenRepo реалізує TokenRepository
+
+type tokenRepo struct {
+	db *gorm.DB
+}
+
+// NewTokenRepository створює новий TokenRepository
+
+func NewTokenRepository(db *gorm.DB) TokenRepository {
+	return &tokenRepo{db: db}
+}
+
+// CreateRefreshToken зберігає новий refresh токен (лише хеш)
+
+func (r *tokenRepo) CreateRefreshToken(ctx context.Context, t *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(t).Error
+}
+
+// GetRefreshTokenByHash шукає refresh токен за SHA-256 хешем
+
+func (r *tokenRepo) GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var t models.RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// RotateRefreshToken відкликає старий токен і створює новий атомарно.
+// Умова revoked_at IS NULL гарантує, що один і той самий токен не можна обміняти двічі паралельно.
+
+func (r *tokenRepo) RotateRefreshToken(ctx context.Context, old *models.RefreshToken, next *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", old.ID).
+			Updates(map[string]interface{}{"revoked_at": time.Now(), "replaced_by": next.ID})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound // токен вже було використано іншим запитом
+		}
+		return nil
+	})
+}
+
+// RevokeRefreshToken відкликає refresh токен за ID
+
+func (r *tokenRepo) RevokeRefreshToken(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAllRefreshTokens відкликає всі активні refresh токени користувача (наприклад, при підозрі на крадіжку)
+
+func (r *tokenRepo) RevokeAllRefreshTokens(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAccessToken додає jti до списку відкликаних (повторне відкликання ігнорується)
+
+func (r *tokenRepo) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.RevokedToken{JTI: jti, ExpiresAt: expiresAt}).Error
+}
+
+// IsAccessTokenRevoked перевіряє, чи є jti у списку відкликаних
+
+func (r *tokenRepo) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// PurgeExpired видаляє прострочені refresh токени та записи відкликаних jti (вони вже недійсні за exp)
+
+func (r *tokenRepo) PurgeExpired(ctx context.Context) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.RevokedToken{}).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.RefreshToken{}).Error
+}
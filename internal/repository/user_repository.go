@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/pagination"
 	"gorm.io/gorm"
 )
 
@@ -14,15 +15,16 @@ import (
 // UserRepository визначає методи для роботи з користувачами (створення, пошук за email).
 
 type UserRepository interface {
-	Create(ctx context.Context, u *models.User) error                    // створює нового користувача
-	GetByEmail(ctx context.Context, email string) (*models.User, error)  // шукає користувача за email
-	GetByUsername(username string) (*models.User, error)                 // шукає користувача за username
-	GetByID(id uint) (*models.User, error)                               // шукає користувача за ID
-	GetAll(ctx context.Context) ([]models.User, error)                   // отримує всіх користувачів з бази даних
-	UpdatePassword(id uint, hashedPassword string) error                 // оновлює пароль користувача за його ID
-	UpdateProfile(id uint, username, email string) (*models.User, error) // оновлює дані користувача (username, email)
-	Update(ctx context.Context, user *models.User) error                 // оновлює користувача в базі даних
-	Delete(ctx context.Context, id uint) error                           // видаляє користувача з бази даних
+	Create(ctx context.Context, u *models.User) error                                                                         // створює нового користувача
+	GetByEmail(ctx context.Context, email string) (*models.User, error)                                                       // шукає користувача за email
+	GetByUsername(username string) (*models.User, error)                                                                      // шукає користувача за username
+	GetByID(id uint) (*models.User, error)                                                                                    // шукає користувача за ID
+	GetAll(ctx context.Context) ([]models.User, error)                                                                        // отримує всіх користувачів з бази даних
+	ListByCursor(ctx context.Context, c *pagination.Cursor, limit int, withTotal bool) (*pagination.Page[models.User], error) // сторінка користувачів за id (keyset-пагінація)
+	UpdatePassword(id uint, hashedPassword string) error                                                                      // оновлює пароль користувача за його ID
+	UpdateProfile(id uint, username, email string) (*models.User, error)                                                      // оновлює дані користувача (username, email)
+	Update(ctx context.Context, user *models.User) error                                                                      // оновлює користувача в базі даних
+	Delete(ctx context.Context, id uint) error                                                                                // видаляє користувача з бази даних
 }
 
 // userRepo реалізує UserRepository
@@ -84,6 +86,32 @@ func (r *userRepo) GetAll(ctx context.Context) ([]models.User, error) {
 	return users, nil
 }
 
+// ListByCursor повертає сторінку користувачів, упорядкованих за id, після (або перед) курсором c.
+// withTotal — додатково порахувати загальну кількість користувачів.
+
+func (r *userRepo) ListByCursor(ctx context.Context, c *pagination.Cursor, limit int, withTotal bool) (*pagination.Page[models.User], error) {
+	var total *int64
+	if withTotal {
+		var n int64
+		if err := r.db.WithContext(ctx).Model(&models.User{}).Count(&n).Error; err != nil {
+			return nil, err
+		}
+		total = &n
+	}
+
+	var users []models.User
+	order := keysetOrder{column: "id"}
+	if err := order.seek(r.db.WithContext(ctx), c, nil, limit).Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	page := pagination.BuildPage(users, c, limit, func(u models.User) pagination.Cursor {
+		return pagination.Cursor{ID: u.ID}
+	})
+	page.Total = total
+	return page, nil
+}
+
 // UpdateProfile оновлює дані користувача (username, email) за його ID
 func (r *userRepo) UpdateProfile(id uint, username, email string) (*models.User, error) {
 	var user models.User                                // створюємо змінну для збереження користувача
@@ -123,5 +151,12 @@ func (r *userRepo) UpdatePassword(id uint, hashedPassword string) error {
 // Delete видаляє користувача з бази даних за його ID
 
 func (r *userRepo) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&models.User{}, id).Error // видаляємо користувача за ID з бази даних
+	res := r.db.WithContext(ctx).Delete(&models.User{}, id) // видаляємо користувача за ID з бази даних
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound // користувача немає або його вже видалено
+	}
+	return nil
 }
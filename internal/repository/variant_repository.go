@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// VariantRepository визначає методи для роботи з варіантами продуктів.
+// Кожна зміна варіанту збільшує версію продукту (ETag продукту включає варіанти).
+
+type VariantRepository interface {
+	ListByProduct(ctx context.Context, productID uint) ([]models.ProductVariant, error) // варіанти продукту за id
+	GetByID(ctx context.Context, productID, id uint) (*models.ProductVariant, error)    // gorm.ErrRecordNotFound якщо варіанту немає в цього продукту
+	Create(ctx context.Context, v *models.ProductVariant) error                         // gorm.ErrRecordNotFound якщо продукту немає; gorm.ErrDuplicatedKey якщо артикул зайнятий
+	Update(ctx context.Context, v *models.ProductVariant) error                         // змінює артикул, атрибути, ціну і залишок
+	Delete(ctx context.Context, productID, id uint) error                               // gorm.ErrRecordNotFound якщо варіанту немає
+}
+
+// variantRepo реалізує VariantRepository
+
+type variantRepo struct {
+	db *gorm.DB
+}
+
+// NewVariantRepository створює новий VariantRepository
+
+func NewVariantRepository(db *gorm.DB) VariantRepository {
+	return &variantRepo{db: db}
+}
+
+// ListByProduct повертає варіанти продукту
+
+func (r *variantRepo) ListByProduct(ctx context.Context, productID uint) ([]models.ProductVariant, error) {
+	var items []models.ProductVariant
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetByID шукає варіант за ID в межах продукту
+
+func (r *variantRepo) GetByID(ctx context.Context, productID, id uint) (*models.ProductVariant, error) {
+	var v models.ProductVariant
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).First(&v, id).Error; err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Create додає варіант і збільшує версію продукту в одній транзакції
+
+func (r *variantRepo) Create(ctx context.Context, v *models.ProductVariant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bumpProductVersion(tx, v.ProductID); err != nil {
+			return err
+		}
+		return tx.Create(v).Error
+	})
+}
+
+// Update змінює варіант і збільшує версію продукту в одній транзакції
+
+func (r *variantRepo) Update(ctx context.Context, v *models.ProductVariant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bumpProductVersion(tx, v.ProductID); err != nil {
+			return err
+		}
+		res := tx.Model(v).Where("product_id = ?", v.ProductID).
+			Select("sku", "options", "price_cents", "stock", "updated_at").Updates(v)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// Delete видаляє варіант і збільшує версію продукту в одній транзакції
+
+func (r *variantRepo) Delete(ctx context.Context, productID, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := bumpProductVersion(tx, productID); err != nil {
+			return err
+		}
+		res := tx.Where("product_id = ?", productID).Delete(&models.ProductVariant{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
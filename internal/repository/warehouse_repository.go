@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// WarehouseRepository визначає методи для роботи зі складськими локаціями.
+// Залишки в локаціях змінюються лише рухами журналу складу (InventoryRepository).
+
+type WarehouseRepository interface {
+	List(ctx context.Context, activeOnly bool) ([]models.Warehouse, error) // локації за пріоритетом
+	GetByID(ctx context.Context, id uint) (*models.Warehouse, error)       // gorm.ErrRecordNotFound якщо не знайдено
+	Create(ctx context.Context, w *models.Warehouse) error                 // w.ID заповнюється автоматично; gorm.ErrDuplicatedKey якщо код зайнятий
+	Update(ctx context.Context, w *models.Warehouse) error                 // змінює всі поля, крім ID і часу створення
+	StockTotal(ctx context.Context, id uint) (int64, error)                // загальна кількість товару в локації
+}
+
+// warehouseRepo реалізує WarehouseRepository
+
+type warehouseRepo struct {
+	db *gorm.DB
+}
+
+// NewWarehouseRepository створює новий WarehouseRepository
+
+func NewWarehouseRepository(db *gorm.DB) WarehouseRepository {
+	return &warehouseRepo{db: db}
+}
+
+// List повертає локації в порядку вибору для замовлень
+
+func (r *warehouseRepo) List(ctx context.Context, activeOnly bool) ([]models.Warehouse, error) {
+	var items []models.Warehouse
+	q := r.db.WithContext(ctx)
+	if activeOnly {
+		q = q.Where("active = ?", true)
+	}
+	if err := q.Order("priority, id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetByID шукає локацію за ID
+
+func (r *warehouseRepo) GetByID(ctx context.Context, id uint) (*models.Warehouse, error) {
+	var w models.Warehouse
+	if err := r.db.WithContext(ctx).First(&w, id).Error; err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// Create додає нову локацію
+
+func (r *warehouseRepo) Create(ctx context.Context, w *models.Warehouse) error {
+	return r.db.WithContext(ctx).Create(w).Error
+}
+
+// Update змінює дані локації. Активність і пріоритет впливають на наявність
+// продуктів, тому версії продуктів із залишком у локації зростають у тій самій транзакції.
+
+func (r *warehouseRepo) Update(ctx context.Context, w *models.Warehouse) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(w).Select("*").Omit("id", "created_at").Updates(w).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Product{}).
+			Where("id IN (SELECT product_id FROM warehouse_stock WHERE warehouse_id = ? AND quantity > 0)", w.ID).
+			Updates(map[string]interface{}{"version": gorm.Expr("version + 1"), "updated_at": gorm.Expr("now()")}).Error
+	})
+}
+
+// StockTotal рахує товар у локації (перед деактивацією вона має бути порожньою)
+
+func (r *warehouseRepo) StockTotal(ctx context.Context, id uint) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&models.WarehouseStock{}).Where("warehouse_id = ?", id).
+		Select("COALESCE(SUM(quantity), 0)").Scan(&total).Error
+	return total, err
+}
@@ -1,38 +1,94 @@
 package routes
 
 import (
+	"strings"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/config"
 	"github.com/AlexRijikov/go-petshop-api/internal/handler"
+	"github.com/AlexRijikov/go-petshop-api/internal/keys"
 	"github.com/AlexRijikov/go-petshop-api/internal/middleware"
 	"github.com/AlexRijikov/go-petshop-api/internal/repository"
 	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"github.com/AlexRijikov/go-petshop-api/internal/storage"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 // RegisterRoutes реєструє всі маршрути (ендпоінти) для продуктів та аутентифікації
+// km — спільний менеджер ключів JWT (підпис у AuthService, перевірка в AuthMiddleware, JWKS)
+// store — сховище завантажених файлів (зображення продуктів)
+// recommender — персональні рекомендації (модель у пам'яті оновлює main)
+
+func RegisterRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config, km *keys.Manager, store storage.Storage, recommender services.RecommendationService) {
+	// Центральний обробник помилок — першим, щоб рендерити помилки всіх handlers і middleware (problem+json)
+	r.Use(middleware.ErrorHandler())
 
-func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
 	// Групуємо всі маршрути під префіксом /api
 	api := r.Group("/api")
 
 	// AUTH - маршрути для реєстрації, входу, виходу  (реєстрація, логін) — публічні маршрути (без авторизації)
 
-	userRepo := repositories.NewUserRepository(db)  // створюємо репозиторій користувачів
-	authSvc := services.NewAuthService(userRepo)    // створюємо сервіс аутентифікації з репозиторієм користувачів
-	authHandler := handlers.NewAuthHandler(authSvc) // створюємо хендлер аутентифікації з сервісом аутентифікації
-	authHandler.RegisterRoutes(api)
+	userRepo := repositories.NewUserRepository(db)                       // створюємо репозиторій користувачів
+	tokenRepo := repositories.NewTokenRepository(db)                     // створюємо репозиторій refresh токенів і відкликаних jti
+	authSvc := services.NewAuthService(userRepo, tokenRepo, km, cfg.JWT) // створюємо сервіс аутентифікації з репозиторіями та налаштуваннями JWT
+	authHandler := handlers.NewAuthHandler(authSvc)                      // створюємо хендлер аутентифікації з сервісом аутентифікації
+
+	authMiddleware := middleware.AuthMiddleware(km.Keyfunc, authSvc) // створюємо middleware для авторизації (перевірка JWT і списку відкликаних)
+	adminOnly := middleware.RequireRole(middleware.RoleAdmin)        // доступ лише для адміністраторів
+
+	authHandler.RegisterRoutes(api, authMiddleware)
+
+	// PRODUCTS - маршрути для роботи з товарами — читання публічне, створення/оновлення/видалення лише для admin
+
+	productRepo := repositories.NewProductRepository(db)                // створюємо репозиторій продуктів
+	categoryRepo := repositories.NewCategoryRepository(db)              // створюємо репозиторій категорій (схеми атрибутів продуктів)
+	productSvc := services.NewProductService(productRepo, categoryRepo) // створюємо сервіс продуктів з репозиторіями продуктів і категорій
+	productHandler := handlers.NewProductHandler(productSvc)            // створюємо хендлер продуктів з сервісом продуктів
+	productHandler.RegisterRoutes(api, authMiddleware, adminOnly)
+
+	variantRepo := repositories.NewVariantRepository(db)               // створюємо репозиторій варіантів продуктів
+	variantSvc := services.NewVariantService(variantRepo, productRepo) // створюємо сервіс варіантів (ціна > 0, унікальні атрибути)
+	handlers.NewVariantHandler(variantSvc).RegisterRoutes(api, authMiddleware, adminOnly)
+
+	imageRepo := repositories.NewImageRepository(db)                                               // створюємо репозиторій зображень продуктів
+	imageSvc := services.NewImageService(imageRepo, productRepo, store, cfg.Storage.MaxUploadSize) // створюємо сервіс зображень (перевірка файлів, мініатюри)
+	handlers.NewImageHandler(imageSvc, cfg.Storage.MaxUploadSize).RegisterRoutes(api, authMiddleware, adminOnly)
+
+	// Файли локального сховища роздає сам сервер (для зовнішнього сховища STORAGE_PUBLIC_URL — його адреса)
+
+	if local, ok := store.(*storage.Local); ok && strings.HasPrefix(cfg.Storage.PublicURL, "/") {
+		r.StaticFS(cfg.Storage.PublicURL, gin.Dir(local.Dir(), false))
+	}
+
+	// CATEGORIES - ієрархічна таксономія каталогу — читання публічне, зміни і категорії продуктів лише для admin
+
+	categorySvc := services.NewCategoryService(categoryRepo, productRepo)   // створюємо сервіс категорій (slug, дерево, перевірка циклів)
+	categoryHandler := handlers.NewCategoryHandler(categorySvc, productSvc) // створюємо хендлер категорій (продукти категорії — через сервіс продуктів)
+	categoryHandler.RegisterRoutes(api, authMiddleware, adminOnly)
+
+	// WAREHOUSES - складські локації (склад, магазини) — читання публічне, зміни лише для admin
+
+	warehouseRepo := repositories.NewWarehouseRepository(db) // створюємо репозиторій локацій
+	handlers.NewWarehouseHandler(services.NewWarehouseService(warehouseRepo)).RegisterRoutes(api, authMiddleware, adminOnly)
 
-	// PRODUCTS - маршрути для роботи з товарами ( отримання списку продуктів, створення продукту тощо) — публічні маршрути (без авторизації)
+	// CART - кошик поточного користувача — захищені маршрути AuthMiddleware (перевірка JWT)
 
-	productRepo := repositories.NewProductRepository(db)     // створюємо репозиторій продуктів
-	productSvc := services.NewProductService(productRepo)    // створюємо сервіс продуктів з репозиторієм продуктів
-	productHandler := handlers.NewProductHandler(productSvc) // створюємо хендлер продуктів з сервісом продуктів
-	productHandler.RegisterRoutes(api)                       // реєструємо маршрути продуктів
+	cartRepo := repositories.NewCartRepository(db)                         // створюємо репозиторій кошиків
+	cartSvc := services.NewCartService(cartRepo, productRepo, variantRepo) // створюємо сервіс кошика (перевіряє продукти, варіанти і залишки)
+	cartHandler := handlers.NewCartHandler(cartSvc)                        // створюємо хендлер кошика
+	cartHandler.RegisterRoutes(api, authMiddleware)
+
+	// ORDERS - оформлення та перегляд власних замовлень — захищені маршрути AuthMiddleware (перевірка JWT)
+
+	orderRepo := repositories.NewOrderRepository(db)          // створюємо репозиторій замовлень
+	orderSvc := services.NewOrderService(orderRepo, cartRepo) // створюємо сервіс замовлень (checkout з кошика)
+	orderHandler := handlers.NewOrderHandler(orderSvc)        // створюємо хендлер замовлень
+	orderHandler.RegisterRoutes(api, authMiddleware)
 
 	// USERS - отримання профілю, оновлення профілю користувача тощо — захищені маршрути AuthMiddleware (перевірка JWT)
 
-	authMiddleware := middleware.AuthMiddleware()    // створюємо middleware для авторизації (перевірка JWT)
-	userHandler := handlers.NewUserHandler(userRepo) // створюємо хендлер користувачів з репозиторієм користувачів
+	userSvc := services.NewUserService(userRepo, tokenRepo) // створюємо сервіс користувачів
+	userHandler := handlers.NewUserHandler(userSvc)         // створюємо хендлер користувачів із сервісом користувачів
 
 	users := api.Group("/users")
 	users.Use(authMiddleware)
@@ -43,6 +99,40 @@ func RegisterRoutes(r *gin.Engine, db *gorm.DB) {
 
 	}
 
+	// REVIEWS - відгуки про продукти — читання схвалених публічне, власний відгук — з AuthMiddleware, модерація — в /api/admin
+
+	reviewSvc := services.NewReviewService(repositories.NewReviewRepository(db), productRepo) // створюємо сервіс відгуків (verified purchase, рейтинг)
+	reviewHandler := handlers.NewReviewHandler(reviewSvc)
+	reviewHandler.RegisterRoutes(api, authMiddleware)
+
+	// PETS - профілі тварин поточного користувача — захищені маршрути AuthMiddleware (перевірка JWT)
+
+	petSvc := services.NewPetService(repositories.NewPetRepository(db)) // створюємо сервіс тварин (перевірка виду, алергії)
+	handlers.NewPetHandler(petSvc).RegisterRoutes(api, authMiddleware)
+	handlers.NewRecommendationHandler(recommender).RegisterRoutes(api, authMiddleware) // рекомендації за тваринами та історією покупок
+
+	// ADMIN - керування користувачами та замовленнями — лише для адміністраторів (AuthMiddleware + RequireRole("admin"))
+
+	admin := api.Group("/admin")
+	admin.Use(authMiddleware, adminOnly)
+	{
+		admin.GET("/users", userHandler.GetAllUsers)
+		admin.DELETE("/users/:id", userHandler.DeleteUser)
+	}
+	orderHandler.RegisterAdminRoutes(admin) // керування статусами замовлень
+
+	importSvc := services.NewImportService(productRepo, categoryRepo) // масовий імпорт/експорт каталогу (CSV, NDJSON)
+	handlers.NewImportHandler(importSvc).RegisterAdminRoutes(admin)
+
+	inventorySvc := services.NewInventoryService(repositories.NewInventoryRepository(db), productRepo, warehouseRepo) // журнал складу — єдиний спосіб змінити залишок
+	handlers.NewInventoryHandler(inventorySvc).RegisterAdminRoutes(admin)
+
+	reviewHandler.RegisterAdminRoutes(admin) // модерація відгуків
+
+	// JWKS - публічні ключі для перевірки токенів іншими сервісами
+
+	handlers.NewJWKSHandler(km).RegisterRoutes(r)
+
 	//  Ping endpoint для перевірки стану сервера (можна видалити в продакшені)
 
 	r.GET("/ping", func(c *gin.Context) {
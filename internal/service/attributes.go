@@ -0,0 +1,189 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+)
+
+// Атрибути продуктів: категорія описує схему (назва, тип, обов'язковість, допустимі значення),
+// продукт зберігає значення в Attributes. Схема категорії діє і для всіх її підкатегорій.
+
+// ErrInvalidAttributes — атрибути продукту не відповідають схемі його категорій
+
+var ErrInvalidAttributes = apperr.Validation("invalid_attributes", "product attributes do not match category schema")
+
+// Обмеження схеми і значень атрибутів
+
+const (
+	maxSchemaAttributes  = 30  // атрибутів у схемі однієї категорії
+	maxEnumValues        = 50  // допустимих значень enum
+	maxAttributeValueLen = 255 // символів у значенні string/enum
+)
+
+// attributeNamePattern — назва атрибута: snake_case, починається з літери (weight_kg)
+
+var attributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// validateAttributeSchema перевіряє схему атрибутів категорії
+
+func validateAttributeSchema(schema models.AttributeSchema) error {
+	if len(schema) > maxSchemaAttributes {
+		return fmt.Errorf("at most %d attributes", maxSchemaAttributes)
+	}
+	names := make(map[string]bool, len(schema))
+	for _, def := range schema {
+		if !attributeNamePattern.MatchString(def.Name) {
+			return fmt.Errorf("attribute name %q must be snake_case (a-z, 0-9, _)", def.Name)
+		}
+		if names[def.Name] {
+			return fmt.Errorf("attribute %q is defined twice", def.Name)
+		}
+		names[def.Name] = true
+		if utf8.RuneCountInString(def.Label) > 100 {
+			return fmt.Errorf("attribute %q: label must be at most 100 characters", def.Name)
+		}
+
+		switch def.Type {
+		case models.AttributeString, models.AttributeNumber, models.AttributeBoolean:
+			if len(def.Values) > 0 {
+				return fmt.Errorf("attribute %q: values are allowed only for enum", def.Name)
+			}
+		case models.AttributeEnum:
+			if len(def.Values) == 0 || len(def.Values) > maxEnumValues {
+				return fmt.Errorf("attribute %q: enum needs 1-%d values", def.Name, maxEnumValues)
+			}
+			seen := make(map[string]bool, len(def.Values))
+			for _, v := range def.Values {
+				if v == "" || utf8.RuneCountInString(v) > maxAttributeValueLen || seen[v] {
+					return fmt.Errorf("attribute %q: enum values must be unique non-empty strings", def.Name)
+				}
+				seen[v] = true
+			}
+		default:
+			return fmt.Errorf("attribute %q: unknown type %q", def.Name, def.Type)
+		}
+	}
+	return nil
+}
+
+// mergeSchemas об'єднує схеми кількох категорій (продукт у кількох категоріях, успадкування від предків).
+// Тип атрибута однаковий в усій таксономії (перевіряє CategoryService), обов'язковість — якщо хоча б в одній,
+// допустимі значення enum об'єднуються.
+
+func mergeSchemas(schemas []models.AttributeSchema) map[string]models.AttributeDef {
+	defs := make(map[string]models.AttributeDef)
+	for _, schema := range schemas {
+		for _, def := range schema {
+			cur, ok := defs[def.Name]
+			if !ok {
+				def.Values = append([]string(nil), def.Values...)
+				defs[def.Name] = def
+				continue
+			}
+			cur.Required = cur.Required || def.Required
+			for _, v := range def.Values {
+				if !containsString(cur.Values, v) {
+					cur.Values = append(cur.Values, v)
+				}
+			}
+			defs[def.Name] = cur
+		}
+	}
+	return defs
+}
+
+// validateAttributes перевіряє значення атрибутів продукту за об'єднаною схемою і нормалізує рядки:
+// невідомі атрибути та значення неправильного типу відхиляються, обов'язкові мають бути присутні.
+
+func validateAttributes(defs map[string]models.AttributeDef, attrs models.Attributes) error {
+	for name, value := range attrs {
+		def, ok := defs[name]
+		if !ok {
+			return ErrInvalidAttributes.WithDetail(fmt.Sprintf("unknown attribute %q for product categories", name))
+		}
+		switch def.Type {
+		case models.AttributeNumber:
+			if _, ok := value.(float64); !ok {
+				return ErrInvalidAttributes.WithDetail(fmt.Sprintf("attribute %q must be a number", name))
+			}
+		case models.AttributeBoolean:
+			if _, ok := value.(bool); !ok {
+				return ErrInvalidAttributes.WithDetail(fmt.Sprintf("attribute %q must be a boolean", name))
+			}
+		default:
+			str, ok := value.(string)
+			str = strings.TrimSpace(str)
+			if !ok || str == "" || utf8.RuneCountInString(str) > maxAttributeValueLen {
+				return ErrInvalidAttributes.WithDetail(fmt.Sprintf("attribute %q must be a non-empty string", name))
+			}
+			if def.Type == models.AttributeEnum && !containsString(def.Values, str) {
+				return ErrInvalidAttributes.WithDetail(fmt.Sprintf("attribute %q must be one of %s", name, strings.Join(def.Values, ", ")))
+			}
+			attrs[name] = str
+		}
+	}
+	for name, def := range defs {
+		if _, ok := attrs[name]; def.Required && !ok {
+			return ErrInvalidAttributes.WithDetail(fmt.Sprintf("attribute %q is required", name))
+		}
+	}
+	return nil
+}
+
+// resolveAttributeFilters перетворює значення фільтрів з query-параметрів (рядки) на типи зі схеми:
+// число для number і min/max, bool для boolean, рядок зі списку для enum
+
+func resolveAttributeFilters(defs map[string]models.AttributeDef, filters []repositories.AttributeFilter) error {
+	for i := range filters {
+		a := &filters[i]
+		def, ok := defs[a.Name]
+		if !ok {
+			return ErrInvalidFilter.WithDetail(fmt.Sprintf("unknown attribute %q", a.Name))
+		}
+		raw, _ := a.Value.(string)
+		if a.Op != repositories.AttrEq && def.Type != models.AttributeNumber {
+			return ErrInvalidFilter.WithDetail(fmt.Sprintf("attribute %q is not numeric, min/max are not supported", a.Name))
+		}
+
+		switch def.Type {
+		case models.AttributeNumber:
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return ErrInvalidFilter.WithDetail(fmt.Sprintf("attribute %q: %q is not a number", a.Name, raw))
+			}
+			a.Value = v
+		case models.AttributeBoolean:
+			v, err := strconv.ParseBool(raw)
+			if err != nil {
+				return ErrInvalidFilter.WithDetail(fmt.Sprintf("attribute %q: %q is not a boolean", a.Name, raw))
+			}
+			a.Value = v
+		case models.AttributeEnum:
+			if !containsString(def.Values, raw) {
+				return ErrInvalidFilter.WithDetail(fmt.Sprintf("attribute %q must be one of %s", a.Name, strings.Join(def.Values, ", ")))
+			}
+			a.Value = raw
+		default:
+			a.Value = raw
+		}
+	}
+	return nil
+}
+
+// containsString перевіряє, чи є рядок у списку
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
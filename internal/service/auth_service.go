@@ -2,73 +2,270 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"time"
 
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/config"
 	"github.com/AlexRijikov/go-petshop-api/internal/models"
 	"github.com/AlexRijikov/go-petshop-api/internal/repository"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
-var jwtKey = []byte("supersecretkey") // потім винесемо у .env
+// Помилки сервісу аутентифікації
 
-// AuthService відповідає за реєстрацію та логін користувачів
+var (
+	ErrInvalidCredentials  = apperr.Unauthorized("invalid_credentials", "invalid email or password")          // невірний email або пароль
+	ErrInvalidRefreshToken = apperr.Unauthorized("invalid_refresh_token", "invalid or expired refresh token") // refresh токен недійсний, прострочений або відкликаний
+	ErrUserExists          = apperr.Conflict("user_already_exists", "user already exists")                    // користувач з таким email вже зареєстрований
+)
+
+// TokenPair — пара токенів, яку отримує клієнт після входу або оновлення
+
+type TokenPair struct {
+	AccessToken  string `json:"token"`         // короткоживучий JWT для заголовка Authorization
+	RefreshToken string `json:"refresh_token"` // одноразовий токен для POST /api/auth/refresh
+	ExpiresIn    int64  `json:"expires_in"`    // час життя access токена в секундах
+}
+
+// TokenSigner підписує JWT claims поточним ключем (реалізується keys.Manager)
+
+type TokenSigner interface {
+	Sign(claims jwt.Claims) (string, error)
+}
+
+// AuthService відповідає за реєстрацію, логін, оновлення токенів та вихід користувачів
 
 type AuthService interface {
-	Register(ctx context.Context, email, password string) error
-	Login(ctx context.Context, email, password string) (string, error)
+	Register(ctx context.Context, email, password string) error                                          // повертає ErrUserExists якщо email зайнятий
+	Login(ctx context.Context, email, password string) (*TokenPair, error)                               // повертає ErrInvalidCredentials якщо email/пароль невірні
+	Refresh(ctx context.Context, refreshToken string) (*TokenPair, error)                                // обмінює refresh токен на нову пару (rotation)
+	Logout(ctx context.Context, userID uint, jti string, expiresAt time.Time, refreshToken string) error // відкликає access токен (jti) і, якщо передано, refresh токен цього ж користувача
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)                                        // перевіряє список відкликаних access токенів
 }
 
 // authService реалізує AuthService
 
 type authService struct {
-	repo repositories.UserRepository
+	repo   repositories.UserRepository
+	tokens repositories.TokenRepository
+	signer TokenSigner      // спільний менеджер ключів JWT
+	cfg    config.JWTConfig // час життя токенів
 }
 
 // NewAuthService створює новий AuthService
 
-func NewAuthService(r repositories.UserRepository) AuthService {
-	return &authService{repo: r}
+func NewAuthService(r repositories.UserRepository, tokens repositories.TokenRepository, signer TokenSigner, cfg config.JWTConfig) AuthService {
+	return &authService{repo: r, tokens: tokens, signer: signer, cfg: cfg}
 }
 
-// Register створює нового користувача з хешованим паролем
+// Register створює нового користувача з хешованим паролем.
+// Конфлікт за username (хтось обрав чужий email як ім'я) також повертає ErrUserExists.
 
 func (s *authService) Register(ctx context.Context, email, password string) error {
 	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
-	// Створюємо користувача
+	// Створюємо користувача; username обов'язковий і унікальний, тому спочатку він дорівнює email
+	// (користувач може змінити його через PUT /api/users/me)
 	user := &models.User{
+		Username: email,
 		Email:    email,
 		Password: string(hashed),
 	}
 	// Зберігаємо користувача в базу даних
-	return s.repo.Create(ctx, user)
+	if err := s.repo.Create(ctx, user); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrUserExists
+		}
+		return err
+	}
+	return nil
 }
 
-// Login перевіряє email і пароль, повертає JWT токен якщо успішно увійшли в систему
+// Login перевіряє email і пароль, повертає access і refresh токени якщо успішно увійшли в систему
 
-func (s *authService) Login(ctx context.Context, email, password string) (string, error) {
+func (s *authService) Login(ctx context.Context, email, password string) (*TokenPair, error) {
 	user, err := s.repo.GetByEmail(ctx, email)
 	if err != nil {
-		return "", errors.New("invalid email or password")
+		return nil, ErrInvalidCredentials
 	}
 	// Перевіряємо пароль
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", errors.New("invalid email or password")
+		return nil, ErrInvalidCredentials
+	}
+
+	refresh, raw, err := s.newRefreshToken(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokens.CreateRefreshToken(ctx, refresh); err != nil {
+		return nil, err
+	}
+	return s.newTokenPair(user, raw)
+}
+
+// Refresh обмінює refresh токен на нову пару токенів.
+// Старий refresh токен відкликається (rotation). Повторне використання вже заміненого
+// токена вважається ознакою крадіжки — тоді відкликаються всі refresh токени користувача.
+
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	old, err := s.tokens.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if old.RevokedAt != nil {
+		// Крадіжкою вважається лише повтор уже заміненого токена; відкликаний виходом токен
+		// (повтор зі старої вкладки чи ретрай клієнта) просто недійсний
+		if old.ReplacedBy != nil {
+			if err := s.tokens.RevokeAllRefreshTokens(ctx, old.UserID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if time.Now().After(old.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Роль могла змінитися з моменту входу — беремо актуальні дані користувача
+
+	user, err := s.repo.GetByID(old.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	next, raw, err := s.newRefreshToken(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokens.RotateRefreshToken(ctx, old, next); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Токен встигли використати паралельно — поводимось як при повторному використанні
+			if err := s.tokens.RevokeAllRefreshTokens(ctx, old.UserID); err != nil {
+				return nil, err
+			}
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	return s.newTokenPair(user, raw)
+}
+
+// Logout відкликає поточний access токен (за jti) та refresh токен, якщо його передано.
+// Refresh токен має належати userID — чужий токен не відкликається (ErrInvalidRefreshToken),
+// і тоді access токен теж залишається дійсним, щоб вихід не виконувався частково.
+
+func (s *authService) Logout(ctx context.Context, userID uint, jti string, expiresAt time.Time, refreshToken string) error {
+	var refresh *models.RefreshToken
+	if refreshToken != "" {
+		t, err := s.tokens.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+		if t.UserID != userID {
+			return ErrInvalidRefreshToken
+		}
+		refresh = t
+	}
+
+	if jti != "" {
+		if err := s.tokens.RevokeAccessToken(ctx, jti, expiresAt); err != nil {
+			return err
+		}
+	}
+	if refresh != nil {
+		if err := s.tokens.RevokeRefreshToken(ctx, refresh.ID); err != nil {
+			return err
+		}
+	}
+
+	// Прибираємо прострочені записи — помилка тут не впливає на результат виходу
+
+	_ = s.tokens.PurgeExpired(ctx)
+	return nil
+}
+
+// IsTokenRevoked перевіряє, чи access токен з вказаним jti було відкликано
+
+func (s *authService) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return s.tokens.IsAccessTokenRevoked(ctx, jti)
+}
+
+// newTokenPair підписує новий access токен і пакує його разом з refresh токеном
+
+func (s *authService) newTokenPair(user *models.User, refreshToken string) (*TokenPair, error) {
+	jti, err := randomString(16)
+	if err != nil {
+		return nil, err
 	}
 
-	// Створюємо JWT токен з user ID і терміном дії 24 години
+	// Створюємо JWT токен з user ID, роллю, унікальним jti і коротким терміном дії
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	now := time.Now()
+	claims := jwt.MapClaims{
 		"user_id": user.ID,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
-	})
+		"role":    user.Role,
+		"jti":     jti,
+		"iat":     now.Unix(),
+		"exp":     now.Add(s.cfg.AccessTTL).Unix(),
+	}
+
+	// Підписуємо токен поточним ключем (алгоритм і kid визначає менеджер ключів)
+
+	signed, err := s.signer.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  signed,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.cfg.AccessTTL.Seconds()),
+	}, nil
+}
+
+// newRefreshToken генерує випадковий refresh токен; у базу потрапляє лише його хеш
+
+func (s *authService) newRefreshToken(userID uint) (*models.RefreshToken, string, error) {
+	raw, err := randomString(32)
+	if err != nil {
+		return nil, "", err
+	}
+	return &models.RefreshToken{
+		UserID:    userID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(s.cfg.RefreshTTL),
+	}, raw, nil
+}
+
+// randomString повертає n криптографічно випадкових байтів у base64url
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
 
-	// Підписуємо токен і повертаємо його
+// hashToken повертає SHA-256 хеш токена у hex (для зберігання в БД)
 
-	return token.SignedString(jwtKey)
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,300 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/config"
+	"github.com/AlexRijikov/go-petshop-api/internal/keys"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/pagination"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// Простий in-memory repo реалізує repositories.UserRepository для тестів.
+
+type memUserRepo struct {
+	data map[uint]*models.User
+	next uint
+}
+
+func newMemUserRepo() *memUserRepo {
+	return &memUserRepo{data: map[uint]*models.User{}, next: 1}
+}
+
+func (m *memUserRepo) Create(ctx context.Context, u *models.User) error {
+	for _, existing := range m.data {
+		if existing.Email == u.Email || existing.Username == u.Username { // унікальні індекси users
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	u.ID = m.next
+	m.next++
+	if u.Role == "" {
+		u.Role = "user"
+	}
+	m.data[u.ID] = u
+	return nil
+}
+
+func (m *memUserRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	for _, u := range m.data {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memUserRepo) GetByUsername(username string) (*models.User, error) {
+	for _, u := range m.data {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memUserRepo) GetByID(id uint) (*models.User, error) {
+	u, ok := m.data[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return u, nil
+}
+
+func (m *memUserRepo) GetAll(ctx context.Context) ([]models.User, error) {
+	var out []models.User
+	for _, u := range m.data {
+		out = append(out, *u)
+	}
+	return out, nil
+}
+
+func (m *memUserRepo) ListByCursor(ctx context.Context, c *pagination.Cursor, limit int, withTotal bool) (*pagination.Page[models.User], error) {
+	return &pagination.Page[models.User]{Items: []models.User{}, Limit: limit}, nil
+}
+
+func (m *memUserRepo) UpdatePassword(id uint, hashedPassword string) error {
+	m.data[id].Password = hashedPassword
+	return nil
+}
+
+func (m *memUserRepo) UpdateProfile(id uint, username, email string) (*models.User, error) {
+	u := m.data[id]
+	u.Username, u.Email = username, email
+	return u, nil
+}
+
+func (m *memUserRepo) Update(ctx context.Context, user *models.User) error {
+	m.data[user.ID] = user
+	return nil
+}
+
+func (m *memUserRepo) Delete(ctx context.Context, id uint) error {
+	delete(m.data, id)
+	return nil
+}
+
+// Простий in-memory repo реалізує repositories.TokenRepository для тестів.
+
+type memTokenRepo struct {
+	refresh map[uint]*models.RefreshToken
+	revoked map[string]time.Time
+	next    uint
+}
+
+func newMemTokenRepo() *memTokenRepo {
+	return &memTokenRepo{refresh: map[uint]*models.RefreshToken{}, revoked: map[string]time.Time{}, next: 1}
+}
+
+func (m *memTokenRepo) CreateRefreshToken(ctx context.Context, t *models.RefreshToken) error {
+	t.ID = m.next
+	m.next++
+	m.refresh[t.ID] = t
+	return nil
+}
+
+func (m *memTokenRepo) GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	for _, t := range m.refresh {
+		if t.TokenHash == hash {
+			cp := *t
+			return &cp, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memTokenRepo) RotateRefreshToken(ctx context.Context, old *models.RefreshToken, next *models.RefreshToken) error {
+	if m.refresh[old.ID].RevokedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	if err := m.CreateRefreshToken(ctx, next); err != nil {
+		return err
+	}
+	now := time.Now()
+	m.refresh[old.ID].RevokedAt = &now
+	m.refresh[old.ID].ReplacedBy = &next.ID
+	return nil
+}
+
+func (m *memTokenRepo) RevokeRefreshToken(ctx context.Context, id uint) error {
+	now := time.Now()
+	m.refresh[id].RevokedAt = &now
+	return nil
+}
+
+func (m *memTokenRepo) RevokeAllRefreshTokens(ctx context.Context, userID uint) error {
+	now := time.Now()
+	for _, t := range m.refresh {
+		if t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *memTokenRepo) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.revoked[jti] = expiresAt
+	return nil
+}
+
+func (m *memTokenRepo) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	_, ok := m.revoked[jti]
+	return ok, nil
+}
+
+func (m *memTokenRepo) PurgeExpired(ctx context.Context) error {
+	return nil
+}
+
+// newTestAuthService створює AuthService з in-memory репозиторіями і зареєстрованим користувачем
+
+func newTestAuthService(t *testing.T) services.AuthService {
+	cfg := config.JWTConfig{
+		Algorithm:  "HS256",
+		Secret:     "test-secret",
+		AccessTTL:  time.Minute,
+		RefreshTTL: time.Hour,
+	}
+	km, err := keys.NewManager(cfg)
+	require.NoError(t, err)
+
+	svc := services.NewAuthService(newMemUserRepo(), newMemTokenRepo(), km, cfg)
+	require.NoError(t, svc.Register(context.Background(), "cat@example.com", "secret123"))
+	return svc
+}
+
+// Тести для AuthService
+
+func TestLoginInvalidPassword(t *testing.T) {
+	svc := newTestAuthService(t)
+
+	_, err := svc.Login(context.Background(), "cat@example.com", "wrong-password")
+	assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+}
+
+// Тест реєстрації: кожен користувач отримує власний username, повторний email — ErrUserExists
+
+func TestRegisterSecondUser(t *testing.T) {
+	svc := newTestAuthService(t)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Register(ctx, "dog@example.com", "secret123"))
+	assert.ErrorIs(t, svc.Register(ctx, "cat@example.com", "other-secret"), services.ErrUserExists)
+
+	_, err := svc.Login(ctx, "dog@example.com", "secret123")
+	assert.NoError(t, err)
+}
+
+// Тест rotation: старий refresh токен після обміну стає недійсним
+
+func TestRefreshRotatesToken(t *testing.T) {
+	svc := newTestAuthService(t)
+	ctx := context.Background()
+
+	pair, err := svc.Login(ctx, "cat@example.com", "secret123")
+	require.NoError(t, err)
+	assert.NotEmpty(t, pair.AccessToken)
+	assert.Equal(t, int64(60), pair.ExpiresIn)
+
+	next, err := svc.Refresh(ctx, pair.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, pair.RefreshToken, next.RefreshToken)
+
+	// Повторне використання старого токена відкликає всі токени користувача, включно з новим
+
+	_, err = svc.Refresh(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+
+	_, err = svc.Refresh(ctx, next.RefreshToken)
+	assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+}
+
+// Тест виходу: access токен потрапляє в список відкликаних, refresh токен більше не працює
+
+func TestLogoutRevokesTokens(t *testing.T) {
+	svc := newTestAuthService(t)
+	ctx := context.Background()
+
+	pair, err := svc.Login(ctx, "cat@example.com", "secret123")
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Logout(ctx, 1, "jti-1", time.Now().Add(time.Minute), pair.RefreshToken))
+
+	revoked, err := svc.IsTokenRevoked(ctx, "jti-1")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	_, err = svc.Refresh(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+}
+
+// Тест: вийти з чужим refresh токеном не можна — ні він, ні access токен не відкликаються
+
+func TestLogoutRejectsForeignRefreshToken(t *testing.T) {
+	svc := newTestAuthService(t)
+	ctx := context.Background()
+
+	pair, err := svc.Login(ctx, "cat@example.com", "secret123")
+	require.NoError(t, err)
+
+	err = svc.Logout(ctx, 2, "jti-2", time.Now().Add(time.Minute), pair.RefreshToken)
+	assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+
+	revoked, err := svc.IsTokenRevoked(ctx, "jti-2")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	_, err = svc.Refresh(ctx, pair.RefreshToken)
+	assert.NoError(t, err)
+}
+
+// Тест: повтор токена, відкликаного виходом, не виходить з усіх пристроїв; повтор заміненого — виходить
+
+func TestRefreshReuseDetection(t *testing.T) {
+	svc := newTestAuthService(t)
+	ctx := context.Background()
+
+	phone, err := svc.Login(ctx, "cat@example.com", "secret123")
+	require.NoError(t, err)
+	laptop, err := svc.Login(ctx, "cat@example.com", "secret123")
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Logout(ctx, 1, "", time.Time{}, phone.RefreshToken))
+	_, err = svc.Refresh(ctx, phone.RefreshToken)
+	assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+
+	next, err := svc.Refresh(ctx, laptop.RefreshToken)
+	require.NoError(t, err, "інші сесії після виходу з одного пристрою працюють")
+
+	_, err = svc.Refresh(ctx, laptop.RefreshToken)
+	assert.ErrorIs(t, err, services.ErrInvalidRefreshToken)
+	_, err = svc.Refresh(ctx, next.RefreshToken)
+	assert.ErrorIs(t, err, services.ErrInvalidRefreshToken, "повтор заміненого токена відкликає всі сесії")
+}
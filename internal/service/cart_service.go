@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Помилки сервісу кошика
+
+var (
+	ErrInvalidQuantity   = apperr.Validation("invalid_quantity", "quantity must be > 0")         // кількість має бути більшою за 0
+	ErrInsufficientStock = apperr.Conflict("insufficient_stock", "not enough stock for product") // на складі недостатньо товару
+	ErrCartItemNotFound  = apperr.NotFound("cart_item_not_found", "product is not in the cart")  // продукту (або цього варіанту) немає в кошику
+)
+
+// CartService визначає бізнес-логіку кошика покупця.
+// variantID 0 — позиція продукту без варіанту; інакше — варіант цього продукту з власними ціною і залишком.
+
+type CartService interface {
+	GetCart(ctx context.Context, userID uint) (*models.Cart, error)                                        // повертає кошик з підрахованою сумою
+	AddItem(ctx context.Context, userID, productID, variantID uint, quantity int) (*models.Cart, error)    // додає кількість до наявної позиції
+	UpdateItem(ctx context.Context, userID, productID, variantID uint, quantity int) (*models.Cart, error) // встановлює кількість (0 — видаляє позицію)
+	RemoveItem(ctx context.Context, userID, productID, variantID uint) (*models.Cart, error)               // повертає ErrCartItemNotFound якщо позиції немає в кошику
+	Clear(ctx context.Context, userID uint) error                                                          // очищає кошик
+}
+
+// cartService реалізує CartService
+
+type cartService struct {
+	carts    repositories.CartRepository
+	products repositories.ProductRepository
+	variants repositories.VariantRepository
+}
+
+// NewCartService створює новий CartService
+
+func NewCartService(carts repositories.CartRepository, products repositories.ProductRepository, variants repositories.VariantRepository) CartService {
+	return &cartService{carts: carts, products: products, variants: variants}
+}
+
+// GetCart повертає кошик користувача і рахує суму з актуальних цін продуктів
+
+func (s *cartService) GetCart(ctx context.Context, userID uint) (*models.Cart, error) {
+	cart, err := s.carts.GetOrCreate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	calculateTotals(cart)
+	return cart, nil
+}
+
+// AddItem додає продукт у кошик; якщо він вже є — збільшує кількість.
+// Збільшення і перевірка залишку відбуваються в репозиторії однією транзакцією, тож два паралельні
+// додавання не перезаписують одне одного і не обходять перевірку залишку.
+
+func (s *cartService) AddItem(ctx context.Context, userID, productID, variantID uint, quantity int) (*models.Cart, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+	cart, err := s.carts.GetOrCreate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	available, err := s.available(ctx, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.carts.AddItem(ctx, cart.ID, productID, variantID, quantity, available)
+	if errors.Is(err, repositories.ErrOutOfStock) {
+		return nil, ErrInsufficientStock
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCart(ctx, userID)
+}
+
+// UpdateItem встановлює точну кількість продукту в кошику (0 — видаляє позицію)
+
+func (s *cartService) UpdateItem(ctx context.Context, userID, productID, variantID uint, quantity int) (*models.Cart, error) {
+	if quantity < 0 {
+		return nil, ErrInvalidQuantity
+	}
+	if quantity == 0 {
+		return s.RemoveItem(ctx, userID, productID, variantID)
+	}
+	cart, err := s.carts.GetOrCreate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if findCartItem(cart, productID, variantID) == nil {
+		return nil, ErrCartItemNotFound
+	}
+	return s.setItem(ctx, cart, productID, variantID, quantity)
+}
+
+// RemoveItem видаляє продукт з кошика
+
+func (s *cartService) RemoveItem(ctx context.Context, userID, productID, variantID uint) (*models.Cart, error) {
+	cart, err := s.carts.GetOrCreate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if findCartItem(cart, productID, variantID) == nil {
+		return nil, ErrCartItemNotFound
+	}
+	if err := s.carts.RemoveItem(ctx, cart.ID, productID, variantID); err != nil {
+		return nil, err
+	}
+	return s.GetCart(ctx, userID)
+}
+
+// Clear видаляє всі позиції з кошика користувача
+
+func (s *cartService) Clear(ctx context.Context, userID uint) error {
+	cart, err := s.carts.GetOrCreate(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.carts.Clear(ctx, cart.ID)
+}
+
+// available перевіряє, що продукт (і його варіант) існує, і повертає залишок варіанту
+// або Product.SellableStock (активні локації)
+
+func (s *cartService) available(ctx context.Context, productID, variantID uint) (int, error) {
+	p, err := s.products.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	if p == nil {
+		return 0, ErrNotFound
+	}
+	if variantID == 0 {
+		return p.SellableStock, nil
+	}
+	v, err := s.variants.GetByID(ctx, productID, variantID)
+	if err != nil {
+		return 0, translateVariantError(err, ErrVariantNotFound)
+	}
+	return v.Stock, nil
+}
+
+// setItem перевіряє, що кількість не перевищує доступний залишок, та зберігає позицію
+
+func (s *cartService) setItem(ctx context.Context, cart *models.Cart, productID, variantID uint, quantity int) (*models.Cart, error) {
+	available, err := s.available(ctx, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if quantity > available {
+		return nil, ErrInsufficientStock
+	}
+
+	if err := s.carts.SetItem(ctx, cart.ID, productID, variantID, quantity); err != nil {
+		return nil, err
+	}
+	return s.GetCart(ctx, cart.UserID)
+}
+
+// findCartItem шукає позицію кошика за ID продукту і варіанту (0 — без варіанту)
+
+func findCartItem(cart *models.Cart, productID, variantID uint) *models.CartItem {
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		if item.ProductID == productID && cartVariantID(item) == variantID {
+			return item
+		}
+	}
+	return nil
+}
+
+// cartVariantID повертає ID варіанту позиції (0 — без варіанту)
+
+func cartVariantID(item *models.CartItem) uint {
+	if item.VariantID == nil {
+		return 0
+	}
+	return *item.VariantID
+}
+
+// calculateTotals рахує суму кожної позиції та кошика на сервері (клієнт ціни не передає):
+// ціна варіанту, якщо він обраний, інакше ціна продукту.
+// Позиції з видаленими продуктами (soft delete) не показуються і не враховуються.
+
+func calculateTotals(cart *models.Cart) {
+	items := make([]models.CartItem, 0, len(cart.Items))
+	var total int64
+	for _, item := range cart.Items {
+		if item.Product.ID == 0 {
+			continue
+		}
+		price := item.Product.PriceCents
+		if item.Variant != nil {
+			price = item.Variant.PriceCents
+		}
+		item.SubtotalCents = price * int64(item.Quantity)
+		total += item.SubtotalCents
+		items = append(items, item)
+	}
+	cart.Items = items
+	cart.TotalCents = total
+}
@@ -0,0 +1,136 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// Простий in-memory repo реалізує repositories.CartRepository для тестів.
+// Продукти і варіанти для позицій беруться з memRepo і memVariantRepo (як Preload("Items.Product")).
+
+type memCartRepo struct {
+	products *memRepo
+	variants *memVariantRepo
+	carts    map[uint]*models.Cart        // ключ — UserID
+	items    map[uint]map[memCartLine]int // CartID -> позиція -> Quantity
+}
+
+// memCartLine — позиція кошика: продукт і варіант (0 — без варіанту)
+
+type memCartLine struct{ productID, variantID uint }
+
+func newMemCartRepo(products *memRepo) *memCartRepo {
+	return &memCartRepo{
+		products: products, variants: newMemVariantRepo(products),
+		carts: map[uint]*models.Cart{}, items: map[uint]map[memCartLine]int{},
+	}
+}
+
+func (m *memCartRepo) GetOrCreate(ctx context.Context, userID uint) (*models.Cart, error) {
+	cart, ok := m.carts[userID]
+	if !ok {
+		cart = &models.Cart{ID: uint(len(m.carts) + 1), UserID: userID}
+		m.carts[userID] = cart
+		m.items[cart.ID] = map[memCartLine]int{}
+	}
+	out := &models.Cart{ID: cart.ID, UserID: userID}
+	for line, qty := range m.items[cart.ID] {
+		item := models.CartItem{CartID: cart.ID, ProductID: line.productID, Quantity: qty}
+		if p, ok := m.products.data[line.productID]; ok {
+			item.Product = *p
+		}
+		if v, ok := m.variants.data[line.variantID]; ok {
+			variantID := line.variantID
+			item.VariantID, item.Variant = &variantID, v
+		}
+		out.Items = append(out.Items, item)
+	}
+	return out, nil
+}
+
+// AddItem збільшує кількість і відкочує зміну, якщо разом більше за available (як транзакція cartRepo)
+
+func (m *memCartRepo) AddItem(ctx context.Context, cartID, productID, variantID uint, quantity, available int) error {
+	line := memCartLine{productID, variantID}
+	if m.items[cartID][line]+quantity > available {
+		return repositories.ErrOutOfStock
+	}
+	m.items[cartID][line] += quantity
+	return nil
+}
+
+func (m *memCartRepo) SetItem(ctx context.Context, cartID, productID, variantID uint, quantity int) error {
+	m.items[cartID][memCartLine{productID, variantID}] = quantity
+	return nil
+}
+
+func (m *memCartRepo) RemoveItem(ctx context.Context, cartID, productID, variantID uint) error {
+	delete(m.items[cartID], memCartLine{productID, variantID})
+	return nil
+}
+
+func (m *memCartRepo) Clear(ctx context.Context, cartID uint) error {
+	m.items[cartID] = map[memCartLine]int{}
+	return nil
+}
+
+// newTestCartService створює CartService з двома продуктами: корм (100 грн, 5 шт.) і іграшка (25 грн, 1 шт.)
+
+func newTestCartService(t *testing.T) services.CartService {
+	products := newMemRepo()
+	require.NoError(t, products.Create(context.Background(), &models.Product{Name: "Корм", PriceCents: 10000, Stock: 5}))
+	require.NoError(t, products.Create(context.Background(), &models.Product{Name: "Іграшка", PriceCents: 2500, Stock: 1}))
+	carts := newMemCartRepo(products)
+	return services.NewCartService(carts, products, carts.variants)
+}
+
+// Тест: сума рахується на сервері з Product.PriceCents
+
+func TestCartTotals(t *testing.T) {
+	svc := newTestCartService(t)
+	ctx := context.Background()
+
+	_, err := svc.AddItem(ctx, 1, 1, 0, 2)
+	require.NoError(t, err)
+	cart, err := svc.AddItem(ctx, 1, 2, 0, 1)
+	require.NoError(t, err)
+
+	assert.Len(t, cart.Items, 2)
+	assert.Equal(t, int64(2*10000+2500), cart.TotalCents)
+
+	cart, err = svc.UpdateItem(ctx, 1, 1, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2500), cart.TotalCents)
+}
+
+// Тест: кількість у кошику не може перевищувати залишок на складі
+
+func TestCartInsufficientStock(t *testing.T) {
+	svc := newTestCartService(t)
+	ctx := context.Background()
+
+	_, err := svc.AddItem(ctx, 1, 1, 0, 4)
+	require.NoError(t, err)
+
+	// 4 + 2 > 5 — сумарна кількість перевіряється при повторному додаванні
+
+	_, err = svc.AddItem(ctx, 1, 1, 0, 2)
+	assert.ErrorIs(t, err, services.ErrInsufficientStock)
+	cart, err := svc.GetCart(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 1)
+	assert.Equal(t, 4, cart.Items[0].Quantity, "невдале додавання не змінює кількість")
+
+	_, err = svc.AddItem(ctx, 1, 99, 0, 1)
+	assert.ErrorIs(t, err, services.ErrNotFound)
+
+	_, err = svc.AddItem(ctx, 1, 2, 0, 0)
+	assert.ErrorIs(t, err, services.ErrInvalidQuantity)
+}
@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Помилки сервісу категорій
+
+var (
+	ErrCategoryNotFound = apperr.NotFound("category_not_found", "category not found")                               // категорію не знайдено
+	ErrInvalidCategory  = apperr.Validation("invalid_category", "invalid category")                                 // назва, slug або батьківська категорія некоректні
+	ErrDuplicateSlug    = apperr.Conflict("duplicate_slug", "category with this slug already exists")               // slug вже використовується іншою категорією
+	ErrCategoryCycle    = apperr.Conflict("category_cycle", "category cannot be moved under its own subtree")       // нова батьківська категорія — сама категорія або її нащадок
+	ErrCategoryNotEmpty = apperr.Conflict("category_not_empty", "category has subcategories and cannot be deleted") // спочатку потрібно видалити або перенести підкатегорії
+)
+
+// maxProductCategories — скільки категорій можна призначити одному продукту
+
+const maxProductCategories = 20
+
+// CategoryService визначає бізнес-логіку таксономії каталогу
+
+type CategoryService interface {
+	CreateCategory(ctx context.Context, c *models.Category) (*models.Category, error)   // slug генерується з назви, якщо не задано; ErrDuplicateSlug якщо зайнятий
+	GetCategory(ctx context.Context, slug string) (*models.Category, error)             // повертає ErrCategoryNotFound якщо не знайдено
+	ListCategories(ctx context.Context) ([]models.Category, error)                      // дерево: кореневі категорії з вкладеними Children
+	UpdateCategory(ctx context.Context, c *models.Category) (*models.Category, error)   // ErrCategoryCycle якщо категорію переносять у власне піддерево; ErrInvalidAttributes якщо продукти піддерева не відповідають новій схемі
+	DeleteCategory(ctx context.Context, id uint) error                                  // ErrCategoryNotEmpty якщо є підкатегорії; зв'язки з продуктами видаляються
+	SubtreeIDs(ctx context.Context, slug string) ([]uint, error)                        // ID категорії за slug разом з усіма нащадками
+	SetProductCategories(ctx context.Context, productID uint, categoryIDs []uint) error // замінює категорії продукту; ErrNotFound якщо продукту немає, ErrInvalidAttributes якщо атрибути не відповідають новим категоріям
+}
+
+// categoryService реалізує CategoryService
+
+type categoryService struct {
+	repo     repositories.CategoryRepository
+	products repositories.ProductRepository // атрибути продукту перевіряються за новими категоріями
+}
+
+// NewCategoryService створює новий CategoryService
+
+func NewCategoryService(r repositories.CategoryRepository, products repositories.ProductRepository) CategoryService {
+	return &categoryService{repo: r, products: products}
+}
+
+// CreateCategory перевіряє назву, slug і батьківську категорію та створює категорію
+
+func (s *categoryService) CreateCategory(ctx context.Context, c *models.Category) (*models.Category, error) {
+	if err := s.validate(ctx, c); err != nil {
+		return nil, err
+	}
+	c.Children = nil
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, translateCategoryError(err)
+	}
+	return c, nil
+}
+
+// GetCategory повертає категорію за slug
+
+func (s *categoryService) GetCategory(ctx context.Context, slug string) (*models.Category, error) {
+	c, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, translateCategoryError(err)
+	}
+	return c, nil
+}
+
+// ListCategories повертає всі категорії деревом (кореневі — на верхньому рівні, впорядковані за назвою)
+
+func (s *categoryService) ListCategories(ctx context.Context) ([]models.Category, error) {
+	items, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return buildCategoryTree(items), nil
+}
+
+// UpdateCategory змінює категорію. Перенесення під іншу батьківську категорію перевіряється на цикли:
+// нова батьківська категорія не може бути самою категорією або її нащадком.
+// Якщо змінюється схема атрибутів або батьківська категорія (а з нею успадкована схема), атрибути всіх
+// продуктів піддерева перевіряються за новими схемами в тій самій транзакції — як у SetProductCategories.
+
+func (s *categoryService) UpdateCategory(ctx context.Context, c *models.Category) (*models.Category, error) {
+	existing, err := s.repo.GetByID(ctx, c.ID)
+	if err != nil {
+		return nil, translateCategoryError(err)
+	}
+	if err := s.validate(ctx, c); err != nil {
+		return nil, err
+	}
+	if c.ParentID != nil {
+		subtree, err := s.repo.SubtreeIDs(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range subtree {
+			if id == *c.ParentID {
+				return nil, ErrCategoryCycle
+			}
+		}
+	}
+
+	c.CreatedAt = existing.CreatedAt
+	c.Children = nil
+	var check func([]repositories.CategoryProduct) error
+	if !reflect.DeepEqual(existing.Attributes, c.Attributes) || !sameParent(existing.ParentID, c.ParentID) {
+		check = validateCategoryProducts
+	}
+	if err := s.repo.Update(ctx, c, check); err != nil {
+		return nil, translateCategoryError(err)
+	}
+	return c, nil
+}
+
+// maxReportedProducts — скільки ID продуктів перелічується в помилці ErrInvalidAttributes
+
+const maxReportedProducts = 20
+
+// validateCategoryProducts перевіряє атрибути продуктів за їхніми схемами і повертає ErrInvalidAttributes
+// з ID продуктів, що перестали відповідати схемі
+
+func validateCategoryProducts(products []repositories.CategoryProduct) error {
+	var invalid []string
+	for _, p := range products {
+		if validateAttributes(mergeSchemas(p.Schemas), p.Attributes) == nil {
+			continue
+		}
+		if len(invalid) == maxReportedProducts {
+			invalid = append(invalid, "...")
+			break
+		}
+		invalid = append(invalid, strconv.FormatUint(uint64(p.ID), 10))
+	}
+	if len(invalid) > 0 {
+		return ErrInvalidAttributes.WithDetail("attributes of products " + strings.Join(invalid, ", ") + " do not match the new category schema")
+	}
+	return nil
+}
+
+// sameParent порівнює батьківські категорії (nil — коренева)
+
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// DeleteCategory видаляє категорію без підкатегорій (продукти лишаються, втрачаючи лише цю категорію)
+
+func (s *categoryService) DeleteCategory(ctx context.Context, id uint) error {
+	n, err := s.repo.CountChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrCategoryNotEmpty
+	}
+	return translateCategoryError(s.repo.Delete(ctx, id))
+}
+
+// SubtreeIDs повертає ID категорії та всіх її нащадків (для вибірки продуктів з підкатегорій)
+
+func (s *categoryService) SubtreeIDs(ctx context.Context, slug string) ([]uint, error) {
+	c, err := s.GetCategory(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.SubtreeIDs(ctx, c.ID)
+}
+
+// SetProductCategories замінює категорії продукту (порожній список — прибрати всі); повтори ігноруються.
+// Атрибути продукту перевіряються за схемами нових категорій, як при зміні продукту, — інакше продукт
+// опинився б у стані, в якому наступний PUT або PATCH відхиляється.
+
+func (s *categoryService) SetProductCategories(ctx context.Context, productID uint, categoryIDs []uint) error {
+	ids, err := normalizeCategoryIDs(categoryIDs)
+	if err != nil {
+		return err
+	}
+	p, err := s.products.GetByID(ctx, productID)
+	if err != nil {
+		return translateProductError(err)
+	}
+	if p == nil {
+		return ErrNotFound
+	}
+	if len(ids) > 0 || len(p.Attributes) > 0 {
+		schemas, err := s.repo.AttributeSchemas(ctx, ids)
+		if err != nil {
+			return err
+		}
+		if err := validateAttributes(mergeSchemas(schemas), p.Attributes); err != nil {
+			return err
+		}
+	}
+	err = s.repo.SetProductCategories(ctx, productID, ids)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return ErrCategoryNotFound
+	}
+	return err
+}
+
+// normalizeCategoryIDs відкидає повтори і перевіряє кількість категорій продукту
+
+func normalizeCategoryIDs(categoryIDs []uint) ([]uint, error) {
+	ids := make([]uint, 0, len(categoryIDs))
+	seen := make(map[uint]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		if id == 0 {
+			return nil, ErrInvalidCategory.WithDetail("category id must be > 0")
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > maxProductCategories {
+		return nil, ErrInvalidCategory.WithDetail("too many categories")
+	}
+	return ids, nil
+}
+
+// slugPattern — дозволений формат slug: малі латинські літери і цифри, розділені одинарними дефісами
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// validate перевіряє назву, схему атрибутів і батьківську категорію, нормалізує або генерує slug
+
+func (s *categoryService) validate(ctx context.Context, c *models.Category) error {
+	c.Name = strings.TrimSpace(c.Name)
+	if n := utf8.RuneCountInString(c.Name); n < 2 || n > 100 {
+		return ErrInvalidCategory.WithDetail("name must be 2-100 characters")
+	}
+
+	c.Slug = strings.ToLower(strings.TrimSpace(c.Slug))
+	if c.Slug == "" {
+		c.Slug = slugify(c.Name)
+	}
+	if c.Slug == "" {
+		return ErrInvalidCategory.WithDetail("slug cannot be derived from name, set it explicitly")
+	}
+	if len(c.Slug) > 100 || !slugPattern.MatchString(c.Slug) {
+		return ErrInvalidCategory.WithDetail("slug must contain only a-z, 0-9 and single hyphens")
+	}
+
+	if err := validateAttributeSchema(c.Attributes); err != nil {
+		return ErrInvalidCategory.WithDetail(err.Error())
+	}
+	if err := s.checkAttributeTypes(ctx, c); err != nil {
+		return err
+	}
+
+	if c.ParentID != nil {
+		if *c.ParentID == c.ID && c.ID != 0 {
+			return ErrCategoryCycle
+		}
+		if _, err := s.repo.GetByID(ctx, *c.ParentID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidCategory.WithDetail("parent category does not exist")
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// checkAttributeTypes перевіряє, що атрибут з тією ж назвою в інших категоріях має той самий тип —
+// інакше фільтр attr.<name> у каталозі був би неоднозначним
+
+func (s *categoryService) checkAttributeTypes(ctx context.Context, c *models.Category) error {
+	if len(c.Attributes) == 0 {
+		return nil
+	}
+	all, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range all {
+		if other.ID == c.ID {
+			continue
+		}
+		for _, od := range other.Attributes {
+			for _, def := range c.Attributes {
+				if def.Name == od.Name && def.Type != od.Type {
+					return ErrInvalidCategory.WithDetail(fmt.Sprintf("attribute %q is already defined as %s in category %s", def.Name, od.Type, other.Slug))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// buildCategoryTree збирає плоский список у дерево, зберігаючи порядок списку на кожному рівні
+
+func buildCategoryTree(items []models.Category) []models.Category {
+	children := make(map[uint][]models.Category)
+	var roots []models.Category
+	for _, c := range items {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+		} else {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+
+	var attach func(nodes []models.Category, depth int)
+	attach = func(nodes []models.Category, depth int) {
+		if depth > len(items) {
+			return // захист від циклу в даних
+		}
+		for i := range nodes {
+			nodes[i].Children = children[nodes[i].ID]
+			attach(nodes[i].Children, depth+1)
+		}
+	}
+	attach(roots, 0)
+	if roots == nil {
+		roots = []models.Category{}
+	}
+	return roots
+}
+
+// ukrainianTranslit — транслітерація кирилиці для slug (спрощена офіційна таблиця 2010 року)
+
+var ukrainianTranslit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "h", 'ґ': "g", 'д': "d", 'е': "e", 'є': "ie", 'ж': "zh",
+	'з': "z", 'и': "y", 'і': "i", 'ї': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n",
+	'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ь': "", 'ю': "iu", 'я': "ia", '\'': "", '’': "",
+	'ё': "e", 'ы': "y", 'э': "e", 'ъ': "",
+}
+
+// slugify будує slug з назви: кирилиця транслітерується, інші символи стають дефісами ("Корм для котів" → "korm-dlia-kotiv")
+
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		default:
+			if t, ok := ukrainianTranslit[r]; ok {
+				b.WriteString(t)
+				if t != "" {
+					dash = false
+				}
+				continue
+			}
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+	s := strings.TrimSuffix(b.String(), "-")
+	if len(s) > 100 {
+		s = strings.TrimRight(s[:100], "-")
+	}
+	return s
+}
+
+// translateCategoryError переводить помилки GORM у помилки домену категорій
+
+func translateCategoryError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrCategoryNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicateSlug
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return ErrInvalidCategory.WithDetail("parent category does not exist")
+	}
+	return err
+}
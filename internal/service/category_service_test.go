@@ -0,0 +1,447 @@
+package services_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// Простий in-memory repo реалізує repositories.CategoryRepository для тестів.
+
+type memCategoryRepo struct {
+	data     map[uint]*models.Category
+	products map[uint][]uint // продукт → категорії
+	catalog  *memRepo        // атрибути продуктів для check в Update (nil — продуктів немає)
+	next     uint
+}
+
+func newMemCategoryRepo() *memCategoryRepo {
+	return &memCategoryRepo{data: map[uint]*models.Category{}, products: map[uint][]uint{}, next: 1}
+}
+
+func (m *memCategoryRepo) Create(ctx context.Context, c *models.Category) error {
+	for _, other := range m.data {
+		if other.Slug == c.Slug {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	c.ID = m.next
+	m.next++
+	cp := *c
+	m.data[c.ID] = &cp
+	return nil
+}
+
+func (m *memCategoryRepo) GetByID(ctx context.Context, id uint) (*models.Category, error) {
+	c, ok := m.data[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (m *memCategoryRepo) GetBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	for _, c := range m.data {
+		if c.Slug == slug {
+			cp := *c
+			return &cp, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memCategoryRepo) List(ctx context.Context) ([]models.Category, error) {
+	items := make([]models.Category, 0, len(m.data))
+	for _, c := range m.data {
+		items = append(items, *c)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items, nil
+}
+
+func (m *memCategoryRepo) Update(ctx context.Context, c *models.Category, check func([]repositories.CategoryProduct) error) error {
+	for _, other := range m.data {
+		if other.Slug == c.Slug && other.ID != c.ID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	prev := m.data[c.ID]
+	cp := *c
+	m.data[c.ID] = &cp
+	if check == nil {
+		return nil
+	}
+	if err := check(m.subtreeProducts(c.ID)); err != nil {
+		m.data[c.ID] = prev // відкат, як у транзакції categoryRepo
+		return err
+	}
+	return nil
+}
+
+// subtreeProducts збирає продукти піддерева категорії зі схемами їхніх категорій (як subtreeProducts у categoryRepo)
+
+func (m *memCategoryRepo) subtreeProducts(id uint) []repositories.CategoryProduct {
+	subtree, _ := m.SubtreeIDs(context.Background(), id)
+	var out []repositories.CategoryProduct
+	for productID, categoryIDs := range m.products {
+		for _, cid := range categoryIDs {
+			if !containsUint(subtree, cid) {
+				continue
+			}
+			var attrs models.Attributes
+			if m.catalog != nil && m.catalog.data[productID] != nil {
+				attrs = m.catalog.data[productID].Attributes
+			}
+			schemas, _ := m.AttributeSchemas(context.Background(), categoryIDs)
+			out = append(out, repositories.CategoryProduct{ID: productID, Attributes: attrs, Schemas: schemas})
+			break
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func containsUint(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *memCategoryRepo) Delete(ctx context.Context, id uint) error {
+	if _, ok := m.data[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(m.data, id)
+	return nil
+}
+
+func (m *memCategoryRepo) CountChildren(ctx context.Context, id uint) (int64, error) {
+	var n int64
+	for _, c := range m.data {
+		if c.ParentID != nil && *c.ParentID == id {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// SubtreeIDs обходить дерево в ширину (як рекурсивний CTE у categoryRepo)
+
+func (m *memCategoryRepo) SubtreeIDs(ctx context.Context, id uint) ([]uint, error) {
+	ids := []uint{id}
+	for i := 0; i < len(ids); i++ {
+		for _, c := range m.data {
+			if c.ParentID != nil && *c.ParentID == ids[i] {
+				ids = append(ids, c.ID)
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (m *memCategoryRepo) SetProductCategories(ctx context.Context, productID uint, categoryIDs []uint) error {
+	for _, id := range categoryIDs {
+		if _, ok := m.data[id]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+	}
+	m.products[productID] = categoryIDs
+	return nil
+}
+
+// AttributeSchemas піднімається від кожної категорії до кореня; кожна категорія — один раз (як UNION у CTE)
+
+func (m *memCategoryRepo) AttributeSchemas(ctx context.Context, categoryIDs []uint) ([]models.AttributeSchema, error) {
+	var schemas []models.AttributeSchema
+	seen := map[uint]bool{}
+	for _, id := range categoryIDs {
+		for c, ok := m.data[id]; ok && !seen[c.ID]; {
+			seen[c.ID] = true
+			schemas = append(schemas, c.Attributes)
+			if c.ParentID == nil {
+				break
+			}
+			c, ok = m.data[*c.ParentID]
+		}
+	}
+	return schemas, nil
+}
+
+func uintPtr(v uint) *uint { return &v }
+
+// Тест створення: slug генерується з назви (з транслітерацією), дублікати відхиляються
+
+func TestCreateCategory(t *testing.T) {
+	svc := services.NewCategoryService(newMemCategoryRepo(), newMemRepo())
+	ctx := context.Background()
+
+	c, err := svc.CreateCategory(ctx, &models.Category{Name: "Корм для котів"})
+	require.NoError(t, err)
+	assert.Equal(t, "korm-dlia-kotiv", c.Slug)
+
+	c, err = svc.CreateCategory(ctx, &models.Category{Name: "Dog food!", Slug: " Dog-Food "})
+	require.NoError(t, err)
+	assert.Equal(t, "dog-food", c.Slug)
+
+	_, err = svc.CreateCategory(ctx, &models.Category{Name: "Dog food"})
+	assert.ErrorIs(t, err, services.ErrDuplicateSlug)
+	_, err = svc.CreateCategory(ctx, &models.Category{Name: "Bad", Slug: "dog food"})
+	assert.ErrorIs(t, err, services.ErrInvalidCategory)
+	_, err = svc.CreateCategory(ctx, &models.Category{Name: "Orphan", ParentID: uintPtr(42)})
+	assert.ErrorIs(t, err, services.ErrInvalidCategory)
+}
+
+// Тест дерева, вибірки нащадків і захисту від циклів
+
+func TestCategoryTree(t *testing.T) {
+	repo := newMemCategoryRepo()
+	svc := services.NewCategoryService(repo, newMemRepo())
+	ctx := context.Background()
+
+	dogs, err := svc.CreateCategory(ctx, &models.Category{Name: "Dogs"})
+	require.NoError(t, err)
+	food, err := svc.CreateCategory(ctx, &models.Category{Name: "Dog food", ParentID: &dogs.ID})
+	require.NoError(t, err)
+	dry, err := svc.CreateCategory(ctx, &models.Category{Name: "Dry food", ParentID: &food.ID})
+	require.NoError(t, err)
+	_, err = svc.CreateCategory(ctx, &models.Category{Name: "Cats"})
+	require.NoError(t, err)
+
+	tree, err := svc.ListCategories(ctx)
+	require.NoError(t, err)
+	require.Len(t, tree, 2)
+	assert.Equal(t, "cats", tree[0].Slug)
+	require.Len(t, tree[1].Children, 1)
+	require.Len(t, tree[1].Children[0].Children, 1)
+	assert.Equal(t, "dry-food", tree[1].Children[0].Children[0].Slug)
+
+	ids, err := svc.SubtreeIDs(ctx, "dogs")
+	require.NoError(t, err)
+	assert.Equal(t, []uint{dogs.ID, food.ID, dry.ID}, ids)
+	_, err = svc.SubtreeIDs(ctx, "birds")
+	assert.ErrorIs(t, err, services.ErrCategoryNotFound)
+
+	// Перенесення Dogs під власну підкатегорію утворило б цикл
+	_, err = svc.UpdateCategory(ctx, &models.Category{ID: dogs.ID, Name: "Dogs", ParentID: &dry.ID})
+	assert.ErrorIs(t, err, services.ErrCategoryCycle)
+	_, err = svc.UpdateCategory(ctx, &models.Category{ID: dogs.ID, Name: "Dogs", ParentID: &dogs.ID})
+	assert.ErrorIs(t, err, services.ErrCategoryCycle)
+
+	moved, err := svc.UpdateCategory(ctx, &models.Category{ID: dry.ID, Name: "Dry food", ParentID: &dogs.ID})
+	require.NoError(t, err)
+	assert.Equal(t, dogs.ID, *moved.ParentID)
+
+	assert.ErrorIs(t, svc.DeleteCategory(ctx, dogs.ID), services.ErrCategoryNotEmpty)
+	assert.NoError(t, svc.DeleteCategory(ctx, dry.ID))
+	assert.ErrorIs(t, svc.DeleteCategory(ctx, dry.ID), services.ErrCategoryNotFound)
+}
+
+// Тест призначення категорій продукту: повтори відкидаються, неіснуюча категорія — ErrCategoryNotFound,
+// атрибути продукту перевіряються за схемами нових категорій
+
+func TestSetProductCategories(t *testing.T) {
+	repo := newMemCategoryRepo()
+	products := newMemRepo()
+	svc := services.NewCategoryService(repo, products)
+	ctx := context.Background()
+	c, err := svc.CreateCategory(ctx, &models.Category{Name: "Toys"})
+	require.NoError(t, err)
+	pets, dryFood := petFoodSchemas(t, svc)
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Корм", PriceCents: 100, Attributes: models.Attributes{"species": "dog", "weight_kg": 2.0}}))
+
+	require.NoError(t, svc.SetProductCategories(ctx, 1, []uint{dryFood.ID, dryFood.ID}))
+	assert.Equal(t, []uint{dryFood.ID}, repo.products[1])
+
+	assert.ErrorIs(t, svc.SetProductCategories(ctx, 1, []uint{pets.ID}), services.ErrInvalidAttributes) // weight_kg лише в dry-food
+	assert.ErrorIs(t, svc.SetProductCategories(ctx, 1, []uint{c.ID}), services.ErrInvalidAttributes)
+	assert.ErrorIs(t, svc.SetProductCategories(ctx, 1, nil), services.ErrInvalidAttributes)
+	assert.Equal(t, []uint{dryFood.ID}, repo.products[1])
+
+	assert.ErrorIs(t, svc.SetProductCategories(ctx, 42, []uint{c.ID}), services.ErrNotFound)
+	assert.ErrorIs(t, svc.SetProductCategories(ctx, 1, []uint{dryFood.ID, 99}), services.ErrCategoryNotFound)
+	assert.ErrorIs(t, svc.SetProductCategories(ctx, 1, []uint{0}), services.ErrInvalidCategory)
+}
+
+// Тест зміни схеми категорії: новий обов'язковий атрибут, видалений атрибут, вилучене значення enum
+// і перенесення під іншу батьківську категорію перевіряються за продуктами піддерева
+
+func TestUpdateCategoryValidatesProducts(t *testing.T) {
+	repo := newMemCategoryRepo()
+	products := newMemRepo()
+	repo.catalog = products
+	svc := services.NewCategoryService(repo, products)
+	ctx := context.Background()
+	pets, dryFood := petFoodSchemas(t, svc)
+	toys, err := svc.CreateCategory(ctx, &models.Category{Name: "Toys"})
+	require.NoError(t, err)
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Корм", PriceCents: 100, Attributes: models.Attributes{"species": "dog", "weight_kg": 2.0}}))
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Лежак", PriceCents: 100, Attributes: models.Attributes{"species": "cat"}}))
+	require.NoError(t, svc.SetProductCategories(ctx, 1, []uint{dryFood.ID}))
+	require.NoError(t, svc.SetProductCategories(ctx, 2, []uint{pets.ID}))
+
+	withBrand := append(models.AttributeSchema{{Name: "brand", Type: models.AttributeString, Required: true}}, pets.Attributes...)
+	_, err = svc.UpdateCategory(ctx, &models.Category{ID: pets.ID, Name: "Pets", Attributes: withBrand})
+	assert.ErrorIs(t, err, services.ErrInvalidAttributes)
+	assert.ErrorContains(t, err, "products 1, 2")
+
+	dogsOnly := models.AttributeSchema{{Name: "species", Type: models.AttributeEnum, Required: true, Values: []string{"dog"}}, pets.Attributes[1]}
+	_, err = svc.UpdateCategory(ctx, &models.Category{ID: pets.ID, Name: "Pets", Attributes: dogsOnly})
+	assert.ErrorContains(t, err, "products 2 ")
+
+	_, err = svc.UpdateCategory(ctx, &models.Category{ID: dryFood.ID, Name: "Dry food", ParentID: &pets.ID, Attributes: dryFood.Attributes[1:]})
+	assert.ErrorContains(t, err, "products 1 ") // weight_kg видалено
+
+	_, err = svc.UpdateCategory(ctx, &models.Category{ID: dryFood.ID, Name: "Dry food", ParentID: &toys.ID, Attributes: dryFood.Attributes})
+	assert.ErrorContains(t, err, "products 1 ") // species більше не успадковується
+	assert.Equal(t, pets.ID, *repo.data[dryFood.ID].ParentID)
+	assert.Equal(t, pets.Attributes, repo.data[pets.ID].Attributes)
+
+	// Необов'язковий атрибут і зміна назви продуктам не заважають
+
+	withColour := append(models.AttributeSchema{{Name: "colour", Type: models.AttributeString}}, pets.Attributes...)
+	_, err = svc.UpdateCategory(ctx, &models.Category{ID: pets.ID, Name: "Pet supplies", Attributes: withColour})
+	assert.NoError(t, err)
+}
+
+// petFoodSchemas створює категорії pets (species, life_stage) → dry-food (weight_kg, grain_free)
+
+func petFoodSchemas(t *testing.T, svc services.CategoryService) (pets, dryFood *models.Category) {
+	ctx := context.Background()
+	pets, err := svc.CreateCategory(ctx, &models.Category{Name: "Pets", Attributes: models.AttributeSchema{
+		{Name: "species", Type: models.AttributeEnum, Required: true, Values: []string{"dog", "cat"}},
+		{Name: "life_stage", Type: models.AttributeEnum, Values: []string{"puppy", "adult", "senior"}},
+	}})
+	require.NoError(t, err)
+	dryFood, err = svc.CreateCategory(ctx, &models.Category{Name: "Dry food", ParentID: &pets.ID, Attributes: models.AttributeSchema{
+		{Name: "weight_kg", Type: models.AttributeNumber},
+		{Name: "grain_free", Type: models.AttributeBoolean},
+	}})
+	require.NoError(t, err)
+	return pets, dryFood
+}
+
+// Тест схеми атрибутів категорії: назви, типи, enum і однаковий тип атрибута в усіх категоріях
+
+func TestCategoryAttributeSchema(t *testing.T) {
+	svc := services.NewCategoryService(newMemCategoryRepo(), newMemRepo())
+	ctx := context.Background()
+	petFoodSchemas(t, svc)
+
+	invalid := []models.AttributeSchema{
+		{{Name: "Weight KG", Type: models.AttributeNumber}},
+		{{Name: "colour", Type: "color"}},
+		{{Name: "size", Type: models.AttributeEnum}},
+		{{Name: "size", Type: models.AttributeString, Values: []string{"S"}}},
+		{{Name: "size", Type: models.AttributeString}, {Name: "size", Type: models.AttributeString}},
+		{{Name: "weight_kg", Type: models.AttributeString}}, // у dry-food це number
+	}
+	for _, schema := range invalid {
+		_, err := svc.CreateCategory(ctx, &models.Category{Name: "Toys", Attributes: schema})
+		assert.ErrorIs(t, err, services.ErrInvalidCategory, schema[0].Name)
+	}
+
+	toys, err := svc.CreateCategory(ctx, &models.Category{Name: "Toys", Attributes: models.AttributeSchema{
+		{Name: "weight_kg", Type: models.AttributeNumber},
+	}})
+	require.NoError(t, err)
+	assert.Len(t, toys.Attributes, 1)
+}
+
+// Тест атрибутів продукту: перевірка за схемою категорії та її предків, PATCH змінює окремі атрибути
+
+func TestProductAttributes(t *testing.T) {
+	categories := newMemCategoryRepo()
+	_, dryFood := petFoodSchemas(t, services.NewCategoryService(categories, newMemRepo()))
+	svc := services.NewProductService(newMemRepo(), categories)
+	ctx := context.Background()
+
+	newProduct := func(attrs models.Attributes) (*models.Product, error) {
+		return svc.CreateProduct(ctx, &models.Product{
+			Name: "Сухий корм", PriceCents: 50000, Categories: []models.Category{{ID: dryFood.ID}}, Attributes: attrs,
+		})
+	}
+	invalid := []models.Attributes{
+		{"weight_kg": 2.0},                                 // species обов'язковий (успадковано від pets)
+		{"species": "parrot"},                              // не зі списку enum
+		{"species": "dog", "weight_kg": "2"},               // рядок замість числа
+		{"species": "dog", "grain_free": "yes"},            // рядок замість bool
+		{"species": "dog", "colour": "red"},                // атрибута немає в схемі
+		{"species": "dog", "life_stage": map[string]any{}}, // об'єкт замість рядка
+	}
+	for _, attrs := range invalid {
+		_, err := newProduct(attrs)
+		assert.ErrorIs(t, err, services.ErrInvalidAttributes, attrs)
+	}
+
+	p, err := newProduct(models.Attributes{"species": " dog ", "weight_kg": 2.5, "grain_free": true})
+	require.NoError(t, err)
+	assert.Equal(t, "dog", p.Attributes["species"])
+
+	_, err = svc.CreateProduct(ctx, &models.Product{Name: "Іграшка", PriceCents: 100, Attributes: models.Attributes{"species": "dog"}})
+	assert.ErrorIs(t, err, services.ErrInvalidAttributes) // без категорій атрибутів немає
+
+	patched, err := svc.PatchProduct(ctx, p.ID, []byte(`{"attributes": {"grain_free": null, "life_stage": "adult"}}`), 0)
+	require.NoError(t, err)
+	assert.Equal(t, models.Attributes{"species": "dog", "weight_kg": 2.5, "life_stage": "adult"}, patched.Attributes)
+	_, err = svc.PatchProduct(ctx, p.ID, []byte(`{"attributes": {"species": null}}`), 0)
+	assert.ErrorIs(t, err, services.ErrInvalidAttributes)
+}
+
+// filterRepo запам'ятовує фільтр останнього List
+
+type filterRepo struct {
+	*memRepo
+	filter repositories.ProductFilter
+}
+
+func (r *filterRepo) List(ctx context.Context, f repositories.ProductFilter) ([]models.Product, int64, error) {
+	r.filter = f
+	return r.memRepo.List(ctx, f)
+}
+
+// Тест фільтрів за атрибутами: значення з query приводяться до типу зі схеми, невідомі атрибути відхиляються
+
+func TestAttributeFilters(t *testing.T) {
+	categories := newMemCategoryRepo()
+	petFoodSchemas(t, services.NewCategoryService(categories, newMemRepo()))
+	repo := &filterRepo{memRepo: newMemRepo()}
+	svc := services.NewProductService(repo, categories)
+	ctx := context.Background()
+
+	list := func(filters ...repositories.AttributeFilter) error {
+		_, _, err := svc.ListProducts(ctx, repositories.ProductFilter{Limit: 20, Attributes: filters})
+		return err
+	}
+	require.NoError(t, list(
+		repositories.AttributeFilter{Name: "species", Op: repositories.AttrEq, Value: "dog"},
+		repositories.AttributeFilter{Name: "weight_kg", Op: repositories.AttrMin, Value: "2"},
+		repositories.AttributeFilter{Name: "grain_free", Op: repositories.AttrEq, Value: "true"},
+	))
+	assert.Equal(t, []interface{}{"dog", 2.0, true}, []interface{}{
+		repo.filter.Attributes[0].Value, repo.filter.Attributes[1].Value, repo.filter.Attributes[2].Value,
+	})
+
+	invalid := []repositories.AttributeFilter{
+		{Name: "colour", Op: repositories.AttrEq, Value: "red"},
+		{Name: "species", Op: repositories.AttrEq, Value: "parrot"},
+		{Name: "species", Op: repositories.AttrMin, Value: "1"},
+		{Name: "weight_kg", Op: repositories.AttrMax, Value: "heavy"},
+		{Name: "grain_free", Op: repositories.AttrEq, Value: "maybe"},
+	}
+	for _, f := range invalid {
+		assert.ErrorIs(t, list(f), services.ErrInvalidFilter, f.Name)
+	}
+}
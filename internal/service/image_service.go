@@ -0,0 +1,243 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/imaging"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/storage"
+	"gorm.io/gorm"
+)
+
+// Помилки сервісу зображень продуктів
+
+var (
+	ErrImageNotFound     = apperr.NotFound("image_not_found", "product image not found")                                           // зображення немає в цього продукту
+	ErrImageTooLarge     = apperr.PayloadTooLarge("image_too_large", "image file is too large")                                    // файл більший за STORAGE_MAX_UPLOAD_SIZE
+	ErrUnsupportedImage  = apperr.New(apperr.KindUnsupportedMediaType, "unsupported_image_type", "image must be jpeg, png or gif") // тип за вмістом файлу (не за заголовком клієнта)
+	ErrInvalidImage      = apperr.Validation("invalid_image", "image could not be decoded")                                        // пошкоджений файл або завеликі розміри в пікселях
+	ErrTooManyImages     = apperr.Conflict("too_many_images", "product already has the maximum number of images")                  // більше maxProductImages
+	ErrInvalidImageOrder = apperr.Validation("invalid_image_order", "image order must list every product image exactly once")      // ids не збігаються з зображеннями продукту
+)
+
+// Обмеження зображень продукту
+
+const (
+	maxProductImages = 20         // зображень на продукт
+	maxImagePixels   = 40_000_000 // ширина × висота оригіналу (40 Мп)
+	thumbnailQuality = 85         // якість JPEG мініатюр
+)
+
+// thumbnailSizes — фіксовані розміри мініатюр: назва → найбільша сторона в пікселях
+
+var thumbnailSizes = []struct {
+	Name string
+	Size int
+}{
+	{"small", 150},
+	{"medium", 400},
+	{"large", 800},
+}
+
+// imageTypes — дозволені типи (визначені http.DetectContentType) і розширення файлу оригіналу
+
+var imageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// ImageService визначає бізнес-логіку зображень продукту: завантаження з мініатюрами, порядок і видалення
+
+type ImageService interface {
+	ListImages(ctx context.Context, productID uint) ([]models.ProductImage, error)                // ErrNotFound якщо продукту немає
+	UploadImage(ctx context.Context, productID uint, r io.Reader) (*models.ProductImage, error)   // ErrImageTooLarge, ErrUnsupportedImage, ErrInvalidImage, ErrTooManyImages
+	ReorderImages(ctx context.Context, productID uint, ids []uint) ([]models.ProductImage, error) // ErrInvalidImageOrder якщо ids — не всі зображення продукту
+	DeleteImage(ctx context.Context, productID, id uint) error                                    // ErrImageNotFound якщо зображення немає
+}
+
+// imageService реалізує ImageService
+
+type imageService struct {
+	repo     repositories.ImageRepository
+	products repositories.ProductRepository
+	store    storage.Storage
+	maxSize  int64 // максимальний розмір файлу в байтах
+}
+
+// NewImageService створює новий ImageService; maxSize — ліміт розміру одного файлу (STORAGE_MAX_UPLOAD_SIZE)
+
+func NewImageService(r repositories.ImageRepository, products repositories.ProductRepository, store storage.Storage, maxSize int64) ImageService {
+	return &imageService{repo: r, products: products, store: store, maxSize: maxSize}
+}
+
+// ListImages повертає зображення продукту в порядку показу
+
+func (s *imageService) ListImages(ctx context.Context, productID uint) ([]models.ProductImage, error) {
+	p, err := s.products.GetByID(ctx, productID)
+	if err != nil {
+		return nil, translateProductError(err)
+	}
+	if p == nil {
+		return nil, ErrNotFound
+	}
+	return s.repo.ListByProduct(ctx, productID)
+}
+
+// UploadImage перевіряє файл (розмір, тип за вмістом, чи декодується), зберігає оригінал і мініатюри
+// у сховище і додає зображення в кінець списку продукту. Якщо запис у БД не вдався — файли видаляються.
+
+func (s *imageService) UploadImage(ctx context.Context, productID uint, r io.Reader) (*models.ProductImage, error) {
+	existing, err := s.ListImages(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxProductImages {
+		return nil, ErrTooManyImages.WithDetail(fmt.Sprintf("at most %d images", maxProductImages))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrImageTooLarge.WithDetail(fmt.Sprintf("max %d bytes", s.maxSize))
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := imageTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedImage.WithDetail(fmt.Sprintf("detected %s", contentType))
+	}
+	src, _, err := imaging.Decode(bytes.NewReader(data), maxImagePixels)
+	if err != nil {
+		return nil, ErrInvalidImage.WithDetail(err.Error())
+	}
+
+	dir, err := imageDir(productID)
+	if err != nil {
+		return nil, err
+	}
+	img := &models.ProductImage{
+		ProductID:   productID,
+		Key:         path.Join(dir, "original"+ext),
+		ContentType: contentType,
+		SizeBytes:   int64(len(data)),
+		Width:       src.Bounds().Dx(),
+		Height:      src.Bounds().Dy(),
+		Thumbnails:  models.ImageThumbnails{},
+	}
+	img.URL = s.store.URL(img.Key)
+
+	if err := s.store.Put(ctx, img.Key, bytes.NewReader(data), contentType); err != nil {
+		return nil, err
+	}
+	stored := []string{img.Key} // ключі вже збережених файлів — для прибирання при помилці
+	for _, ts := range thumbnailSizes {
+		var buf bytes.Buffer
+		if err := imaging.EncodeJPEG(&buf, imaging.Thumbnail(src, ts.Size), thumbnailQuality); err != nil {
+			s.removeFiles(ctx, stored)
+			return nil, err
+		}
+		key := path.Join(dir, ts.Name+".jpg")
+		if err := s.store.Put(ctx, key, &buf, "image/jpeg"); err != nil {
+			s.removeFiles(ctx, stored)
+			return nil, err
+		}
+		stored = append(stored, key)
+		img.Thumbnails[ts.Name] = s.store.URL(key)
+	}
+
+	if err := s.repo.Create(ctx, img); err != nil {
+		s.removeFiles(ctx, stored)
+		return nil, translateImageError(err, ErrNotFound)
+	}
+	return img, nil
+}
+
+// ReorderImages задає новий порядок зображень: ids — усі зображення продукту, перше стає головним
+
+func (s *imageService) ReorderImages(ctx context.Context, productID uint, ids []uint) ([]models.ProductImage, error) {
+	existing, err := s.ListImages(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) != len(existing) {
+		return nil, ErrInvalidImageOrder.WithDetail(fmt.Sprintf("product has %d images", len(existing)))
+	}
+	known := make(map[uint]bool, len(existing))
+	for _, img := range existing {
+		known[img.ID] = true
+	}
+	for _, id := range ids {
+		if !known[id] {
+			return nil, ErrInvalidImageOrder.WithDetail(fmt.Sprintf("image %d is unknown or listed twice", id))
+		}
+		delete(known, id)
+	}
+
+	if err := s.repo.Reorder(ctx, productID, ids); err != nil {
+		return nil, translateImageError(err, ErrInvalidImageOrder)
+	}
+	return s.repo.ListByProduct(ctx, productID)
+}
+
+// DeleteImage видаляє запис зображення, а потім його файли. Помилки видалення файлів лише логуються:
+// запис уже видалено, а файл без запису не показується клієнтам.
+
+func (s *imageService) DeleteImage(ctx context.Context, productID, id uint) error {
+	img, err := s.repo.GetByID(ctx, productID, id)
+	if err != nil {
+		return translateImageError(err, ErrImageNotFound)
+	}
+	if err := s.repo.Delete(ctx, productID, id); err != nil {
+		return translateImageError(err, ErrImageNotFound)
+	}
+	keys := []string{img.Key}
+	for name := range img.Thumbnails {
+		keys = append(keys, path.Join(path.Dir(img.Key), name+".jpg"))
+	}
+	s.removeFiles(ctx, keys)
+	return nil
+}
+
+// removeFiles видаляє файли зі сховища (прибирання після невдалої операції)
+
+func (s *imageService) removeFiles(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("Не вдалося видалити файл %s зі сховища: %v", key, err)
+		}
+	}
+}
+
+// imageDir генерує унікальний каталог для файлів нового зображення (products/<id>/<32 hex>):
+// URL не повторюються, тож кеш CDN або браузера не покаже старий файл
+
+func imageDir(productID uint) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("products/%d/%s", productID, hex.EncodeToString(b)), nil
+}
+
+// translateImageError переводить помилки GORM у помилки домену зображень;
+// notFound — що означає відсутній запис (продукт при створенні, зображення при видаленні)
+
+func translateImageError(err error, notFound error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound
+	}
+	return err
+}
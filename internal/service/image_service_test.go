@@ -0,0 +1,178 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// Простий in-memory repo реалізує repositories.ImageRepository для тестів.
+
+type memImageRepo struct {
+	data      map[uint]*models.ProductImage
+	next      uint
+	failWrite bool // Create повертає помилку (імітація збою БД після запису файлів)
+}
+
+func newMemImageRepo() *memImageRepo {
+	return &memImageRepo{data: map[uint]*models.ProductImage{}, next: 1}
+}
+
+func (m *memImageRepo) ListByProduct(ctx context.Context, productID uint) ([]models.ProductImage, error) {
+	var items []models.ProductImage
+	for _, img := range m.data {
+		if img.ProductID == productID {
+			items = append(items, *img)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Position < items[j].Position })
+	return items, nil
+}
+
+func (m *memImageRepo) GetByID(ctx context.Context, productID, id uint) (*models.ProductImage, error) {
+	img, ok := m.data[id]
+	if !ok || img.ProductID != productID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *img
+	return &cp, nil
+}
+
+func (m *memImageRepo) Create(ctx context.Context, img *models.ProductImage) error {
+	if m.failWrite {
+		return errors.New("db is down")
+	}
+	existing, _ := m.ListByProduct(ctx, img.ProductID)
+	img.ID, img.Position = m.next, len(existing)
+	m.next++
+	cp := *img
+	m.data[img.ID] = &cp
+	return nil
+}
+
+func (m *memImageRepo) Reorder(ctx context.Context, productID uint, ids []uint) error {
+	for i, id := range ids {
+		m.data[id].Position = i
+	}
+	return nil
+}
+
+func (m *memImageRepo) Delete(ctx context.Context, productID, id uint) error {
+	if img, ok := m.data[id]; !ok || img.ProductID != productID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(m.data, id)
+	return nil
+}
+
+// memStorage реалізує storage.Storage у пам'яті
+
+type memStorage map[string][]byte
+
+func (s memStorage) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	data, err := io.ReadAll(r)
+	s[key] = data
+	return err
+}
+
+func (s memStorage) Delete(ctx context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+func (s memStorage) URL(key string) string { return "/media/" + key }
+
+// pngImage кодує однотонне PNG зображення w×h
+
+func pngImage(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 200
+	}
+	img.Set(0, 0, color.Black)
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// Тест завантаження: тип визначається за вмістом, мініатюри зберігаються поруч з оригіналом, ліміти перевіряються
+
+func TestUploadImage(t *testing.T) {
+	products := newMemRepo()
+	require.NoError(t, products.Create(context.Background(), &models.Product{Name: "Лежанка", PriceCents: 90000}))
+	repo, store := newMemImageRepo(), memStorage{}
+	svc := services.NewImageService(repo, products, store, 64<<10)
+	ctx := context.Background()
+
+	img, err := svc.UploadImage(ctx, 1, bytes.NewReader(pngImage(t, 1000, 500)))
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", img.ContentType)
+	assert.Equal(t, 1000, img.Width)
+	assert.Equal(t, 500, img.Height)
+	assert.True(t, strings.HasPrefix(img.Key, "products/1/") && strings.HasSuffix(img.Key, "/original.png"), img.Key)
+	assert.Equal(t, "/media/"+img.Key, img.URL)
+	assert.Len(t, img.Thumbnails, 3)
+	assert.Len(t, store, 4) // оригінал + small, medium, large
+	assert.Equal(t, "/media/"+strings.TrimSuffix(img.Key, "original.png")+"small.jpg", img.Thumbnails["small"])
+
+	_, err = svc.UploadImage(ctx, 1, strings.NewReader("<html><body>not an image</body></html>"))
+	assert.ErrorIs(t, err, services.ErrUnsupportedImage)
+	_, err = svc.UploadImage(ctx, 1, bytes.NewReader(pngImage(t, 10, 10)[:60]))
+	assert.ErrorIs(t, err, services.ErrInvalidImage)
+	_, err = svc.UploadImage(ctx, 1, bytes.NewReader(make([]byte, 64<<10+1)))
+	assert.ErrorIs(t, err, services.ErrImageTooLarge)
+	_, err = svc.UploadImage(ctx, 42, bytes.NewReader(pngImage(t, 10, 10)))
+	assert.ErrorIs(t, err, services.ErrNotFound)
+
+	// Збій БД після запису файлів — файли прибираються
+	repo.failWrite = true
+	_, err = svc.UploadImage(ctx, 1, bytes.NewReader(pngImage(t, 10, 10)))
+	assert.Error(t, err)
+	assert.Len(t, store, 4)
+}
+
+// Тест порядку і видалення: новий порядок має містити всі зображення рівно один раз, видалення прибирає файли
+
+func TestReorderAndDeleteImages(t *testing.T) {
+	products := newMemRepo()
+	require.NoError(t, products.Create(context.Background(), &models.Product{Name: "Лежанка", PriceCents: 90000}))
+	store := memStorage{}
+	svc := services.NewImageService(newMemImageRepo(), products, store, 64<<10)
+	ctx := context.Background()
+
+	var ids []uint
+	for i := 0; i < 3; i++ {
+		img, err := svc.UploadImage(ctx, 1, bytes.NewReader(pngImage(t, 20, 20)))
+		require.NoError(t, err)
+		assert.Equal(t, i, img.Position)
+		ids = append(ids, img.ID)
+	}
+
+	for _, order := range [][]uint{{ids[0], ids[1]}, {ids[0], ids[1], ids[1]}, {ids[0], ids[1], 99}} {
+		_, err := svc.ReorderImages(ctx, 1, order)
+		assert.ErrorIs(t, err, services.ErrInvalidImageOrder, order)
+	}
+	items, err := svc.ReorderImages(ctx, 1, []uint{ids[2], ids[0], ids[1]})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{ids[2], ids[0], ids[1]}, []uint{items[0].ID, items[1].ID, items[2].ID})
+
+	require.Len(t, store, 12)
+	require.NoError(t, svc.DeleteImage(ctx, 1, ids[2]))
+	assert.Len(t, store, 8)
+	assert.ErrorIs(t, svc.DeleteImage(ctx, 1, ids[2]), services.ErrImageNotFound)
+	assert.ErrorIs(t, svc.DeleteImage(ctx, 2, ids[0]), services.ErrImageNotFound)
+}
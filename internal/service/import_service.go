@@ -0,0 +1,483 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+)
+
+// Масовий імпорт і експорт каталогу у CSV або NDJSON (JSON Lines, один продукт на рядок).
+// Імпорт оновлює продукти за артикулом (sku) або створює нові; кожен рядок перевіряється окремо,
+// некоректні рядки пропускаються і повертаються в ImportResult.Errors, решта записується пачками.
+
+// Формати імпорту та експорту
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// ErrInvalidImport — файл імпорту не можна прочитати (формат, заголовок CSV, пошкоджений рядок)
+
+var ErrInvalidImport = apperr.Validation("invalid_import", "invalid import file")
+
+// Обмеження імпорту
+
+const (
+	importBatchSize = 500   // рядків в одному INSERT ... ON CONFLICT
+	maxImportRows   = 50000 // рядків у файлі; решта файлу ігнорується з помилкою
+	maxImportErrors = 1000  // помилок рядків у відповіді (Failed рахує всі)
+	maxNDJSONLine   = 1 << 20
+)
+
+// importColumns — колонки CSV у порядку експорту; sku обов'язкова, решта — необов'язкові
+
+var importColumns = []string{"sku", "name", "description", "price_cents", "stock", "category", "attributes"}
+
+// ImportRowError — помилка одного рядка файлу
+
+type ImportRowError struct {
+	Row    int    `json:"row"`              // номер рядка у файлі (з 1; у CSV рядок 1 — заголовок)
+	SKU    string `json:"sku,omitempty"`    // артикул рядка, якщо його вдалося прочитати
+	Code   string `json:"code"`             // код помилки, як у problem+json (invalid_price, invalid_attributes …)
+	Detail string `json:"detail,omitempty"` // подробиці
+}
+
+// ImportResult — підсумок імпорту
+
+type ImportResult struct {
+	Created int              `json:"created"` // нових продуктів
+	Updated int              `json:"updated"` // оновлених (і відновлених після видалення) продуктів
+	Failed  int              `json:"failed"`  // пропущених рядків
+	Errors  []ImportRowError `json:"errors"`  // перші maxImportErrors помилок
+}
+
+// ImportService визначає масовий імпорт і потоковий експорт продуктів
+
+type ImportService interface {
+	ImportProducts(ctx context.Context, format string, r io.Reader) (*ImportResult, error) // ErrInvalidImport, якщо файл не читається; помилки рядків — у результаті
+	ExportProducts(ctx context.Context, format string, w io.Writer) error                  // пише всі продукти пачками, у форматі, придатному для імпорту
+}
+
+// importService реалізує ImportService
+
+type importService struct {
+	products   repositories.ProductRepository
+	categories repositories.CategoryRepository // схеми атрибутів категорій
+}
+
+// NewImportService створює новий ImportService
+
+func NewImportService(products repositories.ProductRepository, categories repositories.CategoryRepository) ImportService {
+	return &importService{products: products, categories: categories}
+}
+
+// productFields — поля продукту в рядку імпорту та експорту; nil — поля немає в рядку (значення продукту не змінюється)
+
+type productFields struct {
+	SKU         *string            `json:"sku"`
+	Name        *string            `json:"name,omitempty"`
+	Description *string            `json:"description,omitempty"`
+	PriceCents  *int64             `json:"price_cents,omitempty"`
+	Stock       *int               `json:"stock,omitempty"`
+	Category    *string            `json:"category,omitempty"`
+	Attributes  *models.Attributes `json:"attributes,omitempty"`
+}
+
+// importRow — прочитаний рядок файлу; err — рядок не вдалося розібрати
+
+type importRow struct {
+	line   int
+	fields productFields
+	err    error
+}
+
+// sku повертає артикул рядка без пробілів на краях
+
+func (r importRow) sku() string {
+	if r.fields.SKU == nil {
+		return ""
+	}
+	return strings.TrimSpace(*r.fields.SKU)
+}
+
+// ImportProducts читає рядки файлу і записує їх пачками по importBatchSize.
+// Пачки записуються незалежно: якщо запис пачки в БД не вдався, попередні вже збережено.
+
+func (s *importService) ImportProducts(ctx context.Context, format string, r io.Reader) (*ImportResult, error) {
+	next, err := newImportReader(format, r)
+	if err != nil {
+		return nil, err
+	}
+	res := &ImportResult{Errors: []ImportRowError{}}
+	seen := make(map[string]int) // артикул → рядок, де він уже був
+	schemas := make(map[string]map[string]models.AttributeDef)
+	var batch []importRow
+
+	for rows := 0; ; rows++ {
+		row, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if rows == maxImportRows {
+			res.fail(row, ErrInvalidImport.WithDetail(fmt.Sprintf("at most %d rows; the rest of the file is ignored", maxImportRows)))
+			break
+		}
+		if row.err != nil {
+			res.fail(row, row.err)
+			continue
+		}
+		sku := row.sku()
+		if sku == "" {
+			res.fail(row, ErrInvalidProduct.WithDetail("sku is required"))
+			continue
+		}
+		if line, ok := seen[sku]; ok {
+			res.fail(row, ErrDuplicateSKU.WithDetail(fmt.Sprintf("already in row %d", line)))
+			continue
+		}
+		seen[sku] = row.line
+
+		batch = append(batch, row)
+		if len(batch) == importBatchSize {
+			s.importBatch(ctx, batch, schemas, res)
+			batch = batch[:0]
+		}
+	}
+	s.importBatch(ctx, batch, schemas, res)
+	return res, nil
+}
+
+// importBatch застосовує рядки до наявних продуктів (або нових), перевіряє кожен і записує коректні одним запитом.
+// schemas — кеш схем атрибутів за набором категорій продукту.
+// Попередні пачки вже записані, тому жодна помилка (і помилка БД теж) не перериває імпорт:
+// рядки, які не вдалося записати, потрапляють у помилки результату, і клієнт бачить, що саме не імпортовано.
+
+func (s *importService) importBatch(ctx context.Context, batch []importRow, schemas map[string]map[string]models.AttributeDef, res *ImportResult) {
+	if len(batch) == 0 {
+		return
+	}
+	skus := make([]string, len(batch))
+	for i, row := range batch {
+		skus[i] = row.sku()
+	}
+	found, err := s.products.FindBySKUs(ctx, skus)
+	if err != nil {
+		log.Printf("Імпорт: не вдалося прочитати пачку з %d продуктів: %v", len(batch), err)
+		for _, row := range batch {
+			res.fail(row, err)
+		}
+		return
+	}
+	existing := make(map[string]models.Product, len(found))
+	for _, p := range found {
+		existing[p.SKU] = p
+	}
+
+	valid := make([]models.Product, 0, len(batch))
+	rows := make(map[string]importRow, len(batch)) // артикул → рядок коректного продукту
+	for _, row := range batch {
+		p, found := existing[row.sku()]
+		if !found {
+			p = models.Product{SKU: row.sku(), Version: 1}
+		}
+		row.fields.apply(&p)
+		err := validateProduct(&p)
+		if err == nil {
+			err = s.checkAttributes(ctx, p.Categories, p.Attributes, schemas)
+		}
+		if err != nil {
+			var ae *apperr.Error
+			if !errors.As(err, &ae) {
+				log.Printf("Імпорт: рядок %d: %v", row.line, err) // помилка БД, а не рядка
+			}
+			res.fail(row, err)
+			continue
+		}
+		p.Categories = nil // асоціації імпорт не змінює
+		valid = append(valid, p)
+		rows[p.SKU] = row
+	}
+
+	rejected, err := s.products.UpsertBySKU(ctx, valid)
+	if err != nil {
+		log.Printf("Імпорт: не вдалося записати пачку з %d продуктів: %v", len(valid), err)
+		for _, p := range valid {
+			res.fail(rows[p.SKU], err)
+		}
+		return
+	}
+	for _, p := range valid {
+		switch err := rejected[p.SKU]; {
+		case errors.Is(err, repositories.ErrOutOfStock):
+			res.fail(rows[p.SKU], ErrInsufficientStock.WithDetail(fmt.Sprintf("main warehouse holds less than the stock decrease to %d", p.Stock)))
+		case errors.Is(err, repositories.ErrNoWarehouse):
+			res.fail(rows[p.SKU], ErrNoActiveWarehouse)
+		case err != nil:
+			res.fail(rows[p.SKU], err)
+		case existing[p.SKU].ID == 0:
+			res.Created++
+		default:
+			res.Updated++
+		}
+	}
+}
+
+// checkAttributes перевіряє атрибути за схемами категорій продукту; схеми кешуються за набором категорій
+
+func (s *importService) checkAttributes(ctx context.Context, categories []models.Category, attrs models.Attributes, cache map[string]map[string]models.AttributeDef) error {
+	if len(categories) == 0 && len(attrs) == 0 {
+		return nil
+	}
+	ids := categoryIDs(categories)
+	key := fmt.Sprint(ids)
+	defs, ok := cache[key]
+	if !ok {
+		schemas, err := s.categories.AttributeSchemas(ctx, ids)
+		if err != nil {
+			return err
+		}
+		defs = mergeSchemas(schemas)
+		cache[key] = defs
+	}
+	return validateAttributes(defs, attrs)
+}
+
+// apply переносить передані поля рядка в продукт (рядки обрізаються від пробілів)
+
+func (f productFields) apply(p *models.Product) {
+	if f.Name != nil {
+		p.Name = strings.TrimSpace(*f.Name)
+	}
+	if f.Description != nil {
+		p.Description = strings.TrimSpace(*f.Description)
+	}
+	if f.PriceCents != nil {
+		p.PriceCents = *f.PriceCents
+	}
+	if f.Stock != nil {
+		p.Stock = *f.Stock
+	}
+	if f.Category != nil {
+		p.Category = strings.TrimSpace(*f.Category)
+	}
+	if f.Attributes != nil {
+		p.Attributes = *f.Attributes
+	}
+}
+
+// fail додає помилку рядка до результату
+
+func (res *ImportResult) fail(row importRow, err error) {
+	res.Failed++
+	if len(res.Errors) >= maxImportErrors {
+		return
+	}
+	e := ImportRowError{Row: row.line, SKU: row.sku(), Code: "internal_error"}
+	var ae *apperr.Error
+	if errors.As(err, &ae) {
+		e.Code, e.Detail = ae.Code, ae.Detail
+	}
+	res.Errors = append(res.Errors, e)
+}
+
+// newImportReader повертає функцію, що читає наступний рядок файлу (io.EOF — кінець файлу)
+
+func newImportReader(format string, r io.Reader) (func() (importRow, error), error) {
+	switch format {
+	case FormatCSV:
+		return csvRows(r)
+	case FormatNDJSON:
+		return ndjsonRows(r), nil
+	}
+	return nil, ErrInvalidImport.WithDetail(fmt.Sprintf("unsupported format %q (csv, ndjson)", format))
+}
+
+// csvRows читає CSV з заголовком: назви колонок з importColumns у будь-якому порядку, sku обов'язкова.
+// Колонка, якої немає у файлі, не змінює поле продукту; порожня клітинка очищає текстове поле.
+
+func csvRows(r io.Reader) (func() (importRow, error), error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrInvalidImport.WithDetail("empty file")
+	}
+	if err != nil {
+		return nil, ErrInvalidImport.WithDetail(err.Error())
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) // BOM з Excel
+		if !containsString(importColumns, name) {
+			return nil, ErrInvalidImport.WithDetail(fmt.Sprintf("unknown column %q", name))
+		}
+		if _, dup := columns[name]; dup {
+			return nil, ErrInvalidImport.WithDetail(fmt.Sprintf("column %q appears twice", name))
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["sku"]; !ok {
+		return nil, ErrInvalidImport.WithDetail("column sku is required")
+	}
+
+	return func() (importRow, error) {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return importRow{}, io.EOF
+		}
+		line, _ := cr.FieldPos(0)
+		row := importRow{line: line}
+		if errors.Is(err, csv.ErrFieldCount) {
+			row.err = ErrInvalidImport.WithDetail(fmt.Sprintf("expected %d columns, got %d", len(header), len(record)))
+			return row, nil
+		}
+		if err != nil {
+			return row, ErrInvalidImport.WithDetail(err.Error()) // пошкоджені лапки — далі файл не читається надійно
+		}
+		row.fields, row.err = parseCSVRecord(record, columns)
+		return row, nil
+	}, nil
+}
+
+// parseCSVRecord перетворює клітинки рядка CSV у поля продукту
+
+func parseCSVRecord(record []string, columns map[string]int) (productFields, error) {
+	var f productFields
+	cell := func(name string) *string {
+		i, ok := columns[name]
+		if !ok {
+			return nil
+		}
+		return &record[i]
+	}
+	f.SKU, f.Name, f.Description, f.Category = cell("sku"), cell("name"), cell("description"), cell("category")
+
+	if v := cell("price_cents"); v != nil {
+		n, err := strconv.ParseInt(strings.TrimSpace(*v), 10, 64)
+		if err != nil {
+			return f, ErrInvalidPrice.WithDetail("price_cents must be an integer number of cents")
+		}
+		f.PriceCents = &n
+	}
+	if v := cell("stock"); v != nil {
+		n, err := strconv.Atoi(strings.TrimSpace(*v))
+		if err != nil {
+			return f, ErrInvalidProduct.WithDetail("stock must be an integer")
+		}
+		f.Stock = &n
+	}
+	if v := cell("attributes"); v != nil {
+		attrs := models.Attributes{}
+		if s := strings.TrimSpace(*v); s != "" {
+			if err := json.Unmarshal([]byte(s), &attrs); err != nil {
+				return f, ErrInvalidAttributes.WithDetail("attributes must be a JSON object")
+			}
+		}
+		f.Attributes = &attrs
+	}
+	return f, nil
+}
+
+// ndjsonRows читає JSON Lines: кожен непорожній рядок — об'єкт з полями productFields (невідомі поля — помилка рядка)
+
+func ndjsonRows(r io.Reader) func() (importRow, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), maxNDJSONLine)
+	line := 0
+	return func() (importRow, error) {
+		for sc.Scan() {
+			line++
+			data := bytes.TrimSpace(sc.Bytes())
+			if len(data) == 0 {
+				continue
+			}
+			row := importRow{line: line}
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&row.fields); err != nil {
+				row.err = ErrInvalidImport.WithDetail(err.Error())
+			}
+			return row, nil
+		}
+		if err := sc.Err(); err != nil {
+			return importRow{}, ErrInvalidImport.WithDetail(fmt.Sprintf("line %d: %v", line+1, err))
+		}
+		return importRow{}, io.EOF
+	}
+}
+
+// ExportProducts пише всі продукти у форматі імпорту, скидаючи буфер після кожної пачки
+
+func (s *importService) ExportProducts(ctx context.Context, format string, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	var write func(p *models.Product) error
+	flush := bw.Flush
+
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(bw)
+		if err := cw.Write(importColumns); err != nil {
+			return err
+		}
+		write = func(p *models.Product) error {
+			attrs := ""
+			if len(p.Attributes) > 0 {
+				data, err := json.Marshal(p.Attributes)
+				if err != nil {
+					return err
+				}
+				attrs = string(data)
+			}
+			return cw.Write([]string{
+				p.SKU, p.Name, p.Description, strconv.FormatInt(p.PriceCents, 10), strconv.Itoa(p.Stock), p.Category, attrs,
+			})
+		}
+		flush = func() error {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+			return bw.Flush()
+		}
+	case FormatNDJSON:
+		enc := json.NewEncoder(bw)
+		write = func(p *models.Product) error {
+			return enc.Encode(productFields{
+				SKU: &p.SKU, Name: &p.Name, Description: &p.Description, PriceCents: &p.PriceCents,
+				Stock: &p.Stock, Category: &p.Category, Attributes: &p.Attributes,
+			})
+		}
+	default:
+		return ErrInvalidImport.WithDetail(fmt.Sprintf("unsupported format %q (csv, ndjson)", format))
+	}
+
+	err := s.products.Each(ctx, importBatchSize, func(batch []models.Product) error {
+		for i := range batch {
+			if err := write(&batch[i]); err != nil {
+				return err
+			}
+		}
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
@@ -0,0 +1,129 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// Тест імпорту CSV: оновлення за артикулом, нові продукти, помилки окремих рядків не зупиняють імпорт
+
+func TestImportProductsCSV(t *testing.T) {
+	repo := newMemRepo()
+	categories := newMemCategoryRepo()
+	_, dryFood := petFoodSchemas(t, services.NewCategoryService(categories, newMemRepo()))
+	require.NoError(t, repo.Create(context.Background(), &models.Product{
+		Name: "Сухий корм", Description: "Для дорослих собак", PriceCents: 50000, SKU: "DOG-1",
+		Categories: []models.Category{{ID: dryFood.ID}},
+	}))
+	svc := services.NewImportService(repo, categories)
+
+	file := "\ufeffSKU,name,price_cents,stock,attributes\n" +
+		`DOG-1,Сухий корм 2 кг,52000,7,"{""species"": ""dog"", ""weight_kg"": 2}"` + "\n" +
+		"CAT-1,Корм для котів,30000,3,\n" +
+		"CAT-2,Корм,0,1,\n" +
+		"CAT-1,Дублікат,100,1,\n" +
+		"CAT-3,,100,1,\n" +
+		`CAT-4,Іграшка,100,1,"{""species"": ""cat""}"` + "\n" +
+		"CAT-5,Миска,abc,1,\n" +
+		"CAT-6,Миска\n"
+	res, err := svc.ImportProducts(context.Background(), services.FormatCSV, strings.NewReader(file))
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Created)
+	assert.Equal(t, 1, res.Updated)
+	assert.Equal(t, 6, res.Failed)
+
+	codes := map[int]string{}
+	for _, e := range res.Errors {
+		codes[e.Row] = e.Code
+	}
+	assert.Equal(t, map[int]string{
+		4: "invalid_price",
+		5: "duplicate_sku",
+		6: "invalid_product",
+		7: "invalid_attributes", // без категорій атрибутів немає
+		8: "invalid_price",
+		9: "invalid_import",
+	}, codes)
+
+	updated := repo.data[1]
+	assert.Equal(t, "Сухий корм 2 кг", updated.Name)
+	assert.Equal(t, "Для дорослих собак", updated.Description) // колонки description у файлі немає
+	assert.Equal(t, int64(52000), updated.PriceCents)
+	assert.Equal(t, uint(2), updated.Version)
+	assert.Equal(t, models.Attributes{"species": "dog", "weight_kg": 2.0}, updated.Attributes)
+
+	for _, header := range []string{"", "sku,colour\n", "name,price_cents\n", "sku,sku\n"} {
+		_, err := svc.ImportProducts(context.Background(), services.FormatCSV, strings.NewReader(header))
+		assert.ErrorIs(t, err, services.ErrInvalidImport, header)
+	}
+}
+
+// Тест NDJSON: порожні рядки пропускаються, невідомі поля — помилка рядка; експорт імпортується назад без змін
+
+func TestImportExportNDJSON(t *testing.T) {
+	repo := newMemRepo()
+	svc := services.NewImportService(repo, newMemCategoryRepo())
+	ctx := context.Background()
+
+	file := `{"sku": "BIRD-1", "name": "Клітка", "price_cents": 120000, "stock": 2, "category": "birds"}` + "\n\n" +
+		`{"sku": "BIRD-2", "name": "Жердинка", "price_cents": 5000, "colour": "red"}` + "\n" +
+		`{"sku": "BIRD-3", "name": "Гойдалка", "price_cents": 3000}` + "\n"
+	res, err := svc.ImportProducts(ctx, services.FormatNDJSON, strings.NewReader(file))
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Created)
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, services.ImportRowError{Row: 3, SKU: "BIRD-2", Code: "invalid_import", Detail: `json: unknown field "colour"`}, res.Errors[0])
+
+	for _, format := range []string{services.FormatCSV, services.FormatNDJSON} {
+		var out bytes.Buffer
+		require.NoError(t, svc.ExportProducts(ctx, format, &out))
+		assert.Contains(t, out.String(), "BIRD-3")
+
+		res, err := svc.ImportProducts(ctx, format, &out)
+		require.NoError(t, err, format)
+		assert.Equal(t, services.ImportResult{Updated: 2, Errors: []services.ImportRowError{}}, *res, format)
+	}
+	assert.Equal(t, "birds", repo.data[1].Category)
+
+	assert.ErrorIs(t, svc.ExportProducts(ctx, "xlsx", &bytes.Buffer{}), services.ErrInvalidImport)
+}
+
+// Тест: рядок, залишок якого не можна зменшити, і пачка, яку не вдалося записати, — помилки рядків,
+// а не всього імпорту (попередні пачки вже записані)
+
+func TestImportStockAndWriteErrors(t *testing.T) {
+	repo := newMemRepo()
+	ctx := context.Background()
+	require.NoError(t, repo.Create(ctx, &models.Product{Name: "Нашийник", PriceCents: 20000, SKU: "DOG-1", Stock: 5}))
+	svc := services.NewImportService(repo, newMemCategoryRepo())
+
+	repo.rejectSKU = map[string]error{"DOG-1": repositories.ErrOutOfStock}
+	res, err := svc.ImportProducts(ctx, services.FormatCSV, strings.NewReader("sku,name,price_cents,stock\nDOG-1,Нашийник,20000,0\nDOG-2,Повідець,15000,4\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Created)
+	assert.Equal(t, 0, res.Updated)
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, 2, res.Errors[0].Row)
+	assert.Equal(t, "insufficient_stock", res.Errors[0].Code)
+	assert.Equal(t, uint(1), repo.data[1].Version)
+
+	repo.rejectSKU, repo.upsertErr = nil, errors.New("connection reset")
+	res, err = svc.ImportProducts(ctx, services.FormatCSV, strings.NewReader("sku,name,price_cents\nDOG-3,Шлейка,18000\nDOG-4,,1\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Failed)
+	codes := map[int]string{}
+	for _, e := range res.Errors {
+		codes[e.Row] = e.Code
+	}
+	assert.Equal(t, map[int]string{2: "internal_error", 3: "invalid_product"}, codes)
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+)
+
+// Помилки сервісу складу
+
+var (
+	ErrInvalidMovement = apperr.Validation("invalid_stock_movement", "invalid stock movement") // невідомий тип, кількість або відсутня причина руху
+)
+
+// Обмеження журналу складу
+
+const (
+	maxMovementReason = 500 // символів у причині руху
+	maxMovementPage   = 100 // записів історії на сторінку
+)
+
+// movementSigns — знак зміни залишку для кожного типу руху (0 — коригування, знак задає клієнт)
+
+var movementSigns = map[string]int{
+	models.StockReceipt:    1,
+	models.StockSale:       -1,
+	models.StockReturn:     1,
+	models.StockAdjustment: 0,
+	models.StockDamage:     -1,
+}
+
+// MovementInput — рух товару, який вносить адміністратор.
+// Quantity — кількість одиниць (> 0); для adjustment — зміна залишку зі знаком (≠ 0).
+
+type MovementInput struct {
+	Type        string `json:"type" binding:"required"`
+	Quantity    int    `json:"quantity" binding:"required"`
+	Reason      string `json:"reason"`
+	WarehouseID uint   `json:"warehouse_id"` // локація руху; 0 — основна (активна з найменшим priority)
+}
+
+// TransferInput — переміщення товару між локаціями (Quantity > 0)
+
+type TransferInput struct {
+	FromWarehouseID uint   `json:"from_warehouse_id" binding:"required"`
+	ToWarehouseID   uint   `json:"to_warehouse_id" binding:"required"`
+	Quantity        int    `json:"quantity" binding:"required"`
+	Reason          string `json:"reason"`
+}
+
+// StockLevel — залишок продукту на момент At, відновлений за журналом складу
+
+type StockLevel struct {
+	ProductID    uint                  `json:"product_id"`
+	At           time.Time             `json:"at"`
+	Stock        int                   `json:"stock"`
+	LastMovement *models.StockMovement `json:"last_movement,omitempty"` // останній рух до At (nil — рухів ще не було)
+}
+
+// InventoryService — єдиний спосіб змінити залишок продукту вручну; кожна зміна записується в журнал складу.
+// Оформлення і скасування замовлень, створення та імпорт продуктів пишуть у той самий журнал на рівні репозиторіїв.
+
+type InventoryService interface {
+	RecordMovement(ctx context.Context, productID uint, in MovementInput, actorID uint) (*models.StockMovement, error) // ErrNotFound, ErrInvalidMovement, ErrInsufficientStock
+	Transfer(ctx context.Context, productID uint, in TransferInput, actorID uint) ([]models.StockMovement, error)      // пара рухів transfer: списання з джерела і надходження в призначення
+	History(ctx context.Context, productID uint, f repositories.MovementFilter) ([]models.StockMovement, int64, error) // returns items, totalCount; нові першими
+	StockAt(ctx context.Context, productID uint, at time.Time) (*StockLevel, error)                                    // залишок на момент at (нульовий — зараз)
+	ListReorder(ctx context.Context, limit, offset int) ([]models.Product, int64, error)                               // продукти із залишком на рівні порогу дозамовлення або нижче
+}
+
+// inventoryService реалізує InventoryService
+
+type inventoryService struct {
+	repo       repositories.InventoryRepository
+	products   repositories.ProductRepository
+	warehouses repositories.WarehouseRepository
+}
+
+// NewInventoryService створює новий InventoryService
+
+func NewInventoryService(r repositories.InventoryRepository, products repositories.ProductRepository, warehouses repositories.WarehouseRepository) InventoryService {
+	return &inventoryService{repo: r, products: products, warehouses: warehouses}
+}
+
+// RecordMovement перевіряє рух (тип, кількість, причина для коригування і списання) і застосовує його:
+// залишок змінюється і рух записується в одній транзакції, від'ємний залишок — ErrInsufficientStock
+
+func (s *inventoryService) RecordMovement(ctx context.Context, productID uint, in MovementInput, actorID uint) (*models.StockMovement, error) {
+	m, err := newMovement(productID, in, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	if in.WarehouseID != 0 {
+		if _, err := activeWarehouse(ctx, s.warehouses, in.WarehouseID); err != nil {
+			return nil, err
+		}
+		m.WarehouseID = &in.WarehouseID
+	}
+	if err := s.repo.Apply(ctx, m); err != nil {
+		return nil, translateMovementError(err, m.Quantity)
+	}
+	return m, nil
+}
+
+// Transfer переміщує товар між локаціями: загальний залишок не змінюється, обидва рухи пишуться в одній
+// транзакції. Призначення має бути активним; з неактивної локації товар забирати можна
+
+func (s *inventoryService) Transfer(ctx context.Context, productID uint, in TransferInput, actorID uint) ([]models.StockMovement, error) {
+	reason := strings.TrimSpace(in.Reason)
+	switch {
+	case in.FromWarehouseID == 0 || in.ToWarehouseID == 0:
+		return nil, ErrInvalidMovement.WithDetail("from_warehouse_id and to_warehouse_id are required")
+	case in.FromWarehouseID == in.ToWarehouseID:
+		return nil, ErrInvalidMovement.WithDetail("source and destination warehouses must differ")
+	case in.Quantity <= 0:
+		return nil, ErrInvalidMovement.WithDetail("transfer quantity must be > 0")
+	case utf8.RuneCountInString(reason) > maxMovementReason:
+		return nil, ErrInvalidMovement.WithDetail(fmt.Sprintf("reason must be at most %d characters", maxMovementReason))
+	}
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	if _, err := s.warehouses.GetByID(ctx, in.FromWarehouseID); err != nil {
+		return nil, translateWarehouseError(err)
+	}
+	if _, err := activeWarehouse(ctx, s.warehouses, in.ToWarehouseID); err != nil {
+		return nil, err
+	}
+
+	out := &models.StockMovement{
+		ProductID: productID, Type: models.StockTransfer, Quantity: -in.Quantity, Reason: reason,
+		ActorID: actorID, WarehouseID: &in.FromWarehouseID,
+	}
+	incoming := &models.StockMovement{
+		ProductID: productID, Type: models.StockTransfer, Quantity: in.Quantity, Reason: reason,
+		ActorID: actorID, WarehouseID: &in.ToWarehouseID,
+	}
+	if err := s.repo.Transfer(ctx, out, incoming); err != nil {
+		return nil, translateMovementError(err, out.Quantity)
+	}
+	return []models.StockMovement{*out, *incoming}, nil
+}
+
+// History повертає рухи продукту за період з пагінацією
+
+func (s *inventoryService) History(ctx context.Context, productID uint, f repositories.MovementFilter) ([]models.StockMovement, int64, error) {
+	if f.From != nil && f.To != nil && f.From.After(*f.To) {
+		return nil, 0, ErrInvalidFilter.WithDetail("from is after to")
+	}
+	if f.Limit <= 0 || f.Limit > maxMovementPage {
+		f.Limit = maxMovementPage
+	}
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListByProduct(ctx, productID, f)
+}
+
+// StockAt відновлює залишок продукту на момент at: StockAfter останнього руху до at
+// (до першого руху залишок дорівнює 0)
+
+func (s *inventoryService) StockAt(ctx context.Context, productID uint, at time.Time) (*StockLevel, error) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	last, err := s.repo.LastBefore(ctx, productID, at)
+	if err != nil {
+		return nil, err
+	}
+	level := &StockLevel{ProductID: productID, At: at.UTC(), LastMovement: last}
+	if last != nil {
+		level.Stock = last.StockAfter
+	}
+	return level, nil
+}
+
+// ListReorder повертає продукти, які потребують дозамовлення (найбільша нестача першою)
+
+func (s *inventoryService) ListReorder(ctx context.Context, limit, offset int) ([]models.Product, int64, error) {
+	if limit <= 0 || limit > maxMovementPage {
+		limit = maxMovementPage
+	}
+	return s.repo.ListBelowReorder(ctx, limit, offset)
+}
+
+// checkProduct повертає ErrNotFound, якщо продукту немає
+
+func (s *inventoryService) checkProduct(ctx context.Context, productID uint) error {
+	p, err := s.products.GetByID(ctx, productID)
+	if err != nil {
+		return translateProductError(err)
+	}
+	if p == nil {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// translateMovementError перетворює помилки застосування руху на помилки сервісу
+
+func translateMovementError(err error, quantity int) error {
+	switch {
+	case errors.Is(err, repositories.ErrOutOfStock):
+		return ErrInsufficientStock.WithDetail(fmt.Sprintf("stock would become negative (change %d)", quantity))
+	case errors.Is(err, repositories.ErrNoWarehouse):
+		return ErrNoActiveWarehouse
+	}
+	return translateProductError(err)
+}
+
+// newMovement перевіряє введений рух і будує запис журналу зі зміною залишку зі знаком
+
+func newMovement(productID uint, in MovementInput, actorID uint) (*models.StockMovement, error) {
+	sign, ok := movementSigns[in.Type]
+	if in.Type == models.StockTransfer {
+		return nil, ErrInvalidMovement.WithDetail("use the transfers endpoint to move stock between warehouses")
+	}
+	if !ok {
+		return nil, ErrInvalidMovement.WithDetail(fmt.Sprintf("unknown type %q", in.Type))
+	}
+	quantity := in.Quantity
+	switch {
+	case sign == 0 && quantity == 0:
+		return nil, ErrInvalidMovement.WithDetail("adjustment quantity must not be 0")
+	case sign != 0 && quantity <= 0:
+		return nil, ErrInvalidMovement.WithDetail(fmt.Sprintf("%s quantity must be > 0", in.Type))
+	case sign != 0:
+		quantity *= sign
+	}
+
+	reason := strings.TrimSpace(in.Reason)
+	if reason == "" && (in.Type == models.StockAdjustment || in.Type == models.StockDamage) {
+		return nil, ErrInvalidMovement.WithDetail(fmt.Sprintf("%s requires a reason", in.Type))
+	}
+	if utf8.RuneCountInString(reason) > maxMovementReason {
+		return nil, ErrInvalidMovement.WithDetail(fmt.Sprintf("reason must be at most %d characters", maxMovementReason))
+	}
+	return &models.StockMovement{
+		ProductID: productID, Type: in.Type, Quantity: quantity, Reason: reason, ActorID: actorID,
+	}, nil
+}
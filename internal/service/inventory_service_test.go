@@ -0,0 +1,259 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// memInventoryRepo — in-memory журнал складу поверх memRepo; now задає час наступного руху.
+// stock — залишки за [локація, продукт]; рух без локації йде в основну (ID 1)
+
+type memInventoryRepo struct {
+	products  *memRepo
+	movements []models.StockMovement
+	stock     map[[2]uint]int
+	now       time.Time
+}
+
+func (m *memInventoryRepo) Apply(ctx context.Context, mv *models.StockMovement) error {
+	p, ok := m.products.data[mv.ProductID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if mv.WarehouseID == nil {
+		main := uint(1)
+		mv.WarehouseID = &main
+	}
+	if m.stock == nil {
+		m.stock = map[[2]uint]int{}
+	}
+	key := [2]uint{*mv.WarehouseID, mv.ProductID}
+	if p.Stock+mv.Quantity < 0 || (mv.Quantity < 0 && m.stock[key]+mv.Quantity < 0) {
+		return repositories.ErrOutOfStock
+	}
+	m.stock[key] += mv.Quantity
+	p.Stock += mv.Quantity
+	p.Version++
+	mv.ID = uint(len(m.movements) + 1)
+	mv.CreatedAt = m.now
+	mv.StockAfter = p.Stock
+	m.movements = append(m.movements, *mv)
+	return nil
+}
+
+func (m *memInventoryRepo) Transfer(ctx context.Context, out, in *models.StockMovement) error {
+	if err := m.Apply(ctx, out); err != nil {
+		return err
+	}
+	return m.Apply(ctx, in)
+}
+
+func (m *memInventoryRepo) ListByProduct(ctx context.Context, productID uint, f repositories.MovementFilter) ([]models.StockMovement, int64, error) {
+	var out []models.StockMovement
+	for i := len(m.movements) - 1; i >= 0; i-- {
+		mv := m.movements[i]
+		if mv.ProductID == productID && (f.From == nil || !mv.CreatedAt.Before(*f.From)) && (f.To == nil || !mv.CreatedAt.After(*f.To)) {
+			out = append(out, mv)
+		}
+	}
+	total := int64(len(out))
+	if f.Offset >= len(out) {
+		return nil, total, nil
+	}
+	out = out[f.Offset:]
+	if len(out) > f.Limit {
+		out = out[:f.Limit]
+	}
+	return out, total, nil
+}
+
+func (m *memInventoryRepo) LastBefore(ctx context.Context, productID uint, at time.Time) (*models.StockMovement, error) {
+	for i := len(m.movements) - 1; i >= 0; i-- {
+		if mv := m.movements[i]; mv.ProductID == productID && !mv.CreatedAt.After(at) {
+			return &mv, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memInventoryRepo) ListBelowReorder(ctx context.Context, limit, offset int) ([]models.Product, int64, error) {
+	var out []models.Product
+	for id := uint(1); id < m.products.next; id++ {
+		if p, ok := m.products.data[id]; ok && p.ReorderPoint > 0 && p.Stock <= p.ReorderPoint {
+			out = append(out, *p)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+// Тест журналу складу: знак кількості за типом, залишок після руху і автор
+
+func TestRecordMovement(t *testing.T) {
+	ctx := context.Background()
+	products := newMemRepo()
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Корм", PriceCents: 10000}))
+	inv := &memInventoryRepo{products: products, now: time.Now()}
+	svc := services.NewInventoryService(inv, products, newMemWarehouseRepo())
+
+	m, err := svc.RecordMovement(ctx, 1, services.MovementInput{Type: models.StockReceipt, Quantity: 10, Reason: " накладна 17 "}, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 10, m.Quantity)
+	assert.Equal(t, 10, m.StockAfter)
+	assert.Equal(t, "накладна 17", m.Reason)
+	assert.Equal(t, uint(7), m.ActorID)
+
+	m, err = svc.RecordMovement(ctx, 1, services.MovementInput{Type: models.StockDamage, Quantity: 3, Reason: "пошкоджена упаковка"}, 7)
+	require.NoError(t, err)
+	assert.Equal(t, -3, m.Quantity)
+	m, err = svc.RecordMovement(ctx, 1, services.MovementInput{Type: models.StockAdjustment, Quantity: -2, Reason: "інвентаризація"}, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 5, m.StockAfter)
+	assert.Equal(t, 5, products.data[1].Stock)
+
+	_, err = svc.RecordMovement(ctx, 1, services.MovementInput{Type: models.StockSale, Quantity: 6}, 7)
+	assert.ErrorIs(t, err, services.ErrInsufficientStock)
+	assert.Equal(t, 5, products.data[1].Stock)
+	assert.Len(t, inv.movements, 3)
+
+	_, err = svc.RecordMovement(ctx, 42, services.MovementInput{Type: models.StockReceipt, Quantity: 1}, 7)
+	assert.ErrorIs(t, err, services.ErrNotFound)
+}
+
+// Тест валідації руху: тип, кількість і обов'язкова причина
+
+func TestRecordMovementInvalid(t *testing.T) {
+	ctx := context.Background()
+	products := newMemRepo()
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Корм", PriceCents: 10000, Stock: 5}))
+	svc := services.NewInventoryService(&memInventoryRepo{products: products}, products, newMemWarehouseRepo())
+
+	cases := []services.MovementInput{
+		{Type: "theft", Quantity: 1},
+		{Type: models.StockReceipt, Quantity: -1},
+		{Type: models.StockSale, Quantity: 0},
+		{Type: models.StockAdjustment, Quantity: 0, Reason: "інвентаризація"},
+		{Type: models.StockAdjustment, Quantity: 2},
+		{Type: models.StockDamage, Quantity: 1, Reason: "   "},
+		{Type: models.StockTransfer, Quantity: 1},
+	}
+	for _, in := range cases {
+		_, err := svc.RecordMovement(ctx, 1, in, 1)
+		assert.ErrorIs(t, err, services.ErrInvalidMovement, in)
+	}
+	assert.Equal(t, 5, products.data[1].Stock)
+}
+
+// Тест відновлення залишку на момент часу та історії за період
+
+func TestStockAtAndHistory(t *testing.T) {
+	ctx := context.Background()
+	products := newMemRepo()
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Корм", PriceCents: 10000}))
+	start := time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC)
+	inv := &memInventoryRepo{products: products}
+	svc := services.NewInventoryService(inv, products, newMemWarehouseRepo())
+
+	for i, in := range []services.MovementInput{
+		{Type: models.StockReceipt, Quantity: 10},
+		{Type: models.StockSale, Quantity: 4},
+		{Type: models.StockReturn, Quantity: 1},
+	} {
+		inv.now = start.Add(time.Duration(i) * 24 * time.Hour)
+		_, err := svc.RecordMovement(ctx, 1, in, 1)
+		require.NoError(t, err)
+	}
+
+	level, err := svc.StockAt(ctx, 1, start.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, level.Stock)
+	assert.Nil(t, level.LastMovement)
+
+	level, err = svc.StockAt(ctx, 1, start.Add(36*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 6, level.Stock)
+	require.NotNil(t, level.LastMovement)
+	assert.Equal(t, models.StockSale, level.LastMovement.Type)
+
+	level, err = svc.StockAt(ctx, 1, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, 7, level.Stock)
+
+	from := start.Add(time.Hour)
+	items, total, err := svc.History(ctx, 1, repositories.MovementFilter{From: &from, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, items, 1)
+	assert.Equal(t, models.StockReturn, items[0].Type)
+
+	to := start
+	_, _, err = svc.History(ctx, 1, repositories.MovementFilter{From: &from, To: &to})
+	assert.ErrorIs(t, err, services.ErrInvalidFilter)
+}
+
+// Тест переміщення між локаціями: загальний залишок не змінюється, залишки локацій — змінюються
+
+func TestTransfer(t *testing.T) {
+	ctx := context.Background()
+	products := newMemRepo()
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Корм", PriceCents: 10000}))
+	warehouses := newMemWarehouseRepo()
+	require.NoError(t, warehouses.Create(ctx, &models.Warehouse{Code: "shop-podil", Name: "Магазин на Подолі", Kind: models.WarehouseKindStore, Priority: 10, Active: true}))
+	require.NoError(t, warehouses.Create(ctx, &models.Warehouse{Code: "old", Name: "Старий склад", Priority: 20}))
+	inv := &memInventoryRepo{products: products}
+	svc := services.NewInventoryService(inv, products, warehouses)
+
+	_, err := svc.RecordMovement(ctx, 1, services.MovementInput{Type: models.StockReceipt, Quantity: 10}, 1)
+	require.NoError(t, err)
+
+	moved, err := svc.Transfer(ctx, 1, services.TransferInput{FromWarehouseID: 1, ToWarehouseID: 2, Quantity: 4, Reason: "вітрина"}, 7)
+	require.NoError(t, err)
+	require.Len(t, moved, 2)
+	assert.Equal(t, -4, moved[0].Quantity)
+	assert.Equal(t, uint(1), *moved[0].WarehouseID)
+	assert.Equal(t, 4, moved[1].Quantity)
+	assert.Equal(t, uint(2), *moved[1].WarehouseID)
+	assert.Equal(t, models.StockTransfer, moved[1].Type)
+	assert.Equal(t, 10, products.data[1].Stock)
+	assert.Equal(t, 6, inv.stock[[2]uint{1, 1}])
+	assert.Equal(t, 4, inv.stock[[2]uint{2, 1}])
+
+	_, err = svc.Transfer(ctx, 1, services.TransferInput{FromWarehouseID: 2, ToWarehouseID: 1, Quantity: 5}, 7)
+	assert.ErrorIs(t, err, services.ErrInsufficientStock)
+	_, err = svc.Transfer(ctx, 1, services.TransferInput{FromWarehouseID: 1, ToWarehouseID: 3, Quantity: 1}, 7)
+	assert.ErrorIs(t, err, services.ErrWarehouseInactive)
+	_, err = svc.Transfer(ctx, 1, services.TransferInput{FromWarehouseID: 1, ToWarehouseID: 1, Quantity: 1}, 7)
+	assert.ErrorIs(t, err, services.ErrInvalidMovement)
+	_, err = svc.Transfer(ctx, 1, services.TransferInput{FromWarehouseID: 1, ToWarehouseID: 42, Quantity: 1}, 7)
+	assert.ErrorIs(t, err, services.ErrWarehouseNotFound)
+
+	_, err = svc.RecordMovement(ctx, 1, services.MovementInput{Type: models.StockReceipt, Quantity: 1, WarehouseID: 3}, 7)
+	assert.ErrorIs(t, err, services.ErrWarehouseInactive)
+	m, err := svc.RecordMovement(ctx, 1, services.MovementInput{Type: models.StockReceipt, Quantity: 2, WarehouseID: 2}, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 12, m.StockAfter)
+	assert.Equal(t, 6, inv.stock[[2]uint{2, 1}])
+}
+
+// Тест: PUT продукту не змінює залишок — лише рухи журналу складу
+
+func TestUpdateProductKeepsStock(t *testing.T) {
+	ctx := context.Background()
+	products := newMemRepo()
+	svc := services.NewProductService(products, newMemCategoryRepo())
+	created, err := svc.CreateProduct(ctx, &models.Product{Name: "Нашийник", PriceCents: 3000, Stock: 4})
+	require.NoError(t, err)
+
+	updated, err := svc.UpdateProduct(ctx, &models.Product{ID: created.ID, Name: "Нашийник", PriceCents: 3500, Stock: 100})
+	require.NoError(t, err)
+	assert.Equal(t, 4, updated.Stock)
+	assert.Equal(t, 4, products.data[created.ID].Stock)
+}
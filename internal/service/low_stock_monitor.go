@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/notify"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+)
+
+// lowStockBatch — скільки продуктів позначається і надсилається одним сповіщенням
+
+const lowStockBatch = 100
+
+// LowStockMonitor — фонова перевірка залишків: знаходить продукти, що опустилися до порогу
+// дозамовлення, і надсилає про них сповіщення через notify.Notifier. Про кожен перетин порогу
+// сповіщається один раз; коли залишок поповнюють вище порогу, продукт знову відстежується.
+
+type LowStockMonitor struct {
+	alerts   repositories.AlertRepository
+	notifier notify.Notifier
+}
+
+// NewLowStockMonitor створює новий LowStockMonitor
+
+func NewLowStockMonitor(alerts repositories.AlertRepository, notifier notify.Notifier) *LowStockMonitor {
+	return &LowStockMonitor{alerts: alerts, notifier: notifier}
+}
+
+// Check виконує одну перевірку і повертає кількість надісланих сповіщень.
+// Якщо доставка не вдалася, позначки знімаються — продукти потраплять у наступну перевірку.
+
+func (m *LowStockMonitor) Check(ctx context.Context) (int, error) {
+	if _, err := m.alerts.Resolve(ctx); err != nil {
+		return 0, err
+	}
+	sent := 0
+	for {
+		products, err := m.alerts.ClaimLowStock(ctx, lowStockBatch)
+		if err != nil {
+			return sent, err
+		}
+		if len(products) == 0 {
+			return sent, nil
+		}
+		if err := m.notifier.NotifyLowStock(ctx, lowStockAlerts(products)); err != nil {
+			// Знімаємо позначки навіть якщо ctx уже скасовано — інакше сповіщення загубиться
+			if rerr := m.alerts.Release(context.WithoutCancel(ctx), productIDs(products)); rerr != nil {
+				log.Printf("Не вдалося зняти позначки сповіщень: %v", rerr)
+			}
+			return sent, err
+		}
+		sent += len(products)
+		if len(products) < lowStockBatch {
+			return sent, nil
+		}
+	}
+}
+
+// Start запускає перевірку одразу і далі кожні interval у фоновій горутині до скасування ctx;
+// interval <= 0 — перевірку вимкнено
+
+func (m *LowStockMonitor) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if n, err := m.Check(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Помилка перевірки низьких залишків: %v", err)
+			} else if n > 0 {
+				log.Printf("Надіслано сповіщень про низький залишок: %d", n)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// lowStockAlerts будує сповіщення з продуктів
+
+func lowStockAlerts(products []models.Product) []notify.LowStockAlert {
+	now := time.Now().UTC()
+	alerts := make([]notify.LowStockAlert, len(products))
+	for i, p := range products {
+		alerts[i] = notify.LowStockAlert{
+			ProductID: p.ID, Name: p.Name, SKU: p.SKU, Stock: p.Stock,
+			ReorderPoint: p.ReorderPoint, ReorderQuantity: p.ReorderQuantity, DetectedAt: now,
+		}
+	}
+	return alerts
+}
+
+// productIDs повертає ID продуктів
+
+func productIDs(products []models.Product) []uint {
+	ids := make([]uint, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	return ids
+}
@@ -0,0 +1,123 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/notify"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// memAlertRepo — in-memory stock_alerts поверх memRepo
+
+type memAlertRepo struct {
+	products *memRepo
+	claimed  map[uint]bool
+}
+
+func (m *memAlertRepo) ClaimLowStock(ctx context.Context, limit int) ([]models.Product, error) {
+	var out []models.Product
+	for id := uint(1); id < m.products.next && len(out) < limit; id++ {
+		p, ok := m.products.data[id]
+		if ok && !m.claimed[id] && p.ReorderPoint > 0 && p.Stock <= p.ReorderPoint {
+			m.claimed[id] = true
+			out = append(out, *p)
+		}
+	}
+	return out, nil
+}
+
+func (m *memAlertRepo) Release(ctx context.Context, productIDs []uint) error {
+	for _, id := range productIDs {
+		delete(m.claimed, id)
+	}
+	return nil
+}
+
+func (m *memAlertRepo) Resolve(ctx context.Context) (int64, error) {
+	var n int64
+	for id := range m.claimed {
+		if p, ok := m.products.data[id]; !ok || p.ReorderPoint == 0 || p.Stock > p.ReorderPoint {
+			delete(m.claimed, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// recordingNotifier запам'ятовує надіслані сповіщення; err імітує недоступний канал доставки
+
+type recordingNotifier struct {
+	sent []notify.LowStockAlert
+	err  error
+}
+
+func (n *recordingNotifier) NotifyLowStock(ctx context.Context, alerts []notify.LowStockAlert) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, alerts...)
+	return nil
+}
+
+// Тест: сповіщення надсилається один раз на перетин порогу і знову — після поповнення і нового падіння
+
+func TestLowStockMonitor(t *testing.T) {
+	ctx := context.Background()
+	products := newMemRepo()
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Корм", SKU: "CAT-1", PriceCents: 10000, Stock: 2, ReorderPoint: 5, ReorderQuantity: 20}))
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Миска", PriceCents: 5000, Stock: 10, ReorderPoint: 5}))
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Іграшка", PriceCents: 2500, Stock: 0}))
+
+	notifier := &recordingNotifier{}
+	monitor := services.NewLowStockMonitor(&memAlertRepo{products: products, claimed: map[uint]bool{}}, notifier)
+
+	n, err := monitor.Check(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, notify.LowStockAlert{
+		ProductID: 1, Name: "Корм", SKU: "CAT-1", Stock: 2, ReorderPoint: 5, ReorderQuantity: 20, DetectedAt: notifier.sent[0].DetectedAt,
+	}, notifier.sent[0])
+
+	n, err = monitor.Check(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n, "повторна перевірка не дублює сповіщення")
+
+	products.data[1].Stock = 30 // поповнення
+	products.data[2].Stock = 5  // друга позиція дійшла до порогу
+	n, err = monitor.Check(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, uint(2), notifier.sent[1].ProductID)
+
+	products.data[1].Stock = 4
+	n, err = monitor.Check(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, uint(1), notifier.sent[2].ProductID)
+}
+
+// Тест: якщо доставка не вдалася, сповіщення повторюється при наступній перевірці
+
+func TestLowStockMonitorRetry(t *testing.T) {
+	ctx := context.Background()
+	products := newMemRepo()
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Корм", PriceCents: 10000, Stock: 1, ReorderPoint: 3}))
+
+	notifier := &recordingNotifier{err: errors.New("webhook unavailable")}
+	monitor := services.NewLowStockMonitor(&memAlertRepo{products: products, claimed: map[uint]bool{}}, notifier)
+
+	_, err := monitor.Check(ctx)
+	require.Error(t, err)
+
+	notifier.err = nil
+	n, err := monitor.Check(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
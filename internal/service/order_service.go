@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Помилки сервісу замовлень
+
+var (
+	ErrEmptyOrder        = apperr.Validation("empty_order", "order has no items")                              // немає позицій для оформлення (порожній кошик)
+	ErrOrderNotFound     = apperr.NotFound("order_not_found", "order not found")                               // замовлення не знайдено або належить іншому користувачу
+	ErrInvalidStatus     = apperr.Validation("invalid_order_status", "unknown order status")                   // статус відсутній у таблиці переходів
+	ErrInvalidTransition = apperr.Conflict("invalid_status_transition", "order status transition not allowed") // перехід не дозволено з поточного статусу
+)
+
+// CheckoutItem — продукт (і, за потреби, його варіант) та кількість для оформлення замовлення без кошика
+
+type CheckoutItem struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	VariantID uint `json:"variant_id"` // 0 — продукт без варіанту
+	Quantity  int  `json:"quantity" binding:"required,gt=0"`
+}
+
+// OrderService визначає бізнес-логіку замовлень
+
+type OrderService interface {
+	Checkout(ctx context.Context, userID uint, items []CheckoutItem) (*models.Order, error)        // items порожній — оформлюється кошик користувача
+	GetOrder(ctx context.Context, userID, orderID uint) (*models.Order, error)                     // повертає ErrOrderNotFound для чужих замовлень
+	ListOrders(ctx context.Context, userID uint, limit, offset int) ([]models.Order, int64, error) // returns items, totalCount
+
+	// Методи для адміністраторів
+
+	GetAnyOrder(ctx context.Context, orderID uint) (*models.Order, error)                                        // замовлення будь-якого користувача з історією
+	ListAllOrders(ctx context.Context, status string, limit, offset int) ([]models.Order, int64, error)          // всі замовлення з фільтром за статусом
+	ChangeStatus(ctx context.Context, orderID uint, to string, actorID uint, note string) (*models.Order, error) // перевіряє перехід за таблицею і записує історію
+}
+
+// orderService реалізує OrderService
+
+type orderService struct {
+	orders repositories.OrderRepository
+	carts  repositories.CartRepository
+}
+
+// NewOrderService створює новий OrderService
+
+func NewOrderService(orders repositories.OrderRepository, carts repositories.CartRepository) OrderService {
+	return &orderService{orders: orders, carts: carts}
+}
+
+// Checkout перетворює кошик (або передані позиції) на замовлення.
+// Резервування залишків, знімок цін і створення замовлення відбуваються в одній транзакції
+// з блокуванням рядків продуктів, тому два покупці не можуть купити останню одиницю одночасно.
+
+func (s *orderService) Checkout(ctx context.Context, userID uint, items []CheckoutItem) (*models.Order, error) {
+	var cartID uint
+	if len(items) == 0 {
+		cart, err := s.carts.GetOrCreate(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		cartID = cart.ID
+		for _, item := range cart.Items {
+			if item.Product.ID == 0 {
+				continue // продукт видалено з каталогу
+			}
+			items = append(items, CheckoutItem{ProductID: item.ProductID, VariantID: cartVariantID(&item), Quantity: item.Quantity})
+		}
+	}
+
+	// Об'єднуємо повтори одного продукту (з тим самим варіантом) і перевіряємо кількість
+
+	type lineKey struct{ productID, variantID uint }
+	quantities := map[lineKey]int{}
+	var order []lineKey
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+		key := lineKey{item.ProductID, item.VariantID}
+		if _, ok := quantities[key]; !ok {
+			order = append(order, key)
+		}
+		quantities[key] += item.Quantity
+	}
+	if len(order) == 0 {
+		return nil, ErrEmptyOrder
+	}
+
+	o := &models.Order{UserID: userID, Status: models.OrderStatusPending}
+	for _, key := range order {
+		item := models.OrderItem{ProductID: key.productID, Quantity: quantities[key]}
+		if key.variantID != 0 {
+			variantID := key.variantID
+			item.VariantID = &variantID
+		}
+		o.Items = append(o.Items, item)
+	}
+
+	if err := s.orders.CreateWithStock(ctx, o, cartID); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrOutOfStock):
+			return nil, ErrInsufficientStock
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrNotFound // продукт або його варіант
+		}
+		return nil, err
+	}
+	return o, nil
+}
+
+// GetOrder повертає замовлення, якщо воно належить користувачу
+
+func (s *orderService) GetOrder(ctx context.Context, userID, orderID uint) (*models.Order, error) {
+	o, err := s.orders.GetByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	if o.UserID != userID {
+		return nil, ErrOrderNotFound // не розкриваємо існування чужих замовлень
+	}
+	return o, nil
+}
+
+// ListOrders повертає замовлення користувача з пагінацією (limit, offset)
+
+func (s *orderService) ListOrders(ctx context.Context, userID uint, limit, offset int) ([]models.Order, int64, error) {
+	return s.orders.ListByUser(ctx, userID, limit, offset)
+}
+
+// GetAnyOrder повертає замовлення за ID без перевірки власника (для адміністраторів)
+
+func (s *orderService) GetAnyOrder(ctx context.Context, orderID uint) (*models.Order, error) {
+	o, err := s.orders.GetByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return o, nil
+}
+
+// ListAllOrders повертає всі замовлення; status порожній — без фільтра
+
+func (s *orderService) ListAllOrders(ctx context.Context, status string, limit, offset int) ([]models.Order, int64, error) {
+	if status != "" && !IsValidOrderStatus(status) {
+		return nil, 0, ErrInvalidStatus
+	}
+	return s.orders.List(ctx, status, limit, offset)
+}
+
+// ChangeStatus переводить замовлення в новий статус, якщо це дозволено таблицею переходів.
+// Скасування (і повернення коштів до відправлення) повертає зарезервований товар на склад
+// в тій самій транзакції, що й зміна статусу.
+
+func (s *orderService) ChangeStatus(ctx context.Context, orderID uint, to string, actorID uint, note string) (*models.Order, error) {
+	if !IsValidOrderStatus(to) {
+		return nil, ErrInvalidStatus
+	}
+	o, err := s.GetAnyOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !CanTransition(o.Status, to) {
+		return nil, ErrInvalidTransition
+	}
+
+	h := &models.OrderStatusHistory{
+		OrderID:    o.ID,
+		FromStatus: o.Status,
+		ToStatus:   to,
+		ChangedBy:  actorID,
+		Note:       note,
+	}
+	if err := s.orders.UpdateStatus(ctx, h, releasesStock(o.Status, to)); err != nil {
+		if errors.Is(err, repositories.ErrStatusConflict) {
+			return nil, ErrInvalidTransition // статус змінив інший адміністратор — потрібно перечитати замовлення
+		}
+		return nil, err
+	}
+	return s.GetAnyOrder(ctx, orderID)
+}
@@ -0,0 +1,247 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// Простий in-memory repo реалізує repositories.OrderRepository для тестів.
+// CreateWithStock повторює логіку транзакції: перевірка залишків, знімок цін, очищення кошика.
+
+type memOrderRepo struct {
+	products *memRepo
+	carts    *memCartRepo
+	orders   map[uint]*models.Order
+}
+
+func newMemOrderRepo(products *memRepo, carts *memCartRepo) *memOrderRepo {
+	return &memOrderRepo{products: products, carts: carts, orders: map[uint]*models.Order{}}
+}
+
+func (m *memOrderRepo) CreateWithStock(ctx context.Context, o *models.Order, cartID uint) error {
+	for _, item := range o.Items {
+		p, ok := m.products.data[item.ProductID]
+		if !ok {
+			return gorm.ErrRecordNotFound
+		}
+		stock := p.Stock
+		if item.VariantID != nil {
+			v, ok := m.carts.variants.data[*item.VariantID]
+			if !ok || v.ProductID != item.ProductID {
+				return gorm.ErrRecordNotFound
+			}
+			stock = v.Stock
+		}
+		if stock < item.Quantity {
+			return repositories.ErrOutOfStock
+		}
+	}
+	for i := range o.Items {
+		item := &o.Items[i]
+		p := m.products.data[item.ProductID]
+		item.ProductName, item.SKU, item.UnitPriceCents = p.Name, p.SKU, p.PriceCents
+		line := memCartLine{productID: item.ProductID}
+		if item.VariantID != nil {
+			v := m.carts.variants.data[*item.VariantID]
+			v.Stock -= item.Quantity
+			item.SKU, item.UnitPriceCents = v.SKU, v.PriceCents
+			line.variantID = v.ID
+		} else {
+			p.Stock -= item.Quantity
+		}
+		item.SubtotalCents = item.UnitPriceCents * int64(item.Quantity)
+		o.TotalCents += item.SubtotalCents
+		if cartID != 0 {
+			delete(m.carts.items[cartID], line)
+		}
+	}
+	o.ID = uint(len(m.orders) + 1)
+	m.orders[o.ID] = o
+	return nil
+}
+
+func (m *memOrderRepo) GetByID(ctx context.Context, id uint) (*models.Order, error) {
+	o, ok := m.orders[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return o, nil
+}
+
+func (m *memOrderRepo) ListByUser(ctx context.Context, userID uint, limit, offset int) ([]models.Order, int64, error) {
+	var out []models.Order
+	for _, o := range m.orders {
+		if o.UserID == userID {
+			out = append(out, *o)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (m *memOrderRepo) List(ctx context.Context, status string, limit, offset int) ([]models.Order, int64, error) {
+	var out []models.Order
+	for _, o := range m.orders {
+		if status == "" || o.Status == status {
+			out = append(out, *o)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (m *memOrderRepo) UpdateStatus(ctx context.Context, h *models.OrderStatusHistory, restock bool) error {
+	o := m.orders[h.OrderID]
+	if o.Status != h.FromStatus {
+		return repositories.ErrStatusConflict
+	}
+	o.Status = h.ToStatus
+	o.History = append(o.History, *h)
+	if restock {
+		for _, item := range o.Items {
+			if item.VariantID != nil {
+				m.carts.variants.data[*item.VariantID].Stock += item.Quantity
+				continue
+			}
+			m.products.data[item.ProductID].Stock += item.Quantity
+		}
+	}
+	return nil
+}
+
+// Тест: оформлення кошика фіксує ціну, зменшує залишок і очищає кошик
+
+func TestCheckoutFromCart(t *testing.T) {
+	ctx := context.Background()
+	products := newMemRepo()
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Корм", PriceCents: 10000, Stock: 3}))
+	carts := newMemCartRepo(products)
+	cartSvc := services.NewCartService(carts, products, carts.variants)
+	orderSvc := services.NewOrderService(newMemOrderRepo(products, carts), carts)
+
+	_, err := cartSvc.AddItem(ctx, 1, 1, 0, 2)
+	require.NoError(t, err)
+
+	order, err := orderSvc.Checkout(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusPending, order.Status)
+	assert.Equal(t, int64(20000), order.TotalCents)
+	assert.Equal(t, "Корм", order.Items[0].ProductName)
+	assert.Equal(t, 1, products.data[1].Stock)
+
+	cart, err := cartSvc.GetCart(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, cart.Items)
+
+	// Кошик порожній — оформлювати нічого
+
+	_, err = orderSvc.Checkout(ctx, 1, nil)
+	assert.ErrorIs(t, err, services.ErrEmptyOrder)
+}
+
+// Тест: не можна купити більше, ніж є на складі, і не можна бачити чужі замовлення
+
+func TestCheckoutStockAndOwnership(t *testing.T) {
+	ctx := context.Background()
+	products := newMemRepo()
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Корм", PriceCents: 10000, Stock: 1}))
+	carts := newMemCartRepo(products)
+	orderSvc := services.NewOrderService(newMemOrderRepo(products, carts), carts)
+
+	_, err := orderSvc.Checkout(ctx, 1, []services.CheckoutItem{{ProductID: 1, Quantity: 2}})
+	assert.ErrorIs(t, err, services.ErrInsufficientStock)
+	assert.Equal(t, 1, products.data[1].Stock)
+
+	order, err := orderSvc.Checkout(ctx, 1, []services.CheckoutItem{{ProductID: 1, Quantity: 1}})
+	require.NoError(t, err)
+
+	_, err = orderSvc.GetOrder(ctx, 2, order.ID)
+	assert.ErrorIs(t, err, services.ErrOrderNotFound)
+}
+
+// Тест таблиці переходів статусів
+
+func TestCanTransition(t *testing.T) {
+	assert.True(t, services.CanTransition(models.OrderStatusPending, models.OrderStatusPaid))
+	assert.True(t, services.CanTransition(models.OrderStatusShipped, models.OrderStatusDelivered))
+	assert.False(t, services.CanTransition(models.OrderStatusPending, models.OrderStatusShipped))
+	assert.False(t, services.CanTransition(models.OrderStatusDelivered, models.OrderStatusCancelled))
+	assert.False(t, services.CanTransition(models.OrderStatusCancelled, models.OrderStatusPaid))
+}
+
+// Тест: скасування повертає товар на склад і записує історію, недозволений перехід відхиляється
+
+func TestChangeStatusCancelRestocks(t *testing.T) {
+	ctx := context.Background()
+	products := newMemRepo()
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Корм", PriceCents: 10000, Stock: 3}))
+	carts := newMemCartRepo(products)
+	orderSvc := services.NewOrderService(newMemOrderRepo(products, carts), carts)
+
+	order, err := orderSvc.Checkout(ctx, 1, []services.CheckoutItem{{ProductID: 1, Quantity: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, products.data[1].Stock)
+
+	_, err = orderSvc.ChangeStatus(ctx, order.ID, models.OrderStatusShipped, 99, "")
+	assert.ErrorIs(t, err, services.ErrInvalidTransition)
+
+	_, err = orderSvc.ChangeStatus(ctx, order.ID, "lost", 99, "")
+	assert.ErrorIs(t, err, services.ErrInvalidStatus)
+
+	order, err = orderSvc.ChangeStatus(ctx, order.ID, models.OrderStatusCancelled, 99, "клієнт передумав")
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderStatusCancelled, order.Status)
+	assert.Equal(t, 3, products.data[1].Stock)
+	require.Len(t, order.History, 1)
+	assert.Equal(t, uint(99), order.History[0].ChangedBy)
+}
+
+// Тест: варіант у кошику й замовленні має власні ціну, артикул і залишок; скасування повертає товар у варіант
+
+func TestCheckoutVariant(t *testing.T) {
+	ctx := context.Background()
+	products := newMemRepo()
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Корм", SKU: "FOOD", PriceCents: 10000, Stock: 10}))
+	carts := newMemCartRepo(products)
+	require.NoError(t, carts.variants.Create(ctx, &models.ProductVariant{ProductID: 1, SKU: "FOOD-10KG", PriceCents: 45000, Stock: 2}))
+	cartSvc := services.NewCartService(carts, products, carts.variants)
+	orderSvc := services.NewOrderService(newMemOrderRepo(products, carts), carts)
+
+	_, err := cartSvc.AddItem(ctx, 1, 1, 1, 3)
+	assert.ErrorIs(t, err, services.ErrInsufficientStock, "залишок варіанту, а не продукту")
+	_, err = cartSvc.AddItem(ctx, 1, 1, 99, 1)
+	assert.ErrorIs(t, err, services.ErrVariantNotFound)
+
+	_, err = cartSvc.AddItem(ctx, 1, 1, 0, 1)
+	require.NoError(t, err)
+	cart, err := cartSvc.AddItem(ctx, 1, 1, 1, 2)
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 2)
+	assert.Equal(t, int64(10000+2*45000), cart.TotalCents)
+
+	order, err := orderSvc.Checkout(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10000+2*45000), order.TotalCents)
+	var variantItem *models.OrderItem
+	for i := range order.Items {
+		if order.Items[i].VariantID != nil {
+			variantItem = &order.Items[i]
+		}
+	}
+	require.NotNil(t, variantItem)
+	assert.Equal(t, "FOOD-10KG", variantItem.SKU)
+	assert.Equal(t, int64(45000), variantItem.UnitPriceCents)
+	assert.Equal(t, 0, carts.variants.data[1].Stock)
+	assert.Equal(t, 9, products.data[1].Stock)
+
+	_, err = orderSvc.ChangeStatus(ctx, order.ID, models.OrderStatusCancelled, 99, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, carts.variants.data[1].Stock)
+	assert.Equal(t, 10, products.data[1].Stock)
+}
@@ -0,0 +1,48 @@
+package services
+
+import "github.com/AlexRijikov/go-petshop-api/internal/models"
+
+// orderTransitions — дозволені переходи між статусами замовлення.
+// cancelled і refunded — кінцеві статуси.
+
+var orderTransitions = map[string][]string{
+	models.OrderStatusPending:   {models.OrderStatusPaid, models.OrderStatusCancelled},
+	models.OrderStatusPaid:      {models.OrderStatusPacked, models.OrderStatusCancelled, models.OrderStatusRefunded},
+	models.OrderStatusPacked:    {models.OrderStatusShipped, models.OrderStatusCancelled},
+	models.OrderStatusShipped:   {models.OrderStatusDelivered},
+	models.OrderStatusDelivered: {models.OrderStatusRefunded},
+	models.OrderStatusCancelled: {},
+	models.OrderStatusRefunded:  {},
+}
+
+// IsValidOrderStatus перевіряє, чи статус відомий
+
+func IsValidOrderStatus(status string) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// CanTransition перевіряє, чи дозволено перехід замовлення зі статусу from у статус to
+
+func CanTransition(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// releasesStock визначає, чи перехід повертає зарезервований товар на склад:
+// скасування або повернення коштів до відправлення (товар ще фізично на складі)
+
+func releasesStock(from, to string) bool {
+	if to != models.OrderStatusCancelled && to != models.OrderStatusRefunded {
+		return false
+	}
+	switch from {
+	case models.OrderStatusPending, models.OrderStatusPaid, models.OrderStatusPacked:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Помилки сервісу тварин
+
+var (
+	ErrPetNotFound    = apperr.NotFound("pet_not_found", "pet not found")                              // тварину не знайдено (або вона чужа)
+	ErrInvalidPet     = apperr.Validation("invalid_pet", "invalid pet")                                // кличка, порода, дата народження, вага або алергії некоректні
+	ErrUnknownSpecies = apperr.Validation("unknown_species", "unknown species")                        // виду немає в models.PetSpecies
+	ErrTooManyPets    = apperr.Conflict("pet_limit_reached", "maximum number of pets already reached") // досягнуто maxPetsPerUser
+)
+
+// Обмеження профілю тварини
+
+const (
+	maxPetsPerUser  = 20  // тварин в одного користувача
+	maxPetAllergies = 20  // алергенів у однієї тварини
+	maxPetWeightKg  = 200 // кілограмів
+	maxPetAgeYears  = 50  // років від дати народження
+	maxAllergenLen  = 50  // символів у назві алергену
+)
+
+// PetService визначає бізнес-логіку профілів тварин поточного користувача
+
+type PetService interface {
+	ListPets(ctx context.Context, userID uint) ([]models.Pet, error)   // тварини користувача
+	GetPet(ctx context.Context, userID, id uint) (*models.Pet, error)  // ErrPetNotFound якщо не знайдено
+	CreatePet(ctx context.Context, p *models.Pet) (*models.Pet, error) // ErrInvalidPet, ErrUnknownSpecies, ErrTooManyPets
+	UpdatePet(ctx context.Context, p *models.Pet) (*models.Pet, error) // замінює всі поля; ErrPetNotFound, ErrInvalidPet, ErrUnknownSpecies
+	DeletePet(ctx context.Context, userID, id uint) error              // ErrPetNotFound якщо не знайдено
+}
+
+// petService реалізує PetService
+
+type petService struct {
+	repo repositories.PetRepository
+}
+
+// NewPetService створює новий PetService
+
+func NewPetService(r repositories.PetRepository) PetService {
+	return &petService{repo: r}
+}
+
+// ListPets повертає тварин користувача
+
+func (s *petService) ListPets(ctx context.Context, userID uint) ([]models.Pet, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// GetPet повертає тварину користувача за ID
+
+func (s *petService) GetPet(ctx context.Context, userID, id uint) (*models.Pet, error) {
+	p, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, translatePetError(err)
+	}
+	return p, nil
+}
+
+// CreatePet перевіряє і додає тварину користувачу p.UserID
+
+func (s *petService) CreatePet(ctx context.Context, p *models.Pet) (*models.Pet, error) {
+	if err := validatePet(p); err != nil {
+		return nil, err
+	}
+	count, err := s.repo.CountByUser(ctx, p.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxPetsPerUser {
+		return nil, ErrTooManyPets.WithDetail(fmt.Sprintf("at most %d pets per user", maxPetsPerUser))
+	}
+	if err := s.repo.Create(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// UpdatePet замінює профіль тварини; змінити можна лише власну тварину
+
+func (s *petService) UpdatePet(ctx context.Context, p *models.Pet) (*models.Pet, error) {
+	existing, err := s.GetPet(ctx, p.UserID, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePet(p); err != nil {
+		return nil, err
+	}
+	p.CreatedAt = existing.CreatedAt
+	if err := s.repo.Update(ctx, p); err != nil {
+		return nil, translatePetError(err)
+	}
+	return p, nil
+}
+
+// DeletePet видаляє тварину користувача
+
+func (s *petService) DeletePet(ctx context.Context, userID, id uint) error {
+	return translatePetError(s.repo.Delete(ctx, userID, id))
+}
+
+// validatePet нормалізує і перевіряє профіль: вид — з відомого списку, алергени — у нижньому регістрі без повторів
+
+func validatePet(p *models.Pet) error {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Breed = strings.TrimSpace(p.Breed)
+	p.Species = strings.ToLower(strings.TrimSpace(p.Species))
+
+	if !slices.Contains(models.PetSpecies, p.Species) {
+		return ErrUnknownSpecies.WithDetail(fmt.Sprintf("species must be one of: %s", strings.Join(models.PetSpecies, ", ")))
+	}
+	switch {
+	case p.Name == "" || utf8.RuneCountInString(p.Name) > 50:
+		return ErrInvalidPet.WithDetail("name must be 1-50 characters")
+	case utf8.RuneCountInString(p.Breed) > 100:
+		return ErrInvalidPet.WithDetail("breed must be at most 100 characters")
+	case p.WeightKg < 0 || p.WeightKg > maxPetWeightKg:
+		return ErrInvalidPet.WithDetail(fmt.Sprintf("weight_kg must be between 0 and %d", maxPetWeightKg))
+	}
+	if p.BirthDate != nil {
+		now := time.Now()
+		if p.BirthDate.After(now) {
+			return ErrInvalidPet.WithDetail("birth_date must not be in the future")
+		}
+		if p.BirthDate.Before(now.AddDate(-maxPetAgeYears, 0, 0)) {
+			return ErrInvalidPet.WithDetail(fmt.Sprintf("birth_date must be within the last %d years", maxPetAgeYears))
+		}
+	}
+
+	allergies := models.StringList{}
+	for _, a := range p.Allergies {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == "" || utf8.RuneCountInString(a) > maxAllergenLen {
+			return ErrInvalidPet.WithDetail(fmt.Sprintf("allergies must be 1-%d characters each", maxAllergenLen))
+		}
+		if !slices.Contains(allergies, a) {
+			allergies = append(allergies, a)
+		}
+	}
+	if len(allergies) > maxPetAllergies {
+		return ErrInvalidPet.WithDetail(fmt.Sprintf("at most %d allergies", maxPetAllergies))
+	}
+	p.Allergies = allergies
+	return nil
+}
+
+// translatePetError перетворює помилки БД на помилки сервісу тварин
+
+func translatePetError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrPetNotFound
+	}
+	return err
+}
@@ -0,0 +1,158 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// memPetRepo — in-memory тварини; як і справжній репозиторій, чужу тварину не знаходить
+
+type memPetRepo struct {
+	data map[uint]*models.Pet
+	next uint
+}
+
+func newMemPetRepo() *memPetRepo {
+	return &memPetRepo{data: map[uint]*models.Pet{}, next: 1}
+}
+
+func (m *memPetRepo) ListByUser(ctx context.Context, userID uint) ([]models.Pet, error) {
+	var out []models.Pet
+	for id := uint(1); id < m.next; id++ {
+		if p, ok := m.data[id]; ok && p.UserID == userID {
+			out = append(out, *p)
+		}
+	}
+	return out, nil
+}
+
+func (m *memPetRepo) CountByUser(ctx context.Context, userID uint) (int64, error) {
+	items, _ := m.ListByUser(ctx, userID)
+	return int64(len(items)), nil
+}
+
+func (m *memPetRepo) GetByID(ctx context.Context, userID, id uint) (*models.Pet, error) {
+	p, ok := m.data[id]
+	if !ok || p.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *p
+	return &cp, nil
+}
+
+func (m *memPetRepo) Create(ctx context.Context, p *models.Pet) error {
+	p.ID = m.next
+	m.next++
+	cp := *p
+	m.data[p.ID] = &cp
+	return nil
+}
+
+func (m *memPetRepo) Update(ctx context.Context, p *models.Pet) error {
+	if _, err := m.GetByID(ctx, p.UserID, p.ID); err != nil {
+		return err
+	}
+	cp := *p
+	m.data[p.ID] = &cp
+	return nil
+}
+
+func (m *memPetRepo) Delete(ctx context.Context, userID, id uint) error {
+	if _, err := m.GetByID(ctx, userID, id); err != nil {
+		return err
+	}
+	delete(m.data, id)
+	return nil
+}
+
+// Тест створення тварини: нормалізація виду й алергенів
+
+func TestCreatePet(t *testing.T) {
+	ctx := context.Background()
+	svc := services.NewPetService(newMemPetRepo())
+	born := time.Now().AddDate(-3, 0, 0)
+
+	p, err := svc.CreatePet(ctx, &models.Pet{
+		UserID: 1, Name: " Барсик ", Species: "Cat", BirthDate: &born, WeightKg: 4.2,
+		Allergies: []string{" Chicken", "chicken", "grain"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Барсик", p.Name)
+	assert.Equal(t, models.SpeciesCat, p.Species)
+	assert.Equal(t, models.StringList{"chicken", "grain"}, p.Allergies)
+
+	p, err = svc.CreatePet(ctx, &models.Pet{UserID: 1, Name: "Рекс", Species: "dog"})
+	require.NoError(t, err)
+	assert.NotNil(t, p.Allergies)
+}
+
+// Тест валідації профілю: невідомий вид, дата народження в майбутньому, вага, порожній алерген
+
+func TestCreatePetInvalid(t *testing.T) {
+	ctx := context.Background()
+	svc := services.NewPetService(newMemPetRepo())
+	future := time.Now().AddDate(0, 1, 0)
+	ancient := time.Now().AddDate(-60, 0, 0)
+
+	_, err := svc.CreatePet(ctx, &models.Pet{UserID: 1, Name: "Дракон", Species: "dragon"})
+	assert.ErrorIs(t, err, services.ErrUnknownSpecies)
+
+	for _, bad := range []models.Pet{
+		{UserID: 1, Name: "   ", Species: "dog"},
+		{UserID: 1, Name: "Рекс", Species: "dog", BirthDate: &future},
+		{UserID: 1, Name: "Рекс", Species: "dog", BirthDate: &ancient},
+		{UserID: 1, Name: "Рекс", Species: "dog", WeightKg: -1},
+		{UserID: 1, Name: "Рекс", Species: "dog", Allergies: []string{"beef", " "}},
+	} {
+		_, err := svc.CreatePet(ctx, &bad)
+		assert.ErrorIs(t, err, services.ErrInvalidPet, bad)
+	}
+}
+
+// Тест власності: чужу тварину не можна отримати, змінити чи видалити
+
+func TestPetOwnership(t *testing.T) {
+	ctx := context.Background()
+	svc := services.NewPetService(newMemPetRepo())
+	p, err := svc.CreatePet(ctx, &models.Pet{UserID: 1, Name: "Кеша", Species: "bird"})
+	require.NoError(t, err)
+
+	_, err = svc.GetPet(ctx, 2, p.ID)
+	assert.ErrorIs(t, err, services.ErrPetNotFound)
+	_, err = svc.UpdatePet(ctx, &models.Pet{ID: p.ID, UserID: 2, Name: "Кеша", Species: "bird"})
+	assert.ErrorIs(t, err, services.ErrPetNotFound)
+	assert.ErrorIs(t, svc.DeletePet(ctx, 2, p.ID), services.ErrPetNotFound)
+
+	updated, err := svc.UpdatePet(ctx, &models.Pet{ID: p.ID, UserID: 1, Name: "Кеша", Species: "bird", Breed: "хвилястий папуга"})
+	require.NoError(t, err)
+	assert.Equal(t, "хвилястий папуга", updated.Breed)
+
+	require.NoError(t, svc.DeletePet(ctx, 1, p.ID))
+	items, err := svc.ListPets(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+// Тест обмеження кількості тварин в одного користувача
+
+func TestPetLimit(t *testing.T) {
+	ctx := context.Background()
+	svc := services.NewPetService(newMemPetRepo())
+	for i := 0; i < 20; i++ {
+		_, err := svc.CreatePet(ctx, &models.Pet{UserID: 1, Name: "Рибка", Species: "fish"})
+		require.NoError(t, err)
+	}
+	_, err := svc.CreatePet(ctx, &models.Pet{UserID: 1, Name: "Рибка", Species: "fish"})
+	assert.ErrorIs(t, err, services.ErrTooManyPets)
+
+	_, err = svc.CreatePet(ctx, &models.Pet{UserID: 2, Name: "Рибка", Species: "fish"})
+	assert.NoError(t, err)
+}
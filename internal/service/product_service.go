@@ -1,84 +1,365 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/mergepatch"
 	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/pagination"
 	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"gorm.io/gorm"
 )
 
 // Помилки сервісу продуктів
 
 var (
-	ErrInvalidPrice = errors.New("price must be > 0") // ціна має бути більшою за 0
-	ErrNotFound     = errors.New("product not found") // продукт не знайдено
+	ErrInvalidPrice   = apperr.Validation("invalid_price", "price must be > 0")                  // ціна має бути більшою за 0
+	ErrNotFound       = apperr.NotFound("product_not_found", "product not found")                // продукт не знайдено
+	ErrInvalidFilter  = apperr.Validation("invalid_product_filter", "invalid product filter")    // некоректні параметри фільтрації або сортування
+	ErrDuplicateSKU   = apperr.Conflict("duplicate_sku", "product with this sku already exists") // артикул вже використовується іншим продуктом
+	ErrInvalidProduct = apperr.Validation("invalid_product", "invalid product")                  // продукт не пройшов валідацію (назва, залишок, довжина полів)
+	ErrInvalidPatch   = apperr.Validation("invalid_patch", "invalid merge patch")                // патч не є JSON-об'єктом, змінює службові поля або має невідомі поля
+
+	ErrVersionMismatch = apperr.PreconditionFailed("version_mismatch", "product version does not match") // очікувана версія (If-Match) застаріла — продукт вже змінили
+	ErrEditConflict    = apperr.Conflict("edit_conflict", "product was modified concurrently")           // продукт змінили між читанням і записом (без If-Match); варто повторити запит
 )
 
+// maxProductPage — скільки продуктів повертає одна сторінка каталогу (кожен з категоріями, варіантами, фото і наявністю)
+
+const maxProductPage = 100
+
 // ProductService визначає бізнес-логіку для продуктів
 
 type ProductService interface {
-	CreateProduct(ctx context.Context, p *models.Product) (*models.Product, error)        // p.ID заповнюється автоматично
-	GetProduct(ctx context.Context, id uint) (*models.Product, error)                     // повертає ErrNotFound якщо не знайдено або іншу помилку
-	ListProducts(ctx context.Context, limit, offset int) ([]models.Product, int64, error) // returns items, totalCount
-	UpdateProduct(ctx context.Context, p *models.Product) (*models.Product, error)        // повертає ErrNotFound якщо не знайдено або ErrInvalidPrice якщо ціна некоректна
-	DeleteProduct(ctx context.Context, id uint) error                                     // повертає ErrNotFound якщо не знайдено
+	CreateProduct(ctx context.Context, p *models.Product) (*models.Product, error)                                // p.ID заповнюється автоматично; ErrDuplicateSKU якщо артикул зайнятий
+	GetProduct(ctx context.Context, id uint) (*models.Product, error)                                             // повертає ErrNotFound якщо не знайдено; інші помилки БД — як є
+	ListProducts(ctx context.Context, f repositories.ProductFilter) ([]models.Product, int64, error)              // returns items, totalCount; ErrInvalidFilter для некоректних параметрів
+	ListProductsPage(ctx context.Context, f repositories.ProductFilter) (*pagination.Page[models.Product], error) // keyset-пагінація за f.Cursor; ErrInvalidFilter також для чужого курсора
+	UpdateProduct(ctx context.Context, p *models.Product) (*models.Product, error)                                // p.Version — очікувана версія (0 — будь-яка); ErrNotFound, ErrInvalidPrice, ErrVersionMismatch
+	PatchProduct(ctx context.Context, id uint, patch []byte, version uint) (*models.Product, error)               // JSON Merge Patch (RFC 7396): змінює лише передані поля; version як в UpdateProduct
+	DeleteProduct(ctx context.Context, id uint, version uint) error                                               // повертає ErrNotFound якщо не знайдено або ErrVersionMismatch якщо версія застаріла
 }
 
 // productService реалізує ProductService
 
 type productService struct {
-	repo repositories.ProductRepository
+	repo       repositories.ProductRepository
+	categories repositories.CategoryRepository // схеми атрибутів категорій
 }
 
-// NewProductService створює новий ProductService
+// NewProductService створює новий ProductService; категорії потрібні для перевірки атрибутів продукту
 
-func NewProductService(r repositories.ProductRepository) ProductService {
-	return &productService{repo: r}
+func NewProductService(r repositories.ProductRepository, categories repositories.CategoryRepository) ProductService {
+	return &productService{repo: r, categories: categories}
 }
 
-// CreateProduct створює новий продукт, перевіряє що ціна > 0 (в копійках)
+// CreateProduct створює новий продукт (разом з варіантами і категоріями), перевіряє що ціна > 0 (в копійках),
+// інші поля та атрибути за схемою категорій
 
 func (s *productService) CreateProduct(ctx context.Context, p *models.Product) (*models.Product, error) {
-	if p.PriceCents <= 0 {
-		return nil, ErrInvalidPrice
+	if err := validateProduct(p); err != nil {
+		return nil, err
 	}
-	if err := s.repo.Create(ctx, p); err != nil {
+	ids, err := normalizeCategoryIDs(categoryIDs(p.Categories))
+	if err != nil {
 		return nil, err
 	}
+	p.Categories = make([]models.Category, len(ids))
+	for i, id := range ids {
+		p.Categories[i].ID = id
+	}
+	if err := s.checkAttributes(ctx, p.Categories, p.Attributes); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, p); err != nil {
+		switch {
+		case len(p.Variants) > 0 && errors.Is(err, gorm.ErrDuplicatedKey):
+			return nil, ErrDuplicateSKU.WithDetail("sku of the product or one of its variants is already taken")
+		case errors.Is(err, gorm.ErrForeignKeyViolated):
+			return nil, ErrCategoryNotFound
+		case errors.Is(err, repositories.ErrNoWarehouse):
+			return nil, ErrNoActiveWarehouse
+		}
+		return nil, translateProductError(err)
+	}
+	if len(p.Categories) > 0 {
+		return s.GetProduct(ctx, p.ID) // повні дані категорій для відповіді
+	}
 	return p, nil
 }
 
-// GetProduct повертає продукт за ID або ErrNotFound якщо не знайдено (репозиторій повертає помилку)
+// GetProduct повертає продукт за ID або ErrNotFound якщо не знайдено.
+// Інші помилки бази (з'єднання, таймаут) не маскуються під 404.
 
 func (s *productService) GetProduct(ctx context.Context, id uint) (*models.Product, error) {
 	p, err := s.repo.GetByID(ctx, id)
 	if err != nil {
+		return nil, translateProductError(err)
+	}
+	if p == nil {
 		return nil, ErrNotFound
 	}
 	return p, nil
 }
 
-// ListProducts повертає продукти з пагінацією (limit, offset)
+// ListProducts повертає продукти з фільтрами, пошуком і пагінацією.
+// Перевіряє сортування за білим списком, що мінімальна ціна не більша за максимальну, і обмежує limit до maxProductPage.
 
-func (s *productService) ListProducts(ctx context.Context, limit, offset int) ([]models.Product, int64, error) {
-	return s.repo.List(ctx, limit, offset)
+func (s *productService) ListProducts(ctx context.Context, f repositories.ProductFilter) ([]models.Product, int64, error) {
+	if err := validateProductFilter(&f); err != nil {
+		return nil, 0, err
+	}
+	if err := s.resolveAttributeFilters(ctx, &f); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(ctx, f)
+}
+
+// ListProductsPage повертає сторінку продуктів у режимі курсорів (next_cursor/prev_cursor).
+// Фільтри перевіряються так само, як у ListProducts; курсор має бути виданий для того ж сортування.
+
+func (s *productService) ListProductsPage(ctx context.Context, f repositories.ProductFilter) (*pagination.Page[models.Product], error) {
+	if err := validateProductFilter(&f); err != nil {
+		return nil, err
+	}
+	if err := s.resolveAttributeFilters(ctx, &f); err != nil {
+		return nil, err
+	}
+	page, err := s.repo.ListByCursor(ctx, f)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		return nil, ErrInvalidFilter.WithDetail(err.Error())
+	}
+	return page, err
 }
 
-// UpdateProduct оновлює продукт, перевіряє що ціна > 0 (в копійках)
+// resolveAttributeFilters перевіряє фільтри за атрибутами за схемами всіх категорій і приводить значення до типів
+
+func (s *productService) resolveAttributeFilters(ctx context.Context, f *repositories.ProductFilter) error {
+	if len(f.Attributes) == 0 {
+		return nil
+	}
+	all, err := s.categories.List(ctx)
+	if err != nil {
+		return err
+	}
+	schemas := make([]models.AttributeSchema, 0, len(all))
+	for _, c := range all {
+		schemas = append(schemas, c.Attributes)
+	}
+	return resolveAttributeFilters(mergeSchemas(schemas), f.Attributes)
+}
+
+// checkAttributes перевіряє атрибути продукту за схемами його категорій та їхніх предків
+
+func (s *productService) checkAttributes(ctx context.Context, categories []models.Category, attrs models.Attributes) error {
+	if len(categories) == 0 && len(attrs) == 0 {
+		return nil
+	}
+	schemas, err := s.categories.AttributeSchemas(ctx, categoryIDs(categories))
+	if err != nil {
+		return err
+	}
+	return validateAttributes(mergeSchemas(schemas), attrs)
+}
+
+// categoryIDs повертає ID категорій
+
+func categoryIDs(categories []models.Category) []uint {
+	ids := make([]uint, len(categories))
+	for i, c := range categories {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+// validateProductFilter перевіряє параметри фільтра і нормалізує пошуковий запит
+
+func validateProductFilter(f *repositories.ProductFilter) error {
+	if !repositories.IsValidProductSort(f.Sort) {
+		return ErrInvalidFilter.WithDetail(fmt.Sprintf("unsupported sort %q", f.Sort))
+	}
+	if f.MinPriceCents != nil && f.MaxPriceCents != nil && *f.MinPriceCents > *f.MaxPriceCents {
+		return ErrInvalidFilter.WithDetail("min_price is greater than max_price")
+	}
+	f.Query = strings.TrimSpace(f.Query)
+	if f.Limit <= 0 || f.Limit > maxProductPage {
+		f.Limit = maxProductPage
+	}
+	return nil
+}
+
+// UpdateProduct оновлює продукт, перевіряє що ціна > 0 (в копійках), інші поля та атрибути за схемою категорій.
+// p.Version — версія, яку бачив клієнт (If-Match): якщо продукт відтоді змінився — ErrVersionMismatch.
+// Запис умовний за версією, тому паралельна зміна між читанням і записом не губиться.
 
 func (s *productService) UpdateProduct(ctx context.Context, p *models.Product) (*models.Product, error) {
-	if p.PriceCents <= 0 {
-		return nil, ErrInvalidPrice
+	if err := validateProduct(p); err != nil {
+		return nil, err
 	}
-	if err := s.repo.Update(ctx, p); err != nil {
+	existing, err := s.GetProduct(ctx, p.ID)
+	if err != nil {
 		return nil, err
 	}
+	expected := p.Version
+	if err := checkVersion(existing, expected); err != nil {
+		return nil, err
+	}
+	if err := s.checkAttributes(ctx, existing.Categories, p.Attributes); err != nil {
+		return nil, err
+	}
+	p.CreatedAt = existing.CreatedAt
+	p.Version = existing.Version
+	p.Categories = existing.Categories
+	p.Variants = existing.Variants
+	p.Images = existing.Images
+	p.ImageURL = existing.ImageURL // керується через /api/products/:id/images
+	p.Stock = existing.Stock       // змінюється лише рухами журналу складу (InventoryService)
+	p.Availability, p.SellableStock = existing.Availability, existing.SellableStock
+	p.RatingAvg, p.RatingCount = existing.RatingAvg, existing.RatingCount // рахуються зі схвалених відгуків
+	if err := s.repo.Update(ctx, p); err != nil {
+		return nil, translateVersionError(err, expected)
+	}
 	return p, nil
 }
 
-// DeleteProduct видаляє продукт за ID (повертає ErrNotFound якщо не знайдено)
+// productReadOnlyFields — поля, які не можна змінити через PATCH
+// (категорії — через PUT /api/products/:id/categories, варіанти — через /api/products/:id/variants,
+// зображення та image_url — через /api/products/:id/images, залишок і наявність у локаціях — рухами через /api/admin/products/:id/stock/movements, рейтинг — відгуками)
+
+var productReadOnlyFields = map[string]bool{"id": true, "created_at": true, "updated_at": true, "version": true, "categories": true, "variants": true, "images": true, "image_url": true, "stock": true, "availability": true, "sellable_stock": true, "rating_avg": true, "rating_count": true}
+
+// PatchProduct застосовує JSON Merge Patch до поточного стану продукту:
+// передані поля замінюються, null очищає поле, відсутні поля залишаються без змін.
+// Об'єднаний результат проходить ту ж валідацію, що й створення продукту.
+// attributes — вкладений об'єкт, тому {"attributes": {"grain_free": null}} видаляє лише один атрибут.
+
+func (s *productService) PatchProduct(ctx context.Context, id uint, patch []byte, version uint) (*models.Product, error) {
+	keys, err := mergepatch.Keys(patch)
+	if err != nil {
+		return nil, ErrInvalidPatch.WithDetail(err.Error())
+	}
+	for _, k := range keys {
+		if productReadOnlyFields[k] {
+			return nil, ErrInvalidPatch.WithDetail(fmt.Sprintf("field %q is read-only", k))
+		}
+	}
+
+	current, err := s.GetProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(current, version); err != nil {
+		return nil, err
+	}
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := mergepatch.Apply(doc, patch)
+	if err != nil {
+		return nil, ErrInvalidPatch.WithDetail(err.Error())
+	}
+
+	// Невідомі поля та неправильні типи (наприклад, рядок у price_cents) — помилка клієнта
+
+	var p models.Product
+	dec := json.NewDecoder(bytes.NewReader(merged))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return nil, ErrInvalidPatch.WithDetail(err.Error())
+	}
+	p.ID, p.CreatedAt, p.UpdatedAt, p.Version = current.ID, current.CreatedAt, current.UpdatedAt, current.Version
+
+	if err := validateProduct(&p); err != nil {
+		return nil, err
+	}
+	if err := s.checkAttributes(ctx, current.Categories, p.Attributes); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, &p); err != nil {
+		return nil, translateVersionError(err, version)
+	}
+	return &p, nil
+}
+
+// validateProduct перевіряє поля продукту (ті ж правила, що й binding у createProductRequest) і його варіанти
+
+func validateProduct(p *models.Product) error {
+	if p.PriceCents <= 0 {
+		return ErrInvalidPrice
+	}
+	if n := utf8.RuneCountInString(p.Name); n < 2 || n > 255 {
+		return ErrInvalidProduct.WithDetail("name must be 2-255 characters")
+	}
+	if utf8.RuneCountInString(p.Description) > 2000 {
+		return ErrInvalidProduct.WithDetail("description must be at most 2000 characters")
+	}
+	if p.Stock < 0 {
+		return ErrInvalidProduct.WithDetail("stock must be >= 0")
+	}
+	if p.ReorderPoint < 0 || p.ReorderQuantity < 0 {
+		return ErrInvalidProduct.WithDetail("reorder_point and reorder_quantity must be >= 0")
+	}
+	if utf8.RuneCountInString(p.SKU) > 100 {
+		return ErrInvalidProduct.WithDetail("sku must be at most 100 characters")
+	}
+	return validateVariants(p.Variants)
+}
+
+// DeleteProduct видаляє продукт за ID (повертає ErrNotFound якщо не знайдено).
+// Якщо version > 0, видаляє лише цю версію: застаріла версія — ErrVersionMismatch.
 
-func (s *productService) DeleteProduct(ctx context.Context, id uint) error {
-	return s.repo.Delete(ctx, id)
+func (s *productService) DeleteProduct(ctx context.Context, id uint, version uint) error {
+	if version > 0 {
+		current, err := s.GetProduct(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(current, version); err != nil {
+			return err
+		}
+	}
+	return translateVersionError(s.repo.Delete(ctx, id, version), version)
+}
+
+// checkVersion перевіряє очікувану версію продукту (0 — будь-яка)
+
+func checkVersion(p *models.Product, expected uint) error {
+	if expected != 0 && p.Version != expected {
+		return ErrVersionMismatch.WithDetail(fmt.Sprintf("current version is %d", p.Version))
+	}
+	return nil
+}
+
+// translateVersionError — як translateProductError, але конфлікт версій під час запису
+// стає ErrVersionMismatch, якщо клієнт передав очікувану версію, і ErrEditConflict — якщо ні
+
+func translateVersionError(err error, expected uint) error {
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		if expected != 0 {
+			return ErrVersionMismatch
+		}
+		return ErrEditConflict
+	}
+	return translateProductError(err)
+}
+
+// translateProductError переводить помилки GORM у помилки домену продуктів
+
+func translateProductError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicateSKU
+	}
+	return err
 }
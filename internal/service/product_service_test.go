@@ -2,12 +2,18 @@ package services_test
 
 import (
 	"context"
+	"errors"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/pagination"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
 	"github.com/AlexRijikov/go-petshop-api/internal/service"
+	"gorm.io/gorm"
 )
 
 // Тестуємо бізнес-логіку без БД, використовуючи in-memory репозиторій.
@@ -15,8 +21,10 @@ import (
 // Простий in-memory repo реалізує repositories.ProductRepository для тестів.
 
 type memRepo struct {
-	data map[uint]*models.Product
-	next uint
+	data      map[uint]*models.Product
+	next      uint
+	rejectSKU map[string]error // артикул → помилка залишку, яку поверне UpsertBySKU (імітує основний склад)
+	upsertErr error            // помилка запису всієї пачки в UpsertBySKU
 }
 
 // newMemRepo створює новий in-memory репозиторій
@@ -29,6 +37,7 @@ func newMemRepo() *memRepo {
 
 func (m *memRepo) Create(ctx context.Context, p *models.Product) error {
 	p.ID = m.next
+	p.Version = 1
 	m.next++
 	m.data[p.ID] = p
 	return nil
@@ -41,38 +50,138 @@ func (m *memRepo) GetByID(ctx context.Context, id uint) (*models.Product, error)
 	if !ok {
 		return nil, nil
 	}
+	p.SellableStock = p.Stock // складів у пам'яті немає — весь залишок доступний
 	return p, nil
 }
 
 // List повертає всі продукти (без реальної пагінації для простоти)
 
-func (m *memRepo) List(ctx context.Context, limit, offset int) ([]models.Product, int64, error) {
+func (m *memRepo) List(ctx context.Context, f repositories.ProductFilter) ([]models.Product, int64, error) {
 	var out []models.Product
 	for _, v := range m.data {
-		out = append(out, *v)
+		p := *v
+		p.SellableStock = p.Stock // складів у пам'яті немає — весь залишок доступний
+		out = append(out, p)
 	}
 	return out, int64(len(out)), nil
 }
 
-// Update оновлює продукт в пам'яті (перезаписує по ID)
+// ListByCursor повертає сторінку продуктів за id (інші сортування в пам'яті не підтримуються)
+
+func (m *memRepo) ListByCursor(ctx context.Context, f repositories.ProductFilter) (*pagination.Page[models.Product], error) {
+	c := f.Cursor
+	if c != nil && c.Sort != f.Sort {
+		return nil, pagination.ErrInvalidCursor
+	}
+	var out []models.Product
+	for _, v := range m.data {
+		if c == nil || (!c.Prev && v.ID > c.ID) || (c.Prev && v.ID < c.ID) {
+			p := *v
+			p.SellableStock = p.Stock
+			out = append(out, p)
+		}
+	}
+	backward := c != nil && c.Prev
+	sort.Slice(out, func(i, j int) bool { return (out[i].ID < out[j].ID) != backward })
+	if len(out) > f.Limit+1 {
+		out = out[:f.Limit+1]
+	}
+	return pagination.BuildPage(out, c, f.Limit, func(p models.Product) pagination.Cursor {
+		return pagination.Cursor{Sort: f.Sort, ID: p.ID}
+	}), nil
+}
+
+// Update оновлює продукт в пам'яті (перезаписує по ID, якщо версія збігається — як у productRepo)
 
 func (m *memRepo) Update(ctx context.Context, p *models.Product) error {
-	m.data[p.ID] = p
+	cur, ok := m.data[p.ID]
+	if !ok || cur.Version != p.Version {
+		return repositories.ErrVersionConflict
+	}
+	p.Version++
+	cp := *p
+	m.data[p.ID] = &cp
 	return nil
 }
 
-// Delete видаляє продукт з пам'яті
+// Delete видаляє продукт з пам'яті (з перевіркою версії, як у productRepo)
 
-func (m *memRepo) Delete(ctx context.Context, id uint) error {
+func (m *memRepo) Delete(ctx context.Context, id uint, version uint) error {
+	cur, ok := m.data[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if version > 0 && cur.Version != version {
+		return repositories.ErrVersionConflict
+	}
 	delete(m.data, id)
 	return nil
 }
 
+func (m *memRepo) FindBySKUs(ctx context.Context, skus []string) ([]models.Product, error) {
+	var out []models.Product
+	for _, p := range m.data {
+		for _, sku := range skus {
+			if p.SKU == sku {
+				out = append(out, *p)
+			}
+		}
+	}
+	return out, nil
+}
+
+// UpsertBySKU оновлює продукт з тим самим артикулом (версія + 1) або додає новий;
+// артикули з rejectSKU не записуються і повертаються як відхилені
+
+func (m *memRepo) UpsertBySKU(ctx context.Context, products []models.Product) (map[string]error, error) {
+	if m.upsertErr != nil {
+		return nil, m.upsertErr
+	}
+	rejected := map[string]error{}
+	for i := range products {
+		p := products[i]
+		if err := m.rejectSKU[p.SKU]; err != nil {
+			rejected[p.SKU] = err
+			continue
+		}
+		for _, cur := range m.data {
+			if cur.SKU == p.SKU {
+				p.ID, p.Version, p.CreatedAt = cur.ID, cur.Version+1, cur.CreatedAt
+				p.Categories, p.Variants = cur.Categories, cur.Variants
+			}
+		}
+		if p.ID == 0 {
+			p.ID = m.next
+			m.next++
+		}
+		m.data[p.ID] = &p
+	}
+	return rejected, nil
+}
+
+// Each передає продукти пачками у порядку id
+
+func (m *memRepo) Each(ctx context.Context, batchSize int, fn func([]models.Product) error) error {
+	var batch []models.Product
+	for id := uint(1); id < m.next; id++ {
+		if p, ok := m.data[id]; ok {
+			batch = append(batch, *p)
+		}
+		if len(batch) == batchSize || (id == m.next-1 && len(batch) > 0) {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = nil
+		}
+	}
+	return nil
+}
+
 // Тести для ProductService
 
 func TestCreateProduct(t *testing.T) {
 	repo := newMemRepo()
-	svc := services.NewProductService(repo)
+	svc := services.NewProductService(repo, newMemCategoryRepo())
 
 	p := &models.Product{
 		Name:       "Test",
@@ -89,7 +198,7 @@ func TestCreateProduct(t *testing.T) {
 
 func TestCreateInvalidPrice(t *testing.T) {
 	repo := newMemRepo()
-	svc := services.NewProductService(repo)
+	svc := services.NewProductService(repo, newMemCategoryRepo())
 
 	p := &models.Product{
 		Name:       "Bad",
@@ -99,3 +208,225 @@ func TestCreateInvalidPrice(t *testing.T) {
 	_, err := svc.CreateProduct(context.Background(), p)
 	assert.Error(t, err)
 }
+
+// Тест оновлення неіснуючого продукту — ErrNotFound замість створення нового рядка
+
+func TestUpdateProductNotFound(t *testing.T) {
+	repo := newMemRepo()
+	svc := services.NewProductService(repo, newMemCategoryRepo())
+
+	_, err := svc.UpdateProduct(context.Background(), &models.Product{ID: 42, Name: "Ghost", PriceCents: 100})
+	assert.ErrorIs(t, err, services.ErrNotFound)
+	assert.Empty(t, repo.data)
+}
+
+// Тест: помилка бази при читанні продукту не маскується під ErrNotFound
+
+func TestGetProductRepositoryError(t *testing.T) {
+	svc := services.NewProductService(failingRepo{newMemRepo()}, newMemCategoryRepo())
+
+	_, err := svc.GetProduct(context.Background(), 1)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, services.ErrNotFound)
+}
+
+// failingRepo імітує недоступну базу даних для читання
+
+type failingRepo struct{ *memRepo }
+
+func (failingRepo) GetByID(ctx context.Context, id uint) (*models.Product, error) {
+	return nil, errors.New("connection refused")
+}
+
+// Тест некоректних параметрів фільтрації
+
+func TestListProductsInvalidFilter(t *testing.T) {
+	repo := newMemRepo()
+	svc := services.NewProductService(repo, newMemCategoryRepo())
+
+	_, _, err := svc.ListProducts(context.Background(), repositories.ProductFilter{Sort: "stock; DROP TABLE products"})
+	assert.ErrorIs(t, err, services.ErrInvalidFilter)
+
+	minPrice, maxPrice := int64(500), int64(100)
+	_, _, err = svc.ListProducts(context.Background(), repositories.ProductFilter{MinPriceCents: &minPrice, MaxPriceCents: &maxPrice})
+	assert.ErrorIs(t, err, services.ErrInvalidFilter)
+
+	_, _, err = svc.ListProducts(context.Background(), repositories.ProductFilter{Sort: "-price"})
+	assert.NoError(t, err)
+}
+
+// Тест: limit сторінки каталогу обмежений, щоб один запит не вантажив увесь каталог
+
+func TestListProductsPageLimit(t *testing.T) {
+	svc := services.NewProductService(newMemRepo(), newMemCategoryRepo())
+
+	page, err := svc.ListProductsPage(context.Background(), repositories.ProductFilter{Limit: 1000000})
+	require.NoError(t, err)
+	assert.Equal(t, 100, page.Limit)
+}
+
+// Тест режиму курсорів: вперед до кінця і назад за prev_cursor
+
+func TestListProductsPage(t *testing.T) {
+	repo := newMemRepo()
+	svc := services.NewProductService(repo, newMemCategoryRepo())
+	for i := 0; i < 5; i++ {
+		_, err := svc.CreateProduct(context.Background(), &models.Product{Name: "Product", PriceCents: 100})
+		require.NoError(t, err)
+	}
+
+	page, err := svc.ListProductsPage(context.Background(), repositories.ProductFilter{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, productIDs(page.Items))
+	assert.Empty(t, page.PrevCursor)
+	require.NotEmpty(t, page.NextCursor)
+
+	next, err := pagination.Decode(page.NextCursor)
+	require.NoError(t, err)
+	page, err = svc.ListProductsPage(context.Background(), repositories.ProductFilter{Limit: 2, Cursor: next})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3, 4}, productIDs(page.Items))
+	require.NotEmpty(t, page.PrevCursor)
+
+	next, err = pagination.Decode(page.NextCursor)
+	require.NoError(t, err)
+	page, err = svc.ListProductsPage(context.Background(), repositories.ProductFilter{Limit: 2, Cursor: next})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{5}, productIDs(page.Items))
+	assert.Empty(t, page.NextCursor)
+
+	prev, err := pagination.Decode(page.PrevCursor)
+	require.NoError(t, err)
+	page, err = svc.ListProductsPage(context.Background(), repositories.ProductFilter{Limit: 2, Cursor: prev})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3, 4}, productIDs(page.Items))
+	assert.NotEmpty(t, page.NextCursor)
+	assert.NotEmpty(t, page.PrevCursor)
+}
+
+// Тест: курсор, виданий для іншого сортування, — ErrInvalidFilter
+
+func TestListProductsPageCursorSortMismatch(t *testing.T) {
+	svc := services.NewProductService(newMemRepo(), newMemCategoryRepo())
+
+	c := &pagination.Cursor{Sort: "price", Key: "100", ID: 1}
+	_, err := svc.ListProductsPage(context.Background(), repositories.ProductFilter{Sort: "-price", Limit: 2, Cursor: c})
+	assert.ErrorIs(t, err, services.ErrInvalidFilter)
+}
+
+// productIDs повертає ID продуктів у порядку сторінки
+
+func productIDs(items []models.Product) []uint {
+	ids := make([]uint, 0, len(items))
+	for _, p := range items {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+// Тест PATCH: змінюються лише передані поля, null очищає поле
+
+func TestPatchProduct(t *testing.T) {
+	repo := newMemRepo()
+	svc := services.NewProductService(repo, newMemCategoryRepo())
+	created, err := svc.CreateProduct(context.Background(), &models.Product{
+		Name: "Корм для котів", Description: "1 кг", PriceCents: 25000, Stock: 5,
+		Category: "food", ImageURL: "/img/1.png",
+	})
+	require.NoError(t, err)
+
+	patched, err := svc.PatchProduct(context.Background(), created.ID, []byte(`{"price_cents": 19900, "description": null}`), 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(19900), patched.PriceCents)
+	assert.Empty(t, patched.Description)
+	assert.Equal(t, "Корм для котів", patched.Name)
+	assert.Equal(t, "food", patched.Category)
+	assert.Equal(t, "/img/1.png", patched.ImageURL)
+	assert.Equal(t, 5, repo.data[created.ID].Stock)
+}
+
+// Тест PATCH: об'єднаний результат валідується, службові та невідомі поля відхиляються
+
+func TestPatchProductInvalid(t *testing.T) {
+	repo := newMemRepo()
+	svc := services.NewProductService(repo, newMemCategoryRepo())
+	created, err := svc.CreateProduct(context.Background(), &models.Product{Name: "Миска", PriceCents: 5000})
+	require.NoError(t, err)
+
+	cases := map[string]error{
+		`{"price_cents": 0}`:       services.ErrInvalidPrice,
+		`{"name": null}`:           services.ErrInvalidProduct,
+		`{"name": "М"}`:            services.ErrInvalidProduct,
+		`{"stock": 3}`:             services.ErrInvalidPatch,
+		`{"id": 99}`:               services.ErrInvalidPatch,
+		`{"colour": "red"}`:        services.ErrInvalidPatch,
+		`{"price_cents": "cheap"}`: services.ErrInvalidPatch,
+		`["name"]`:                 services.ErrInvalidPatch,
+	}
+	for patch, want := range cases {
+		_, err := svc.PatchProduct(context.Background(), created.ID, []byte(patch), 0)
+		assert.ErrorIs(t, err, want, patch)
+	}
+	assert.Equal(t, int64(5000), repo.data[created.ID].PriceCents)
+
+	_, err = svc.PatchProduct(context.Background(), 42, []byte(`{"name": "Миска"}`), 0)
+	assert.ErrorIs(t, err, services.ErrNotFound)
+}
+
+// Тест оптимістичного блокування: кожна зміна збільшує версію, застаріла версія відхиляється
+
+func TestProductVersionMismatch(t *testing.T) {
+	repo := newMemRepo()
+	svc := services.NewProductService(repo, newMemCategoryRepo())
+	created, err := svc.CreateProduct(context.Background(), &models.Product{Name: "Нашийник", PriceCents: 3000})
+	require.NoError(t, err)
+	require.Equal(t, uint(1), created.Version)
+
+	updated, err := svc.UpdateProduct(context.Background(), &models.Product{ID: created.ID, Name: "Нашийник", PriceCents: 3500, Version: 1})
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), updated.Version)
+
+	// Другий адміністратор редагував версію 1 — його зміна не перезаписує першу
+	_, err = svc.UpdateProduct(context.Background(), &models.Product{ID: created.ID, Name: "Нашийник", PriceCents: 2000, Version: 1})
+	assert.ErrorIs(t, err, services.ErrVersionMismatch)
+	_, err = svc.PatchProduct(context.Background(), created.ID, []byte(`{"price_cents": 4000}`), 1)
+	assert.ErrorIs(t, err, services.ErrVersionMismatch)
+	assert.ErrorIs(t, svc.DeleteProduct(context.Background(), created.ID, 1), services.ErrVersionMismatch)
+	assert.Equal(t, int64(3500), repo.data[created.ID].PriceCents)
+
+	patched, err := svc.PatchProduct(context.Background(), created.ID, []byte(`{"price_cents": 4000}`), 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), patched.Version)
+	assert.NoError(t, svc.DeleteProduct(context.Background(), created.ID, 3))
+}
+
+// staleRepo повертає з GetByID копію продукту зі старою версією — імітує паралельну зміну між читанням і записом
+
+type staleRepo struct {
+	*memRepo
+}
+
+func (r staleRepo) GetByID(ctx context.Context, id uint) (*models.Product, error) {
+	p, err := r.memRepo.GetByID(ctx, id)
+	if p == nil || err != nil {
+		return p, err
+	}
+	cp := *p
+	cp.Version--
+	return &cp, nil
+}
+
+// Тест: паралельна зміна без If-Match дає ErrEditConflict, з If-Match — ErrVersionMismatch
+
+func TestUpdateProductConcurrentEdit(t *testing.T) {
+	repo := newMemRepo()
+	require.NoError(t, repo.Create(context.Background(), &models.Product{Name: "Лежанка", PriceCents: 90000}))
+	repo.data[1].Version = 2
+	svc := services.NewProductService(staleRepo{repo}, newMemCategoryRepo())
+
+	_, err := svc.UpdateProduct(context.Background(), &models.Product{ID: 1, Name: "Лежанка", PriceCents: 80000})
+	assert.ErrorIs(t, err, services.ErrEditConflict)
+	_, err = svc.PatchProduct(context.Background(), 1, []byte(`{"price_cents": 80000}`), 1)
+	assert.ErrorIs(t, err, services.ErrVersionMismatch)
+	assert.Equal(t, int64(90000), repo.data[1].PriceCents)
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/recommend"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+)
+
+// Обмеження рекомендацій
+
+const (
+	maxRecommendations     = 50                   // продуктів в одній відповіді
+	maxRankedCandidates    = 1000                 // кандидатів з моделі, серед яких шукаємо limit продуктів у наявності
+	recommendationLookback = 180 * 24 * time.Hour // за який період враховуються спільні покупки
+)
+
+// Recommendation — рекомендований продукт з оцінкою і причинами (recommend.Reason*)
+
+type Recommendation struct {
+	Product models.Product `json:"product"`
+	Score   float64        `json:"score"`
+	Reasons []string       `json:"reasons"`
+}
+
+// RecommendationService визначає персональний підбір товарів
+
+type RecommendationService interface {
+	Recommend(ctx context.Context, userID uint, limit int) ([]Recommendation, error) // найкращі продукти для користувача за тваринами та історією покупок
+}
+
+// Recommender реалізує RecommendationService: модель (каталог і спільні покупки) тримається в пам'яті
+// і оновлюється у фоні (Start), а тварини та покупки користувача читаються при кожному запиті
+
+type Recommender struct {
+	repo     repositories.RecommendationRepository
+	pets     repositories.PetRepository
+	products repositories.ProductRepository
+	model    atomic.Pointer[recommend.Model]
+	mu       sync.Mutex // одне оновлення моделі одночасно (і одна побудова при першому запиті)
+}
+
+// NewRecommender створює новий Recommender; модель будується першим Refresh
+
+func NewRecommender(repo repositories.RecommendationRepository, pets repositories.PetRepository, products repositories.ProductRepository) *Recommender {
+	return &Recommender{repo: repo, pets: pets, products: products}
+}
+
+// Refresh перебудовує модель з поточного каталогу і замовлень за recommendationLookback.
+// Запити під час оновлення обслуговує попередня модель.
+
+func (r *Recommender) Refresh(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.refresh(ctx)
+}
+
+// refresh будує і публікує модель; викликається під mu
+
+func (r *Recommender) refresh(ctx context.Context) error {
+	catalog, err := r.repo.Catalog(ctx)
+	if err != nil {
+		return err
+	}
+	baskets, err := r.repo.Baskets(ctx, time.Now().Add(-recommendationLookback))
+	if err != nil {
+		return err
+	}
+	items := make([]recommend.Item, len(catalog))
+	for i, p := range catalog {
+		items[i] = recommend.ItemFromProduct(p)
+	}
+	r.model.Store(recommend.Build(items, baskets))
+	return nil
+}
+
+// Start будує модель одразу і далі кожні interval у фоновій горутині до скасування ctx.
+// Без фонового оновлення модель не бачила б нових продуктів і замовлень, тому config.Load
+// вимагає RECOMMENDATIONS_REFRESH_INTERVAL > 0; interval <= 0 тут нічого не запускає
+
+func (r *Recommender) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := r.Refresh(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Помилка оновлення моделі рекомендацій: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Recommend ранжує продукти за профілями тварин і покупками користувача.
+// Продукти в відповіді — актуальні (ціна, залишок), а не зі знімка моделі. Модель ранжує з запасом,
+// а продукти підвантажуються пачками по limit, доки відповідь не заповниться, — розпродані після
+// побудови моделі продукти не скорочують відповідь.
+
+func (r *Recommender) Recommend(ctx context.Context, userID uint, limit int) ([]Recommendation, error) {
+	if limit <= 0 || limit > maxRecommendations {
+		limit = maxRecommendations
+	}
+	model, err := r.loadModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pets, err := r.pets.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	purchased, err := r.repo.PurchasedBy(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	profile := recommend.Profile{Purchased: purchased}
+	for _, p := range pets {
+		profile.Pets = append(profile.Pets, recommend.Pet{
+			Species: p.Species, Stage: recommend.StageAt(p.BirthDate, now), Allergies: p.Allergies,
+		})
+	}
+
+	scored := model.Rank(profile, maxRankedCandidates)
+	out := make([]Recommendation, 0, min(limit, len(scored)))
+	for start := 0; start < len(scored) && len(out) < limit; start += limit {
+		batch := scored[start:min(start+limit, len(scored))]
+		ids := make([]uint, len(batch))
+		for i, s := range batch {
+			ids[i] = s.ProductID
+		}
+		products, _, err := r.products.List(ctx, repositories.ProductFilter{IDs: ids, Limit: len(ids), SkipTotal: true})
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[uint]models.Product, len(products))
+		for _, p := range products {
+			byID[p.ID] = p
+		}
+
+		// Продукт, видалений або розпроданий після побудови моделі, пропускається
+
+		for _, s := range batch {
+			p, ok := byID[s.ProductID]
+			if !ok || p.SellableStock <= 0 {
+				continue
+			}
+			reasons := s.Reasons
+			if reasons == nil {
+				reasons = []string{}
+			}
+			out = append(out, Recommendation{Product: p, Score: s.Score, Reasons: reasons})
+			if len(out) == limit {
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+// loadModel повертає поточну модель; якщо її ще немає (перший запит до завершення Start),
+// будує її один раз — конкурентні запити чекають на mu і беруть уже побудовану модель
+
+func (r *Recommender) loadModel(ctx context.Context) (*recommend.Model, error) {
+	if model := r.model.Load(); model != nil {
+		return model, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if model := r.model.Load(); model != nil {
+		return model, nil
+	}
+	if err := r.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return r.model.Load(), nil
+}
@@ -0,0 +1,128 @@
+package services_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/recommend"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// memRecommendationRepo — каталог з memRepo і задані кошики та покупки
+
+type memRecommendationRepo struct {
+	products  *memRepo
+	baskets   [][]uint
+	purchased map[uint][]uint
+	refreshes int
+	delay     time.Duration // імітує повільне читання каталогу
+}
+
+func (m *memRecommendationRepo) Catalog(ctx context.Context) ([]models.Product, error) {
+	m.refreshes++
+	time.Sleep(m.delay)
+	var out []models.Product
+	for id := uint(1); id < m.products.next; id++ {
+		if p, ok := m.products.data[id]; ok {
+			item := *p
+			item.SellableStock = item.Stock
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+func (m *memRecommendationRepo) Baskets(ctx context.Context, since time.Time) ([][]uint, error) {
+	return m.baskets, nil
+}
+
+func (m *memRecommendationRepo) PurchasedBy(ctx context.Context, userID uint) ([]uint, error) {
+	return m.purchased[userID], nil
+}
+
+// Тест рекомендацій: модель будується при першому запиті, тварини й покупки впливають на порядок,
+// а продукт, розпроданий після побудови моделі, пропускається без скорочення відповіді
+
+func TestRecommend(t *testing.T) {
+	ctx := context.Background()
+	products := newMemRepo()
+	for _, p := range []models.Product{
+		{Name: "Корм для кошенят", PriceCents: 20000, Stock: 5, Attributes: models.Attributes{"species": "cat", "life_stage": "kitten"}},
+		{Name: "Корм з куркою", PriceCents: 18000, Stock: 5, Attributes: models.Attributes{"species": "cat", "allergens": "chicken"}},
+		{Name: "Кістка для собак", PriceCents: 5000, Stock: 5, Attributes: models.Attributes{"species": "dog"}},
+		{Name: "Миска", PriceCents: 9000, Stock: 5},
+		{Name: "Лежанка", PriceCents: 90000, Stock: 5},
+	} {
+		require.NoError(t, products.Create(ctx, &p))
+	}
+	repo := &memRecommendationRepo{
+		products:  products,
+		baskets:   [][]uint{{4, 5}, {4, 5}, {1, 4}},
+		purchased: map[uint][]uint{7: {4}},
+	}
+	pets := newMemPetRepo()
+	born := time.Now().AddDate(0, -4, 0)
+	require.NoError(t, pets.Create(ctx, &models.Pet{UserID: 7, Name: "Мурка", Species: "cat", BirthDate: &born, Allergies: models.StringList{"chicken"}}))
+	svc := services.NewRecommender(repo, pets, products)
+
+	items, err := svc.Recommend(ctx, 7, 10)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "Корм для кошенят", items[0].Product.Name)
+	assert.Equal(t, []string{recommend.ReasonSpecies, recommend.ReasonLifeStage, recommend.ReasonBoughtTogether}, items[0].Reasons)
+	assert.Equal(t, "Лежанка", items[1].Product.Name)
+	assert.Equal(t, 1, repo.refreshes)
+
+	products.data[1].Stock = 0
+	items, err = svc.Recommend(ctx, 7, 10)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Лежанка", items[0].Product.Name)
+	assert.Equal(t, 1, repo.refreshes, "модель не перебудовується на кожен запит")
+
+	// Розпроданий продукт не скорочує відповідь: його місце займає наступний за оцінкою
+
+	items, err = svc.Recommend(ctx, 7, 1)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Лежанка", items[0].Product.Name)
+
+	// Новий користувач без тварин і покупок отримує популярні товари
+
+	items, err = svc.Recommend(ctx, 8, 1)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Миска", items[0].Product.Name)
+	assert.Equal(t, []string{recommend.ReasonPopular}, items[0].Reasons)
+}
+
+// Тест: конкурентні перші запити будують модель лише один раз
+
+func TestRecommendBuildsModelOnce(t *testing.T) {
+	ctx := context.Background()
+	products := newMemRepo()
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Миска", PriceCents: 9000, Stock: 5}))
+	repo := &memRecommendationRepo{products: products, baskets: [][]uint{{1}}, delay: 50 * time.Millisecond}
+	svc := services.NewRecommender(repo, newMemPetRepo(), products)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = svc.Recommend(ctx, 7, 10)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, repo.refreshes)
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Помилки сервісу відгуків
+
+var (
+	ErrReviewNotFound    = apperr.NotFound("review_not_found", "review not found")                            // відгук не знайдено
+	ErrInvalidReview     = apperr.Validation("invalid_review", "invalid review")                              // оцінка поза 1–5 або некоректний текст
+	ErrReviewExists      = apperr.Conflict("review_already_exists", "you have already reviewed this product") // один відгук на користувача і продукт
+	ErrInvalidModeration = apperr.Validation("invalid_moderation", "invalid moderation decision")             // стан модерації має бути approved або rejected
+	ErrReviewModified    = apperr.Conflict("review_modified", "review was changed after it was viewed")       // автор змінив відгук, поки модератор його переглядав
+)
+
+// Обмеження відгуків
+
+const (
+	maxReviewText     = 2000 // символів у тексті відгуку
+	maxModerationNote = 500  // символів у коментарі модератора
+	maxReviewPage     = 100  // відгуків на сторінку
+)
+
+// ReviewInput — оцінка і текст відгуку від покупця
+
+type ReviewInput struct {
+	Rating int    `json:"rating" binding:"required"`
+	Text   string `json:"text" binding:"required"`
+}
+
+// ModerationInput — рішення модератора щодо відгуку
+
+type ModerationInput struct {
+	Status    string    `json:"status" binding:"required"` // approved або rejected
+	Note      string    `json:"note"`                      // причина відхилення (видно автору)
+	UpdatedAt time.Time `json:"updated_at"`                // updated_at відгуку, який бачив модератор (обов'язково)
+}
+
+// ReviewService визначає бізнес-логіку відгуків: покупці пишуть, адміністратори модерують,
+// публікуються і враховуються в рейтингу продукту лише схвалені відгуки
+
+type ReviewService interface {
+	ListProductReviews(ctx context.Context, productID uint, f repositories.ReviewFilter) ([]models.Review, int64, error) // схвалені відгуки продукту; returns items, totalCount
+	CreateReview(ctx context.Context, userID, productID uint, in ReviewInput) (*models.Review, error)                    // ErrNotFound, ErrInvalidReview, ErrReviewExists
+	GetMyReview(ctx context.Context, userID, productID uint) (*models.Review, error)                                     // ErrReviewNotFound якщо користувач ще не писав відгук
+	UpdateMyReview(ctx context.Context, userID, productID uint, in ReviewInput) (*models.Review, error)                  // змінений відгук знову чекає модерації
+	DeleteMyReview(ctx context.Context, userID, productID uint) error                                                    // ErrReviewNotFound якщо відгуку немає
+	ListReviews(ctx context.Context, f repositories.ReviewFilter) ([]models.Review, int64, error)                        // усі відгуки для модерації (фільтр за станом)
+	ModerateReview(ctx context.Context, id uint, in ModerationInput, moderatorID uint) (*models.Review, error)           // ErrReviewNotFound, ErrInvalidModeration, ErrReviewModified
+}
+
+// reviewService реалізує ReviewService
+
+type reviewService struct {
+	repo     repositories.ReviewRepository
+	products repositories.ProductRepository
+}
+
+// NewReviewService створює новий ReviewService
+
+func NewReviewService(r repositories.ReviewRepository, products repositories.ProductRepository) ReviewService {
+	return &reviewService{repo: r, products: products}
+}
+
+// ListProductReviews повертає опубліковані відгуки продукту
+
+func (s *reviewService) ListProductReviews(ctx context.Context, productID uint, f repositories.ReviewFilter) ([]models.Review, int64, error) {
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return nil, 0, err
+	}
+	f.ProductID = productID
+	f.Status = models.ReviewApproved
+	return s.ListReviews(ctx, f)
+}
+
+// CreateReview додає відгук поточного користувача. Позначка verified_purchase ставиться,
+// якщо користувач має оплачене замовлення з цим продуктом; відгук чекає модерації.
+
+func (s *reviewService) CreateReview(ctx context.Context, userID, productID uint, in ReviewInput) (*models.Review, error) {
+	text, err := validateReview(in)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	verified, err := s.repo.HasPurchased(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	rv := &models.Review{
+		ProductID: productID, UserID: userID, Rating: in.Rating, Text: text,
+		VerifiedPurchase: verified, Status: models.ReviewPending,
+	}
+	if err := s.repo.Create(ctx, rv); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrReviewExists
+		}
+		return nil, err
+	}
+	return s.GetMyReview(ctx, userID, productID)
+}
+
+// GetMyReview повертає відгук поточного користувача про продукт (у будь-якому стані модерації)
+
+func (s *reviewService) GetMyReview(ctx context.Context, userID, productID uint) (*models.Review, error) {
+	rv, err := s.repo.GetByUserProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, translateReviewError(err)
+	}
+	return rv, nil
+}
+
+// UpdateMyReview змінює оцінку і текст; відгук знімається з публікації до повторної модерації
+
+func (s *reviewService) UpdateMyReview(ctx context.Context, userID, productID uint, in ReviewInput) (*models.Review, error) {
+	text, err := validateReview(in)
+	if err != nil {
+		return nil, err
+	}
+	rv, err := s.GetMyReview(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	verified, err := s.repo.HasPurchased(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	rv.Rating, rv.Text, rv.VerifiedPurchase = in.Rating, text, verified
+	rv.Status, rv.ModerationNote, rv.ModeratedBy, rv.ModeratedAt = models.ReviewPending, "", nil, nil
+	if err := s.repo.Update(ctx, rv); err != nil {
+		return nil, translateReviewError(err)
+	}
+	return rv, nil
+}
+
+// DeleteMyReview видаляє відгук поточного користувача про продукт
+
+func (s *reviewService) DeleteMyReview(ctx context.Context, userID, productID uint) error {
+	rv, err := s.GetMyReview(ctx, userID, productID)
+	if err != nil {
+		return err
+	}
+	return translateReviewError(s.repo.Delete(ctx, rv.ID))
+}
+
+// ListReviews повертає відгуки з фільтрами (для модерації — будь-які стани)
+
+func (s *reviewService) ListReviews(ctx context.Context, f repositories.ReviewFilter) ([]models.Review, int64, error) {
+	if !repositories.IsValidReviewSort(f.Sort) {
+		return nil, 0, ErrInvalidFilter.WithDetail(fmt.Sprintf("unsupported sort %q", f.Sort))
+	}
+	switch f.Status {
+	case "", models.ReviewPending, models.ReviewApproved, models.ReviewRejected:
+	default:
+		return nil, 0, ErrInvalidFilter.WithDetail(fmt.Sprintf("unknown status %q", f.Status))
+	}
+	if f.Limit <= 0 || f.Limit > maxReviewPage {
+		f.Limit = maxReviewPage
+	}
+	return s.repo.List(ctx, f)
+}
+
+// ModerateReview схвалює або відхиляє відгук у тій версії, яку бачив модератор (in.UpdatedAt):
+// якщо автор тим часом змінив відгук, повертає ErrReviewModified. Рейтинг продукту перераховується в репозиторії.
+
+func (s *reviewService) ModerateReview(ctx context.Context, id uint, in ModerationInput, moderatorID uint) (*models.Review, error) {
+	if in.Status != models.ReviewApproved && in.Status != models.ReviewRejected {
+		return nil, ErrInvalidModeration.WithDetail("status must be approved or rejected")
+	}
+	if in.UpdatedAt.IsZero() {
+		return nil, ErrInvalidModeration.WithDetail("updated_at of the reviewed version is required")
+	}
+	note := strings.TrimSpace(in.Note)
+	if utf8.RuneCountInString(note) > maxModerationNote {
+		return nil, ErrInvalidModeration.WithDetail(fmt.Sprintf("note must be at most %d characters", maxModerationNote))
+	}
+	now := time.Now()
+	rv := &models.Review{ID: id, Status: in.Status, ModerationNote: note, ModeratedBy: &moderatorID, ModeratedAt: &now}
+	if err := s.repo.Moderate(ctx, rv, in.UpdatedAt); err != nil {
+		return nil, translateReviewError(err)
+	}
+	return rv, nil
+}
+
+// checkProduct повертає ErrNotFound, якщо продукту немає
+
+func (s *reviewService) checkProduct(ctx context.Context, productID uint) error {
+	p, err := s.products.GetByID(ctx, productID)
+	if err != nil {
+		return translateProductError(err)
+	}
+	if p == nil {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// validateReview перевіряє оцінку і текст відгуку; повертає текст без пробілів по краях
+
+func validateReview(in ReviewInput) (string, error) {
+	text := strings.TrimSpace(in.Text)
+	switch {
+	case in.Rating < 1 || in.Rating > 5:
+		return "", ErrInvalidReview.WithDetail("rating must be between 1 and 5")
+	case text == "":
+		return "", ErrInvalidReview.WithDetail("text is required")
+	case utf8.RuneCountInString(text) > maxReviewText:
+		return "", ErrInvalidReview.WithDetail(fmt.Sprintf("text must be at most %d characters", maxReviewText))
+	}
+	return text, nil
+}
+
+// translateReviewError перетворює помилки БД на помилки сервісу відгуків
+
+func translateReviewError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrReviewNotFound
+	case errors.Is(err, repositories.ErrReviewChanged):
+		return ErrReviewModified
+	}
+	return err
+}
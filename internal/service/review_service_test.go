@@ -0,0 +1,253 @@
+package services_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// memReviewRepo — in-memory відгуки; як і reviewRepo, перераховує рейтинг продукту в memRepo
+
+type memReviewRepo struct {
+	products  *memRepo
+	data      map[uint]*models.Review
+	next      uint
+	purchased map[[2]uint]bool // [користувач, продукт]
+}
+
+func newMemReviewRepo(products *memRepo) *memReviewRepo {
+	return &memReviewRepo{products: products, data: map[uint]*models.Review{}, next: 1, purchased: map[[2]uint]bool{}}
+}
+
+func (m *memReviewRepo) Create(ctx context.Context, rv *models.Review) error {
+	if _, err := m.GetByUserProduct(ctx, rv.UserID, rv.ProductID); err == nil {
+		return gorm.ErrDuplicatedKey
+	}
+	rv.ID = m.next
+	m.next++
+	rv.CreatedAt, rv.UpdatedAt = time.Now(), time.Now()
+	cp := *rv
+	m.data[rv.ID] = &cp
+	m.refresh(rv.ProductID)
+	return nil
+}
+
+func (m *memReviewRepo) GetByID(ctx context.Context, id uint) (*models.Review, error) {
+	rv, ok := m.data[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *rv
+	return &cp, nil
+}
+
+func (m *memReviewRepo) GetByUserProduct(ctx context.Context, userID, productID uint) (*models.Review, error) {
+	for _, rv := range m.data {
+		if rv.UserID == userID && rv.ProductID == productID {
+			cp := *rv
+			return &cp, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memReviewRepo) List(ctx context.Context, f repositories.ReviewFilter) ([]models.Review, int64, error) {
+	var out []models.Review
+	for id := uint(1); id < m.next; id++ {
+		rv, ok := m.data[id]
+		if ok && (f.ProductID == 0 || rv.ProductID == f.ProductID) && (f.Status == "" || rv.Status == f.Status) {
+			out = append(out, *rv)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (m *memReviewRepo) Update(ctx context.Context, rv *models.Review) error {
+	if _, ok := m.data[rv.ID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	rv.UpdatedAt = time.Now()
+	cp := *rv
+	m.data[rv.ID] = &cp
+	m.refresh(rv.ProductID)
+	return nil
+}
+
+func (m *memReviewRepo) Moderate(ctx context.Context, rv *models.Review, seen time.Time) error {
+	current, ok := m.data[rv.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if !current.UpdatedAt.Equal(seen) {
+		return repositories.ErrReviewChanged
+	}
+	current.Status, current.ModerationNote, current.ModeratedBy, current.ModeratedAt = rv.Status, rv.ModerationNote, rv.ModeratedBy, rv.ModeratedAt
+	current.UpdatedAt = time.Now()
+	*rv = *current
+	m.refresh(rv.ProductID)
+	return nil
+}
+
+func (m *memReviewRepo) Delete(ctx context.Context, id uint) error {
+	rv, ok := m.data[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(m.data, id)
+	m.refresh(rv.ProductID)
+	return nil
+}
+
+func (m *memReviewRepo) HasPurchased(ctx context.Context, userID, productID uint) (bool, error) {
+	return m.purchased[[2]uint{userID, productID}], nil
+}
+
+// refresh рахує рейтинг продукту зі схвалених відгуків (як refreshProductRating)
+
+func (m *memReviewRepo) refresh(productID uint) {
+	sum, count := 0, 0
+	for _, rv := range m.data {
+		if rv.ProductID == productID && rv.Status == models.ReviewApproved {
+			sum += rv.Rating
+			count++
+		}
+	}
+	p := m.products.data[productID]
+	p.RatingCount, p.RatingAvg = count, 0
+	if count > 0 {
+		p.RatingAvg = math.Round(float64(sum)/float64(count)*100) / 100
+	}
+}
+
+// newReviewEnv створює продукт і сервіс відгуків з in-memory репозиторіями
+
+func newReviewEnv(t *testing.T) (services.ReviewService, *memReviewRepo, *memRepo) {
+	products := newMemRepo()
+	require.NoError(t, products.Create(context.Background(), &models.Product{Name: "Корм", PriceCents: 10000}))
+	reviews := newMemReviewRepo(products)
+	return services.NewReviewService(reviews, products), reviews, products
+}
+
+// Тест створення відгуку: підтверджена покупка, очікування модерації і один відгук на користувача
+
+func TestCreateReview(t *testing.T) {
+	ctx := context.Background()
+	svc, reviews, _ := newReviewEnv(t)
+	reviews.purchased[[2]uint{7, 1}] = true
+
+	rv, err := svc.CreateReview(ctx, 7, 1, services.ReviewInput{Rating: 5, Text: " Кіт у захваті "})
+	require.NoError(t, err)
+	assert.True(t, rv.VerifiedPurchase)
+	assert.Equal(t, models.ReviewPending, rv.Status)
+	assert.Equal(t, "Кіт у захваті", rv.Text)
+
+	_, err = svc.CreateReview(ctx, 7, 1, services.ReviewInput{Rating: 4, Text: "Ще раз"})
+	assert.ErrorIs(t, err, services.ErrReviewExists)
+
+	rv, err = svc.CreateReview(ctx, 8, 1, services.ReviewInput{Rating: 3, Text: "Нормально"})
+	require.NoError(t, err)
+	assert.False(t, rv.VerifiedPurchase)
+
+	for _, bad := range []services.ReviewInput{{Rating: 0, Text: "Текст"}, {Rating: 6, Text: "Текст"}, {Rating: 3, Text: "  "}} {
+		_, err := svc.CreateReview(ctx, 9, 1, bad)
+		assert.ErrorIs(t, err, services.ErrInvalidReview, bad)
+	}
+	_, err = svc.CreateReview(ctx, 9, 42, services.ReviewInput{Rating: 3, Text: "Текст"})
+	assert.ErrorIs(t, err, services.ErrNotFound)
+}
+
+// Тест модерації: рейтинг продукту рахується лише зі схвалених відгуків,
+// а зміна відгуку автором знімає його з публікації
+
+func TestModerateReviewRating(t *testing.T) {
+	ctx := context.Background()
+	svc, reviews, products := newReviewEnv(t)
+	seen := func(id uint) time.Time { return reviews.data[id].UpdatedAt }
+	a, err := svc.CreateReview(ctx, 7, 1, services.ReviewInput{Rating: 5, Text: "Чудово"})
+	require.NoError(t, err)
+	b, err := svc.CreateReview(ctx, 8, 1, services.ReviewInput{Rating: 2, Text: "Погано пахне"})
+	require.NoError(t, err)
+	c, err := svc.CreateReview(ctx, 9, 1, services.ReviewInput{Rating: 4, Text: "Добре"})
+	require.NoError(t, err)
+
+	_, err = svc.ModerateReview(ctx, a.ID, services.ModerationInput{Status: "deleted", UpdatedAt: seen(a.ID)}, 1)
+	assert.ErrorIs(t, err, services.ErrInvalidModeration)
+	_, err = svc.ModerateReview(ctx, a.ID, services.ModerationInput{Status: models.ReviewApproved}, 1)
+	assert.ErrorIs(t, err, services.ErrInvalidModeration)
+	_, err = svc.ModerateReview(ctx, 42, services.ModerationInput{Status: models.ReviewApproved, UpdatedAt: time.Now()}, 1)
+	assert.ErrorIs(t, err, services.ErrReviewNotFound)
+
+	for _, id := range []uint{a.ID, b.ID, c.ID} {
+		_, err := svc.ModerateReview(ctx, id, services.ModerationInput{Status: models.ReviewApproved, UpdatedAt: seen(id)}, 1)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, products.data[1].RatingCount)
+	assert.Equal(t, 3.67, products.data[1].RatingAvg)
+
+	rv, err := svc.ModerateReview(ctx, b.ID, services.ModerationInput{Status: models.ReviewRejected, Note: " без аргументів ", UpdatedAt: seen(b.ID)}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "без аргументів", rv.ModerationNote)
+	require.NotNil(t, rv.ModeratedBy)
+	assert.Equal(t, 4.5, products.data[1].RatingAvg)
+
+	items, total, err := svc.ListProductReviews(ctx, 1, repositories.ReviewFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, items, 2)
+
+	rv, err = svc.UpdateMyReview(ctx, 9, 1, services.ReviewInput{Rating: 1, Text: "Передумав"})
+	require.NoError(t, err)
+	assert.Equal(t, models.ReviewPending, rv.Status)
+	assert.Nil(t, rv.ModeratedBy)
+	assert.Equal(t, 1, products.data[1].RatingCount)
+	assert.Equal(t, 5.0, products.data[1].RatingAvg)
+
+	require.NoError(t, svc.DeleteMyReview(ctx, 7, 1))
+	assert.Equal(t, 0, products.data[1].RatingCount)
+	assert.Equal(t, 0.0, products.data[1].RatingAvg)
+	assert.ErrorIs(t, svc.DeleteMyReview(ctx, 7, 1), services.ErrReviewNotFound)
+}
+
+// Тест: автор змінив відгук, поки модератор його переглядав — рішення не застосовується (409),
+// а нова версія залишається в очікуванні модерації
+
+func TestModerateReviewChanged(t *testing.T) {
+	ctx := context.Background()
+	svc, reviews, products := newReviewEnv(t)
+	rv, err := svc.CreateReview(ctx, 7, 1, services.ReviewInput{Rating: 5, Text: "Чудово"})
+	require.NoError(t, err)
+	seen := reviews.data[rv.ID].UpdatedAt
+
+	time.Sleep(time.Millisecond)
+	_, err = svc.UpdateMyReview(ctx, 7, 1, services.ReviewInput{Rating: 1, Text: "Кіт отруївся"})
+	require.NoError(t, err)
+
+	_, err = svc.ModerateReview(ctx, rv.ID, services.ModerationInput{Status: models.ReviewApproved, UpdatedAt: seen}, 1)
+	assert.ErrorIs(t, err, services.ErrReviewModified)
+	assert.Equal(t, models.ReviewPending, reviews.data[rv.ID].Status)
+	assert.Equal(t, "Кіт отруївся", reviews.data[rv.ID].Text)
+	assert.Equal(t, 0, products.data[1].RatingCount)
+}
+
+// Тест фільтрів списку відгуків для модерації
+
+func TestListReviewsInvalidFilter(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newReviewEnv(t)
+
+	_, _, err := svc.ListReviews(ctx, repositories.ReviewFilter{Sort: "stars"})
+	assert.ErrorIs(t, err, services.ErrInvalidFilter)
+	_, _, err = svc.ListReviews(ctx, repositories.ReviewFilter{Status: "spam"})
+	assert.ErrorIs(t, err, services.ErrInvalidFilter)
+	_, _, err = svc.ListReviews(ctx, repositories.ReviewFilter{Status: models.ReviewPending, Sort: "-rating"})
+	assert.NoError(t, err)
+}
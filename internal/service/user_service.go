@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/pagination"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Помилки керування користувачами
+
+var (
+	ErrUserNotFound      = apperr.NotFound("user_not_found", "user not found")                       // користувача не знайдено
+	ErrUserTaken         = apperr.Conflict("user_already_exists", "username or email already taken") // username або email зайнятий іншим користувачем
+	ErrIncorrectPassword = apperr.Unauthorized("incorrect_password", "incorrect old password")       // старий пароль не збігається
+)
+
+// maxUserPage — скільки користувачів повертає одна сторінка адмінського списку
+
+const maxUserPage = 100
+
+// UserService визначає бізнес-логіку профілю користувача та адміністрування користувачів
+
+type UserService interface {
+	GetProfile(ctx context.Context, id uint) (*models.User, error)                                                            // ErrUserNotFound якщо не знайдено
+	UpdateProfile(ctx context.Context, id uint, username, email string) (*models.User, error)                                 // ErrUserNotFound, ErrUserTaken
+	ChangePassword(ctx context.Context, id uint, oldPassword, newPassword string) error                                       // відкликає всі refresh токени; ErrUserNotFound, ErrIncorrectPassword
+	List(ctx context.Context) ([]models.User, error)                                                                          // усі користувачі
+	ListByCursor(ctx context.Context, c *pagination.Cursor, limit int, withTotal bool) (*pagination.Page[models.User], error) // сторінка користувачів за id
+	Delete(ctx context.Context, id uint) error                                                                                // ErrUserNotFound якщо не знайдено
+}
+
+// userService реалізує UserService
+
+type userService struct {
+	repo   repositories.UserRepository
+	tokens repositories.TokenRepository
+}
+
+// NewUserService створює новий UserService
+
+func NewUserService(r repositories.UserRepository, tokens repositories.TokenRepository) UserService {
+	return &userService{repo: r, tokens: tokens}
+}
+
+// GetProfile повертає користувача за ID
+
+func (s *userService) GetProfile(ctx context.Context, id uint) (*models.User, error) {
+	u, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, translateUserError(err)
+	}
+	return u, nil
+}
+
+// UpdateProfile змінює username і email користувача
+
+func (s *userService) UpdateProfile(ctx context.Context, id uint, username, email string) (*models.User, error) {
+	u, err := s.repo.UpdateProfile(id, username, email)
+	if err != nil {
+		return nil, translateUserError(err)
+	}
+	return u, nil
+}
+
+// ChangePassword перевіряє старий пароль, зберігає хеш нового і відкликає всі refresh токени користувача —
+// викрадений токен після зміни пароля не працює (access токени діють до закінчення свого короткого TTL)
+
+func (s *userService) ChangePassword(ctx context.Context, id uint, oldPassword, newPassword string) error {
+	u, err := s.repo.GetByID(id)
+	if err != nil {
+		return translateUserError(err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(oldPassword)); err != nil {
+		return ErrIncorrectPassword
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePassword(id, string(hashed)); err != nil {
+		return err
+	}
+	return s.tokens.RevokeAllRefreshTokens(ctx, id)
+}
+
+// List повертає всіх користувачів
+
+func (s *userService) List(ctx context.Context) ([]models.User, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// ListByCursor повертає сторінку користувачів за id (не більше maxUserPage)
+
+func (s *userService) ListByCursor(ctx context.Context, c *pagination.Cursor, limit int, withTotal bool) (*pagination.Page[models.User], error) {
+	if limit <= 0 || limit > maxUserPage {
+		limit = maxUserPage
+	}
+	return s.repo.ListByCursor(ctx, c, limit, withTotal)
+}
+
+// Delete видаляє користувача за ID
+
+func (s *userService) Delete(ctx context.Context, id uint) error {
+	return translateUserError(s.repo.Delete(ctx, id))
+}
+
+// translateUserError переводить помилки GORM з репозиторію користувачів у помилки домену
+
+func translateUserError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrUserTaken
+	}
+	return err
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Помилки сервісу варіантів продуктів
+
+var (
+	ErrVariantNotFound         = apperr.NotFound("variant_not_found", "product variant not found")                                // варіанту немає в цього продукту
+	ErrInvalidVariant          = apperr.Validation("invalid_variant", "invalid product variant")                                  // артикул, атрибути або залишок некоректні
+	ErrDuplicateVariantSKU     = apperr.Conflict("duplicate_variant_sku", "variant with this sku already exists")                 // артикул вже використовується іншим варіантом
+	ErrDuplicateVariantOptions = apperr.Conflict("duplicate_variant_options", "product already has a variant with these options") // два варіанти продукту з однаковими атрибутами
+)
+
+// Обмеження атрибутів варіанту
+
+const (
+	maxVariantOptions     = 10  // атрибутів на варіант
+	maxVariantOptionName  = 50  // символів у назві атрибута
+	maxVariantOptionValue = 100 // символів у значенні атрибута
+)
+
+// VariantService визначає бізнес-логіку варіантів продукту (розмір, вага, смак)
+
+type VariantService interface {
+	ListVariants(ctx context.Context, productID uint) ([]models.ProductVariant, error)           // ErrNotFound якщо продукту немає
+	CreateVariant(ctx context.Context, v *models.ProductVariant) (*models.ProductVariant, error) // ErrInvalidPrice якщо ціна <= 0; ErrDuplicateVariantSKU, ErrDuplicateVariantOptions
+	UpdateVariant(ctx context.Context, v *models.ProductVariant) (*models.ProductVariant, error) // ErrVariantNotFound якщо варіанту немає в продукту v.ProductID
+	DeleteVariant(ctx context.Context, productID, id uint) error                                 // ErrVariantNotFound якщо варіанту немає
+}
+
+// variantService реалізує VariantService
+
+type variantService struct {
+	repo     repositories.VariantRepository
+	products repositories.ProductRepository
+}
+
+// NewVariantService створює новий VariantService
+
+func NewVariantService(r repositories.VariantRepository, products repositories.ProductRepository) VariantService {
+	return &variantService{repo: r, products: products}
+}
+
+// ListVariants повертає варіанти продукту
+
+func (s *variantService) ListVariants(ctx context.Context, productID uint) ([]models.ProductVariant, error) {
+	p, err := s.products.GetByID(ctx, productID)
+	if err != nil {
+		return nil, translateProductError(err)
+	}
+	if p == nil {
+		return nil, ErrNotFound
+	}
+	return s.repo.ListByProduct(ctx, productID)
+}
+
+// CreateVariant перевіряє варіант (ціна > 0, атрибути, унікальність атрибутів у продукті) і додає його
+
+func (s *variantService) CreateVariant(ctx context.Context, v *models.ProductVariant) (*models.ProductVariant, error) {
+	if err := validateVariant(v); err != nil {
+		return nil, err
+	}
+	existing, err := s.ListVariants(ctx, v.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVariantOptions(existing, v); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, v); err != nil {
+		return nil, translateVariantError(err, ErrNotFound)
+	}
+	return v, nil
+}
+
+// UpdateVariant змінює артикул, атрибути, ціну і залишок варіанту
+
+func (s *variantService) UpdateVariant(ctx context.Context, v *models.ProductVariant) (*models.ProductVariant, error) {
+	if err := validateVariant(v); err != nil {
+		return nil, err
+	}
+	current, err := s.repo.GetByID(ctx, v.ProductID, v.ID)
+	if err != nil {
+		return nil, translateVariantError(err, ErrVariantNotFound)
+	}
+	siblings, err := s.repo.ListByProduct(ctx, v.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVariantOptions(siblings, v); err != nil {
+		return nil, err
+	}
+	v.CreatedAt = current.CreatedAt
+	if err := s.repo.Update(ctx, v); err != nil {
+		return nil, translateVariantError(err, ErrVariantNotFound)
+	}
+	return v, nil
+}
+
+// DeleteVariant видаляє варіант продукту
+
+func (s *variantService) DeleteVariant(ctx context.Context, productID, id uint) error {
+	return translateVariantError(s.repo.Delete(ctx, productID, id), ErrVariantNotFound)
+}
+
+// validateVariant нормалізує і перевіряє поля варіанту: артикул обов'язковий, ціна > 0, залишок >= 0
+
+func validateVariant(v *models.ProductVariant) error {
+	v.SKU = strings.TrimSpace(v.SKU)
+	if v.SKU == "" || utf8.RuneCountInString(v.SKU) > 100 {
+		return ErrInvalidVariant.WithDetail("sku is required and must be at most 100 characters")
+	}
+	if v.PriceCents <= 0 {
+		return ErrInvalidPrice.WithDetail(fmt.Sprintf("variant %s: price must be > 0", v.SKU))
+	}
+	if v.Stock < 0 {
+		return ErrInvalidVariant.WithDetail("stock must be >= 0")
+	}
+
+	if len(v.Options) > maxVariantOptions {
+		return ErrInvalidVariant.WithDetail(fmt.Sprintf("at most %d options", maxVariantOptions))
+	}
+	opts := make(models.VariantOptions, len(v.Options))
+	for name, value := range v.Options {
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if name == "" || utf8.RuneCountInString(name) > maxVariantOptionName {
+			return ErrInvalidVariant.WithDetail(fmt.Sprintf("option name must be 1-%d characters", maxVariantOptionName))
+		}
+		if value == "" || utf8.RuneCountInString(value) > maxVariantOptionValue {
+			return ErrInvalidVariant.WithDetail(fmt.Sprintf("option %q: value must be 1-%d characters", name, maxVariantOptionValue))
+		}
+		opts[name] = value
+	}
+	v.Options = opts
+	return nil
+}
+
+// validateVariants перевіряє варіанти нового продукту: кожен окремо і що артикули та атрибути не повторюються
+
+func validateVariants(variants []models.ProductVariant) error {
+	skus := make(map[string]bool, len(variants))
+	for i := range variants {
+		v := &variants[i]
+		if err := validateVariant(v); err != nil {
+			return err
+		}
+		if skus[v.SKU] {
+			return ErrDuplicateVariantSKU.WithDetail(v.SKU)
+		}
+		skus[v.SKU] = true
+		if err := checkVariantOptions(variants[:i], v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkVariantOptions перевіряє, що серед інших варіантів продукту немає варіанту з тими ж атрибутами
+
+func checkVariantOptions(others []models.ProductVariant, v *models.ProductVariant) error {
+	for _, o := range others {
+		if o.ID != 0 && o.ID == v.ID {
+			continue
+		}
+		if sameOptions(o.Options, v.Options) {
+			return ErrDuplicateVariantOptions.WithDetail(fmt.Sprintf("variant %s has the same options", o.SKU))
+		}
+	}
+	return nil
+}
+
+// sameOptions порівнює атрибути двох варіантів
+
+func sameOptions(a, b models.VariantOptions) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// translateVariantError переводить помилки GORM у помилки домену варіантів;
+// notFound — що означає відсутній запис (продукт при створенні, варіант при зміні)
+
+func translateVariantError(err error, notFound error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return notFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicateVariantSKU
+	}
+	return err
+}
@@ -0,0 +1,153 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// Простий in-memory repo реалізує repositories.VariantRepository для тестів.
+
+type memVariantRepo struct {
+	products *memRepo
+	data     map[uint]*models.ProductVariant
+	next     uint
+}
+
+func newMemVariantRepo(products *memRepo) *memVariantRepo {
+	return &memVariantRepo{products: products, data: map[uint]*models.ProductVariant{}, next: 1}
+}
+
+func (m *memVariantRepo) ListByProduct(ctx context.Context, productID uint) ([]models.ProductVariant, error) {
+	var items []models.ProductVariant
+	for id := uint(1); id < m.next; id++ {
+		if v, ok := m.data[id]; ok && v.ProductID == productID {
+			items = append(items, *v)
+		}
+	}
+	return items, nil
+}
+
+func (m *memVariantRepo) GetByID(ctx context.Context, productID, id uint) (*models.ProductVariant, error) {
+	v, ok := m.data[id]
+	if !ok || v.ProductID != productID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *v
+	return &cp, nil
+}
+
+// Create перевіряє продукт і унікальність артикулу (як FK і унікальний індекс у БД)
+
+func (m *memVariantRepo) Create(ctx context.Context, v *models.ProductVariant) error {
+	if _, ok := m.products.data[v.ProductID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	for _, other := range m.data {
+		if other.SKU == v.SKU {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	v.ID = m.next
+	m.next++
+	cp := *v
+	m.data[v.ID] = &cp
+	return nil
+}
+
+func (m *memVariantRepo) Update(ctx context.Context, v *models.ProductVariant) error {
+	for _, other := range m.data {
+		if other.SKU == v.SKU && other.ID != v.ID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	cp := *v
+	m.data[v.ID] = &cp
+	return nil
+}
+
+func (m *memVariantRepo) Delete(ctx context.Context, productID, id uint) error {
+	if v, ok := m.data[id]; !ok || v.ProductID != productID {
+		return gorm.ErrRecordNotFound
+	}
+	delete(m.data, id)
+	return nil
+}
+
+// Тест CRUD варіантів: ціна > 0 для кожного варіанту, унікальні артикул і атрибути
+
+func TestProductVariants(t *testing.T) {
+	products := newMemRepo()
+	svc := services.NewVariantService(newMemVariantRepo(products), products)
+	ctx := context.Background()
+	require.NoError(t, products.Create(ctx, &models.Product{Name: "Корм для собак", PriceCents: 50000}))
+
+	small, err := svc.CreateVariant(ctx, &models.ProductVariant{
+		ProductID: 1, SKU: " DOG-2KG ", Options: models.VariantOptions{"weight": " 2 kg "}, PriceCents: 50000, Stock: 4,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "DOG-2KG", small.SKU)
+	assert.Equal(t, "2 kg", small.Options["weight"])
+
+	_, err = svc.CreateVariant(ctx, &models.ProductVariant{ProductID: 1, SKU: "DOG-10KG", Options: models.VariantOptions{"weight": "10 kg"}})
+	assert.ErrorIs(t, err, services.ErrInvalidPrice)
+	_, err = svc.CreateVariant(ctx, &models.ProductVariant{ProductID: 1, SKU: "DOG-2KG-B", Options: models.VariantOptions{"weight": "2 kg"}, PriceCents: 1})
+	assert.ErrorIs(t, err, services.ErrDuplicateVariantOptions)
+	_, err = svc.CreateVariant(ctx, &models.ProductVariant{ProductID: 1, SKU: "DOG-2KG", Options: models.VariantOptions{"weight": "5 kg"}, PriceCents: 1})
+	assert.ErrorIs(t, err, services.ErrDuplicateVariantSKU)
+	_, err = svc.CreateVariant(ctx, &models.ProductVariant{ProductID: 42, SKU: "X", PriceCents: 1})
+	assert.ErrorIs(t, err, services.ErrNotFound)
+
+	big, err := svc.CreateVariant(ctx, &models.ProductVariant{ProductID: 1, SKU: "DOG-10KG", Options: models.VariantOptions{"weight": "10 kg"}, PriceCents: 200000})
+	require.NoError(t, err)
+
+	_, err = svc.UpdateVariant(ctx, &models.ProductVariant{ID: big.ID, ProductID: 1, SKU: "DOG-10KG", Options: models.VariantOptions{"weight": "10 kg"}, PriceCents: 0})
+	assert.ErrorIs(t, err, services.ErrInvalidPrice)
+	updated, err := svc.UpdateVariant(ctx, &models.ProductVariant{ID: big.ID, ProductID: 1, SKU: "DOG-10KG", Options: models.VariantOptions{"weight": "10 kg"}, PriceCents: 180000, Stock: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(180000), updated.PriceCents)
+	_, err = svc.UpdateVariant(ctx, &models.ProductVariant{ID: big.ID, ProductID: 2, SKU: "DOG-10KG", PriceCents: 1})
+	assert.ErrorIs(t, err, services.ErrVariantNotFound)
+
+	items, err := svc.ListVariants(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	require.NoError(t, svc.DeleteVariant(ctx, 1, small.ID))
+	assert.ErrorIs(t, svc.DeleteVariant(ctx, 1, small.ID), services.ErrVariantNotFound)
+}
+
+// Тест створення продукту з варіантами: кожен варіант валідується, повтори в запиті відхиляються
+
+func TestCreateProductWithVariants(t *testing.T) {
+	svc := services.NewProductService(newMemRepo(), newMemCategoryRepo())
+	ctx := context.Background()
+
+	_, err := svc.CreateProduct(ctx, &models.Product{Name: "Нашийник", PriceCents: 3000, Variants: []models.ProductVariant{
+		{SKU: "COL-S", Options: models.VariantOptions{"size": "S"}, PriceCents: 3000},
+		{SKU: "COL-M", Options: models.VariantOptions{"size": "M"}, PriceCents: -1},
+	}})
+	assert.ErrorIs(t, err, services.ErrInvalidPrice)
+
+	_, err = svc.CreateProduct(ctx, &models.Product{Name: "Нашийник", PriceCents: 3000, Variants: []models.ProductVariant{
+		{SKU: "COL-S", Options: models.VariantOptions{"size": "S"}, PriceCents: 3000},
+		{SKU: "COL-S", Options: models.VariantOptions{"size": "M"}, PriceCents: 3200},
+	}})
+	assert.ErrorIs(t, err, services.ErrDuplicateVariantSKU)
+
+	p, err := svc.CreateProduct(ctx, &models.Product{Name: "Нашийник", PriceCents: 3000, Variants: []models.ProductVariant{
+		{SKU: "COL-S", Options: models.VariantOptions{"size": "S"}, PriceCents: 3000},
+		{SKU: "COL-M", Options: models.VariantOptions{"size": "M"}, PriceCents: 3200},
+	}})
+	require.NoError(t, err)
+	assert.Len(t, p.Variants, 2)
+
+	_, err = svc.PatchProduct(ctx, p.ID, []byte(`{"variants": []}`), 0)
+	assert.ErrorIs(t, err, services.ErrInvalidPatch)
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/apperr"
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Помилки сервісу складських локацій
+
+var (
+	ErrWarehouseNotFound      = apperr.NotFound("warehouse_not_found", "warehouse not found")                               // локацію не знайдено
+	ErrInvalidWarehouse       = apperr.Validation("invalid_warehouse", "invalid warehouse")                                 // код, назва, тип або пріоритет некоректні
+	ErrDuplicateWarehouseCode = apperr.Conflict("duplicate_warehouse_code", "warehouse with this code already exists")      // код вже використовується іншою локацією
+	ErrWarehouseNotEmpty      = apperr.Conflict("warehouse_not_empty", "warehouse still has stock")                         // перед деактивацією товар потрібно перемістити
+	ErrWarehouseInactive      = apperr.Conflict("warehouse_inactive", "warehouse is inactive")                              // неактивна локація не приймає товар
+	ErrNoActiveWarehouse      = apperr.Conflict("no_active_warehouse", "there is no active warehouse to receive stock")     // усі локації деактивовано
+	ErrLastActiveWarehouse    = apperr.Conflict("last_active_warehouse", "the only active warehouse cannot be deactivated") // має лишатися хоча б одна активна локація
+)
+
+// WarehouseService визначає бізнес-логіку складських локацій (склад, магазини)
+
+type WarehouseService interface {
+	ListWarehouses(ctx context.Context, activeOnly bool) ([]models.Warehouse, error)     // локації за пріоритетом
+	GetWarehouse(ctx context.Context, id uint) (*models.Warehouse, error)                // ErrWarehouseNotFound якщо не знайдено
+	CreateWarehouse(ctx context.Context, w *models.Warehouse) (*models.Warehouse, error) // ErrInvalidWarehouse, ErrDuplicateWarehouseCode
+	UpdateWarehouse(ctx context.Context, w *models.Warehouse) (*models.Warehouse, error) // ErrWarehouseNotEmpty, ErrLastActiveWarehouse при деактивації
+}
+
+// warehouseService реалізує WarehouseService
+
+type warehouseService struct {
+	repo repositories.WarehouseRepository
+}
+
+// NewWarehouseService створює новий WarehouseService
+
+func NewWarehouseService(r repositories.WarehouseRepository) WarehouseService {
+	return &warehouseService{repo: r}
+}
+
+// ListWarehouses повертає локації (activeOnly — лише активні)
+
+func (s *warehouseService) ListWarehouses(ctx context.Context, activeOnly bool) ([]models.Warehouse, error) {
+	return s.repo.List(ctx, activeOnly)
+}
+
+// GetWarehouse повертає локацію за ID
+
+func (s *warehouseService) GetWarehouse(ctx context.Context, id uint) (*models.Warehouse, error) {
+	w, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, translateWarehouseError(err)
+	}
+	return w, nil
+}
+
+// CreateWarehouse перевіряє і додає нову локацію
+
+func (s *warehouseService) CreateWarehouse(ctx context.Context, w *models.Warehouse) (*models.Warehouse, error) {
+	if err := validateWarehouse(w); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, w); err != nil {
+		return nil, translateWarehouseError(err)
+	}
+	return w, nil
+}
+
+// UpdateWarehouse змінює локацію. Деактивувати можна лише порожню локацію і не останню активну —
+// так товар не «застрягає» там, де його не можна продати, а новим надходженням є куди потрапити.
+
+func (s *warehouseService) UpdateWarehouse(ctx context.Context, w *models.Warehouse) (*models.Warehouse, error) {
+	existing, err := s.GetWarehouse(ctx, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWarehouse(w); err != nil {
+		return nil, err
+	}
+	if existing.Active && !w.Active {
+		total, err := s.repo.StockTotal(ctx, w.ID)
+		if err != nil {
+			return nil, err
+		}
+		if total > 0 {
+			return nil, ErrWarehouseNotEmpty
+		}
+		active, err := s.repo.List(ctx, true)
+		if err != nil {
+			return nil, err
+		}
+		if len(active) <= 1 {
+			return nil, ErrLastActiveWarehouse
+		}
+	}
+	w.CreatedAt = existing.CreatedAt
+	if err := s.repo.Update(ctx, w); err != nil {
+		return nil, translateWarehouseError(err)
+	}
+	return w, nil
+}
+
+// validateWarehouse нормалізує і перевіряє поля локації (код — у форматі slug)
+
+func validateWarehouse(w *models.Warehouse) error {
+	w.Code = strings.ToLower(strings.TrimSpace(w.Code))
+	w.Name = strings.TrimSpace(w.Name)
+	w.Address = strings.TrimSpace(w.Address)
+	if w.Kind == "" {
+		w.Kind = models.WarehouseKindWarehouse
+	}
+	switch {
+	case len(w.Code) < 2 || len(w.Code) > 50 || !slugPattern.MatchString(w.Code):
+		return ErrInvalidWarehouse.WithDetail("code must be 2-50 lowercase latin letters, digits and hyphens")
+	case utf8.RuneCountInString(w.Name) < 2 || utf8.RuneCountInString(w.Name) > 100:
+		return ErrInvalidWarehouse.WithDetail("name must be 2-100 characters")
+	case w.Kind != models.WarehouseKindWarehouse && w.Kind != models.WarehouseKindStore:
+		return ErrInvalidWarehouse.WithDetail("kind must be warehouse or store")
+	case utf8.RuneCountInString(w.Address) > 255:
+		return ErrInvalidWarehouse.WithDetail("address must be at most 255 characters")
+	case w.Priority < 0:
+		return ErrInvalidWarehouse.WithDetail("priority must be >= 0")
+	}
+	return nil
+}
+
+// activeWarehouse повертає локацію, якщо вона існує і активна
+
+func activeWarehouse(ctx context.Context, repo repositories.WarehouseRepository, id uint) (*models.Warehouse, error) {
+	w, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, translateWarehouseError(err)
+	}
+	if !w.Active {
+		return nil, ErrWarehouseInactive.WithDetail(w.Code)
+	}
+	return w, nil
+}
+
+// translateWarehouseError перетворює помилки БД на помилки сервісу локацій
+
+func translateWarehouseError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrWarehouseNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicateWarehouseCode
+	case errors.Is(err, repositories.ErrNoWarehouse):
+		return ErrNoActiveWarehouse
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/models"
+	"github.com/AlexRijikov/go-petshop-api/internal/service"
+)
+
+// memWarehouseRepo — in-memory локації; основна локація "main" (ID 1) створюється одразу, як і міграцією.
+// stock — загальна кількість товару в локації для StockTotal
+
+type memWarehouseRepo struct {
+	data  map[uint]*models.Warehouse
+	stock map[uint]int64
+}
+
+func newMemWarehouseRepo() *memWarehouseRepo {
+	return &memWarehouseRepo{
+		data:  map[uint]*models.Warehouse{1: {ID: 1, Code: "main", Name: "Основний склад", Kind: models.WarehouseKindWarehouse, Active: true}},
+		stock: map[uint]int64{},
+	}
+}
+
+func (m *memWarehouseRepo) List(ctx context.Context, activeOnly bool) ([]models.Warehouse, error) {
+	var out []models.Warehouse
+	for _, w := range m.data {
+		if !activeOnly || w.Active {
+			out = append(out, *w)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Priority < out[j].Priority })
+	return out, nil
+}
+
+func (m *memWarehouseRepo) GetByID(ctx context.Context, id uint) (*models.Warehouse, error) {
+	w, ok := m.data[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *w
+	return &cp, nil
+}
+
+func (m *memWarehouseRepo) Create(ctx context.Context, w *models.Warehouse) error {
+	for _, other := range m.data {
+		if other.Code == w.Code {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	w.ID = uint(len(m.data) + 1)
+	cp := *w
+	m.data[w.ID] = &cp
+	return nil
+}
+
+func (m *memWarehouseRepo) Update(ctx context.Context, w *models.Warehouse) error {
+	for _, other := range m.data {
+		if other.Code == w.Code && other.ID != w.ID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	cp := *w
+	m.data[w.ID] = &cp
+	return nil
+}
+
+func (m *memWarehouseRepo) StockTotal(ctx context.Context, id uint) (int64, error) {
+	return m.stock[id], nil
+}
+
+// Тест створення локації: нормалізація коду, тип за замовчуванням і унікальний код
+
+func TestCreateWarehouse(t *testing.T) {
+	ctx := context.Background()
+	svc := services.NewWarehouseService(newMemWarehouseRepo())
+
+	w, err := svc.CreateWarehouse(ctx, &models.Warehouse{Code: " Shop-Podil ", Name: "Магазин на Подолі", Priority: 10, Active: true})
+	require.NoError(t, err)
+	assert.Equal(t, "shop-podil", w.Code)
+	assert.Equal(t, models.WarehouseKindWarehouse, w.Kind)
+
+	_, err = svc.CreateWarehouse(ctx, &models.Warehouse{Code: "shop-podil", Name: "Дубль", Active: true})
+	assert.ErrorIs(t, err, services.ErrDuplicateWarehouseCode)
+
+	for _, bad := range []models.Warehouse{
+		{Code: "склад", Name: "Склад"},
+		{Code: "ok", Name: "С"},
+		{Code: "ok", Name: "Склад", Kind: "truck"},
+		{Code: "ok", Name: "Склад", Priority: -1},
+	} {
+		_, err := svc.CreateWarehouse(ctx, &bad)
+		assert.ErrorIs(t, err, services.ErrInvalidWarehouse, bad)
+	}
+
+	_, err = svc.GetWarehouse(ctx, 42)
+	assert.ErrorIs(t, err, services.ErrWarehouseNotFound)
+}
+
+// Тест деактивації: лише порожня локація і не остання активна
+
+func TestDeactivateWarehouse(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemWarehouseRepo()
+	svc := services.NewWarehouseService(repo)
+
+	_, err := svc.UpdateWarehouse(ctx, &models.Warehouse{ID: 1, Code: "main", Name: "Основний склад", Active: false})
+	assert.ErrorIs(t, err, services.ErrLastActiveWarehouse)
+
+	_, err = svc.CreateWarehouse(ctx, &models.Warehouse{Code: "shop", Name: "Магазин", Kind: models.WarehouseKindStore, Active: true})
+	require.NoError(t, err)
+	repo.stock[2] = 3
+	_, err = svc.UpdateWarehouse(ctx, &models.Warehouse{ID: 2, Code: "shop", Name: "Магазин", Kind: models.WarehouseKindStore, Active: false})
+	assert.ErrorIs(t, err, services.ErrWarehouseNotEmpty)
+
+	repo.stock[2] = 0
+	w, err := svc.UpdateWarehouse(ctx, &models.Warehouse{ID: 2, Code: "shop", Name: "Магазин", Kind: models.WarehouseKindStore, Active: false})
+	require.NoError(t, err)
+	assert.False(t, w.Active)
+
+	active, err := svc.ListWarehouses(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, "main", active[0].Code)
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local зберігає файли в каталозі на диску; роздає їх сам HTTP-сервер за префіксом baseURL
+
+type Local struct {
+	dir     string // кореневий каталог сховища
+	baseURL string // префікс публічних URL без "/" у кінці (/media або https://cdn.example.com)
+}
+
+// NewLocal створює Local і каталог dir, якщо його ще немає
+
+func NewLocal(dir, baseURL string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create storage dir: %w", err)
+	}
+	return &Local{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+// Dir повертає кореневий каталог сховища (для роздачі файлів HTTP-сервером)
+
+func (l *Local) Dir() string {
+	return l.dir
+}
+
+// Put записує файл у тимчасовий файл поруч і перейменовує його — читачі не бачать частково записаний файл
+
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+	dst := filepath.Join(l.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // після Rename файлу вже немає — помилку ігноруємо
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// Delete видаляє файл; порожні каталоги не прибираються
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+	err := os.Remove(filepath.Join(l.dir, filepath.FromSlash(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// URL повертає публічний URL файлу
+
+func (l *Local) URL(key string) string {
+	return l.baseURL + "/" + key
+}
@@ -0,0 +1,44 @@
+package storage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/storage"
+)
+
+// Тест локального сховища: запис, перезапис, URL, видалення і захист від виходу за межі каталогу
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	s, err := storage.NewLocal(dir, "/media/")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "products/1/a/original.png", strings.NewReader("first"), "image/png"))
+	require.NoError(t, s.Put(ctx, "products/1/a/original.png", strings.NewReader("second"), "image/png"))
+	data, err := os.ReadFile(filepath.Join(dir, "products", "1", "a", "original.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+	assert.Equal(t, "/media/products/1/a/original.png", s.URL("products/1/a/original.png"))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "products", "1", "a"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1) // тимчасові файли не залишаються
+
+	require.NoError(t, s.Delete(ctx, "products/1/a/original.png"))
+	require.NoError(t, s.Delete(ctx, "products/1/a/original.png"))
+	_, err = os.Stat(filepath.Join(dir, "products", "1", "a", "original.png"))
+	assert.True(t, os.IsNotExist(err))
+
+	for _, key := range []string{"", "../secret", "/etc/passwd", "products/../../x", "products//x", ".."} {
+		assert.ErrorIs(t, s.Put(ctx, key, strings.NewReader("x"), "text/plain"), storage.ErrInvalidKey, key)
+		assert.ErrorIs(t, s.Delete(ctx, key), storage.ErrInvalidKey, key)
+	}
+}
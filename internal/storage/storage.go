@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/AlexRijikov/go-petshop-api/internal/config"
+)
+
+// Сховище завантажених файлів (зображення продуктів). Файли адресуються ключем —
+// відносним шляхом через "/" (products/12/3f9a.../original.png); сервіси не знають, де саме лежать файли.
+// Реалізації: Local (каталог на диску); S3-сумісне сховище додається новою реалізацією Storage.
+
+// ErrInvalidKey — ключ порожній, абсолютний або виходить за межі сховища (..)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage визначає операції зі сховищем файлів
+
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error // зберігає файл (перезаписує існуючий)
+	Delete(ctx context.Context, key string) error                               // видаляє файл; відсутній файл — не помилка
+	URL(key string) string                                                      // публічний URL файлу
+}
+
+// New створює сховище за конфігурацією (STORAGE_DRIVER)
+
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "local":
+		return NewLocal(cfg.LocalDir, cfg.PublicURL)
+	}
+	return nil, fmt.Errorf("unsupported storage driver %q", cfg.Driver)
+}
+
+// validKey перевіряє, що ключ — нормалізований відносний шлях без виходу за межі сховища
+
+func validKey(key string) bool {
+	return key != "" && !strings.HasPrefix(key, "/") && path.Clean(key) == key &&
+		key != ".." && !strings.HasPrefix(key, "../")
+}